@@ -0,0 +1,131 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025-present Datadog, Inc.
+
+package attributes
+
+import (
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	semconv16 "go.opentelemetry.io/otel/semconv/v1.6.1"
+
+	"github.com/DataDog/datadog-agent/pkg/opentelemetry-mapping-go/otlp/attributes/azure"
+	"github.com/DataDog/datadog-agent/pkg/opentelemetry-mapping-go/otlp/attributes/ec2"
+	"github.com/DataDog/datadog-agent/pkg/opentelemetry-mapping-go/otlp/attributes/gcp"
+)
+
+// HostnameResolver is a strategy to get a hostname from resource attributes.
+//
+// It returns the hostname and true if it found one. Otherwise, the next resolver
+// in the chain is tried, unless stop is true. Resolvers return stop when the attributes
+// are known to have no hostname (e.g. on AWS ECS Fargate).
+type HostnameResolver func(attrs pcommon.Map) (hostname string, ok bool, stop bool)
+
+var (
+	// LiteralHostResolver uses the literal "host" attribute, to avoid double tagging.
+	LiteralHostResolver HostnameResolver = literalHostResolver
+	// DatadogHostnameResolver uses the custom Datadog hostname provided by the "datadog.host.name" attribute.
+	DatadogHostnameResolver HostnameResolver = AttributeHostnameResolver(AttributeDatadogHostname)
+	// ECSFargateResolver stops the resolution on AWS ECS Fargate, which has no hostname.
+	ECSFargateResolver HostnameResolver = ecsFargateResolver
+	// CloudProviderResolver uses the cloud provider specific hostname for AWS, Azure or GCP.
+	// It stops the resolution when the cloud provider is known, even if no hostname is found.
+	CloudProviderResolver HostnameResolver = cloudProviderResolver
+	// KubernetesNodeResolver uses the Kubernetes node name, and the cluster name if available.
+	KubernetesNodeResolver HostnameResolver = kubernetesNodeResolver
+	// HostIDResolver uses the "host.id" attribute.
+	HostIDResolver HostnameResolver = AttributeHostnameResolver(string(semconv16.HostIDKey))
+	// HostNameResolver uses the "host.name" attribute.
+	HostNameResolver HostnameResolver = AttributeHostnameResolver(string(semconv16.HostNameKey))
+)
+
+// DefaultHostnameResolvers returns the default hostname resolution chain:
+//
+//  1. the "host" attribute to avoid double tagging if present,
+//
+//  2. a custom Datadog hostname provided by the "datadog.host.name" attribute,
+//
+//  3. no hostname on AWS ECS Fargate,
+//
+//  4. cloud provider specific hostname for AWS, Azure or GCP,
+//
+//  5. the Kubernetes node name (and cluster name if available),
+//
+//  6. the cloud provider host ID and
+//
+//  7. the host.name attribute.
+func DefaultHostnameResolvers() []HostnameResolver {
+	return []HostnameResolver{
+		LiteralHostResolver,
+		DatadogHostnameResolver,
+		ECSFargateResolver,
+		CloudProviderResolver,
+		KubernetesNodeResolver,
+		HostIDResolver,
+		HostNameResolver,
+	}
+}
+
+// defaultHostnameResolvers is the chain used by SourceFromAttrs and by default by a Translator.
+var defaultHostnameResolvers = DefaultHostnameResolvers()
+
+// AttributeHostnameResolver returns a HostnameResolver that uses the string value of the given attribute.
+func AttributeHostnameResolver(key string) HostnameResolver {
+	return func(attrs pcommon.Map) (string, bool, bool) {
+		if hostname, ok := attrs.Get(key); ok {
+			return hostname.Str(), true, false
+		}
+		return "", false, false
+	}
+}
+
+func literalHostResolver(attrs pcommon.Map) (string, bool, bool) {
+	if literalHost, ok := attrs.Get(AttributeHost); ok {
+		// Use even if not a string, so that we avoid double tagging if
+		// `resource_attributes_as_tags` is true and 'host' has a non-string value.
+		return literalHost.AsString(), true, false
+	}
+	return "", false, false
+}
+
+func ecsFargateResolver(attrs pcommon.Map) (string, bool, bool) {
+	if launchType, ok := attrs.Get(string(semconv16.AWSECSLaunchtypeKey)); ok && launchType.Str() == semconv16.AWSECSLaunchtypeFargate.Value.AsString() {
+		return "", false, true
+	}
+	return "", false, false
+}
+
+func cloudProviderResolver(attrs pcommon.Map) (string, bool, bool) {
+	cloudProvider, ok := attrs.Get(string(semconv16.CloudProviderKey))
+	if !ok {
+		return "", false, false
+	}
+
+	var hostname string
+	switch cloudProvider.Str() {
+	case semconv16.CloudProviderAWS.Value.AsString():
+		hostname, ok = ec2.HostnameFromAttrs(attrs)
+	case semconv16.CloudProviderGCP.Value.AsString():
+		hostname, ok = gcp.HostnameFromAttrs(attrs)
+	case semconv16.CloudProviderAzure.Value.AsString():
+		hostname, ok = azure.HostnameFromAttrs(attrs)
+	default:
+		return "", false, false
+	}
+	return hostname, ok, true
+}
+
+func kubernetesNodeResolver(attrs pcommon.Map) (string, bool, bool) {
+	hostname, ok := k8sHostnameFromAttributes(attrs)
+	return hostname, ok, false
+}
+
+// resolveHostname runs the resolvers in order until one finds a hostname or stops the resolution.
+func resolveHostname(attrs pcommon.Map, resolvers []HostnameResolver) (string, bool) {
+	for _, resolver := range resolvers {
+		if hostname, ok, stop := resolver(attrs); ok || stop {
+			return hostname, ok
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,127 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025-present Datadog, Inc.
+
+package attributes
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/otel/attribute"
+	semconv16 "go.opentelemetry.io/otel/semconv/v1.6.1"
+
+	"github.com/DataDog/opentelemetry-mapping-go/pkg/otlp/attributes/internal/testutils"
+	"github.com/DataDog/opentelemetry-mapping-go/pkg/otlp/attributes/source"
+)
+
+func TestResolveHostname(t *testing.T) {
+	k8sAttrs := map[string]string{
+		AttributeK8sNodeName:                testNodeName,
+		string(semconv16.K8SClusterNameKey): testClusterName,
+		string(semconv16.HostNameKey):       testHostName,
+	}
+	fargateAttrs := map[string]string{
+		string(semconv16.AWSECSLaunchtypeKey): semconv16.AWSECSLaunchtypeFargate.Value.AsString(),
+		string(semconv16.HostNameKey):         testHostName,
+	}
+
+	tests := []struct {
+		name      string
+		attrs     map[string]string
+		resolvers []HostnameResolver
+
+		ok       bool
+		hostname string
+	}{
+		{
+			name:      "default chain",
+			attrs:     k8sAttrs,
+			resolvers: DefaultHostnameResolvers(),
+			ok:        true,
+			hostname:  testNodeName + "-" + testClusterName,
+		},
+		{
+			name:      "host.name before Kubernetes",
+			attrs:     k8sAttrs,
+			resolvers: []HostnameResolver{HostNameResolver, KubernetesNodeResolver},
+			ok:        true,
+			hostname:  testHostName,
+		},
+		{
+			name:      "custom attribute",
+			attrs:     map[string]string{"nomad.node.name": "nomad-node", string(semconv16.HostNameKey): testHostName},
+			resolvers: append([]HostnameResolver{AttributeHostnameResolver("nomad.node.name")}, DefaultHostnameResolvers()...),
+			ok:        true,
+			hostname:  "nomad-node",
+		},
+		{
+			name:      "custom attribute missing",
+			attrs:     map[string]string{string(semconv16.HostNameKey): testHostName},
+			resolvers: append([]HostnameResolver{AttributeHostnameResolver("nomad.node.name")}, DefaultHostnameResolvers()...),
+			ok:        true,
+			hostname:  testHostName,
+		},
+		{
+			name:      "ECS Fargate stops the chain",
+			attrs:     fargateAttrs,
+			resolvers: DefaultHostnameResolvers(),
+		},
+		{
+			name:      "ECS Fargate not in the chain",
+			attrs:     fargateAttrs,
+			resolvers: []HostnameResolver{KubernetesNodeResolver, HostNameResolver},
+			ok:        true,
+			hostname:  testHostName,
+		},
+		{
+			name:      "no resolver applies",
+			attrs:     map[string]string{string(semconv16.HostIDKey): testHostID},
+			resolvers: []HostnameResolver{LiteralHostResolver, HostNameResolver},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hostname, ok := validHostnameFromAttributes(testutils.NewAttributeMap(tt.attrs), tt.resolvers)
+			assert.Equal(t, tt.ok, ok)
+			assert.Equal(t, tt.hostname, hostname)
+		})
+	}
+}
+
+func TestTranslatorWithHostnameResolvers(t *testing.T) {
+	translator, err := NewTranslator(componenttest.NewNopTelemetrySettings(),
+		WithHostnameResolvers(HostNameResolver, KubernetesNodeResolver),
+	)
+	require.NoError(t, err)
+
+	res := pcommon.NewResource()
+	res.Attributes().FromRaw(map[string]any{
+		AttributeK8sNodeName:          testNodeName,
+		string(semconv16.HostNameKey): testHostName,
+	})
+
+	src, ok := translator.ResourceToSource(context.Background(), res, attribute.NewSet(), nil)
+	assert.True(t, ok)
+	assert.Equal(t, source.Source{Kind: source.HostnameKind, Identifier: testHostName}, src)
+
+	src, ok = translator.AttributesToSource(context.Background(), res.Attributes())
+	assert.True(t, ok)
+	assert.Equal(t, source.Source{Kind: source.HostnameKind, Identifier: testHostName}, src)
+
+	// The package-level function keeps using the default chain.
+	src, ok = SourceFromAttrs(res.Attributes(), nil)
+	assert.True(t, ok)
+	assert.Equal(t, source.Source{Kind: source.HostnameKind, Identifier: testNodeName}, src)
+
+	_, err = NewTranslator(componenttest.NewNopTelemetrySettings(), WithHostnameResolvers())
+	assert.EqualError(t, err, "hostname resolution chain must not be empty")
+	_, err = NewTranslator(componenttest.NewNopTelemetrySettings(), WithHostnameResolvers(HostNameResolver, nil))
+	assert.EqualError(t, err, "hostname resolver 1 is nil")
+}
@@ -0,0 +1,80 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025-present Datadog, Inc.
+
+package attributes
+
+import (
+	"strings"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	semconv127 "go.opentelemetry.io/otel/semconv/v1.27.0"
+	semconv16 "go.opentelemetry.io/otel/semconv/v1.6.1"
+
+	"github.com/DataDog/opentelemetry-mapping-go/pkg/otlp/attributes/source"
+)
+
+// eksFargateNodePrefix is the prefix of the name of the nodes running pods on AWS EKS Fargate.
+const eksFargateNodePrefix = "fargate-"
+
+// firstStr returns the first non-empty string value of the given attributes.
+func firstStr(attrs pcommon.Map, keys ...string) (string, bool) {
+	for _, key := range keys {
+		if val, ok := attrs.Get(key); ok && val.Str() != "" {
+			return val.Str(), true
+		}
+	}
+	return "", false
+}
+
+func isCloudPlatform(attrs pcommon.Map, platform string) bool {
+	val, ok := attrs.Get(string(semconv127.CloudPlatformKey))
+	return ok && val.Str() == platform
+}
+
+// ecsFargateSourceFromAttrs gets an AWS ECS Fargate source from attributes.
+// ECS Fargate tasks have no hostname: they are identified by their task ARN, whatever their other attributes.
+func ecsFargateSourceFromAttrs(attrs pcommon.Map) (source.Source, bool) {
+	if launchType, ok := attrs.Get(string(semconv16.AWSECSLaunchtypeKey)); ok && launchType.Str() == semconv16.AWSECSLaunchtypeFargate.Value.AsString() {
+		if taskARN, ok := attrs.Get(string(semconv16.AWSECSTaskARNKey)); ok {
+			return source.Source{Kind: source.AWSECSFargateKind, Identifier: taskARN.Str()}, true
+		}
+	}
+	return source.Source{}, false
+}
+
+// serverlessSourceFromAttrs gets the source of serverless platforms other than AWS ECS Fargate from attributes.
+// Serverless sources have no hostname.
+func serverlessSourceFromAttrs(attrs pcommon.Map) (source.Source, bool) {
+	// AWS Lambda: cloud.resource_id replaces faas.id in recent semantic conventions.
+	if isCloudPlatform(attrs, semconv127.CloudPlatformAWSLambda.Value.AsString()) {
+		if arn, ok := firstStr(attrs, string(semconv127.CloudResourceIDKey), string(semconv16.FaaSIDKey)); ok {
+			return source.Source{Kind: source.AWSLambdaKind, Identifier: arn}, true
+		}
+	}
+
+	// AWS EKS Fargate: every pod runs on its own node.
+	if isCloudPlatform(attrs, semconv127.CloudPlatformAWSEKS.Value.AsString()) {
+		if node, ok := firstStr(attrs, AttributeK8sNodeName); ok && strings.HasPrefix(node, eksFargateNodePrefix) {
+			return source.Source{Kind: source.AWSEKSFargateKind, Identifier: node}, true
+		}
+	}
+
+	// GCP Cloud Run services and jobs. Jobs may only be detected from their execution attribute.
+	_, isCloudRunJob := attrs.Get(string(semconv127.GCPCloudRunJobExecutionKey))
+	if isCloudRunJob || isCloudPlatform(attrs, semconv127.CloudPlatformGCPCloudRun.Value.AsString()) {
+		if name, ok := firstStr(attrs, string(semconv127.FaaSNameKey)); ok {
+			return source.Source{Kind: source.GCPCloudRunKind, Identifier: name}, true
+		}
+	}
+
+	// Azure Container Apps
+	if isCloudPlatform(attrs, semconv127.CloudPlatformAzureContainerApps.Value.AsString()) {
+		if name, ok := firstStr(attrs, string(semconv127.FaaSNameKey)); ok {
+			return source.Source{Kind: source.AzureContainerAppsKind, Identifier: name}, true
+		}
+	}
+
+	return source.Source{}, false
+}
@@ -20,8 +20,7 @@ import (
 
 	"github.com/DataDog/datadog-agent/pkg/opentelemetry-mapping-go/otlp/attributes/azure"
 	"github.com/DataDog/datadog-agent/pkg/opentelemetry-mapping-go/otlp/attributes/ec2"
-	"github.com/DataDog/datadog-agent/pkg/opentelemetry-mapping-go/otlp/attributes/gcp"
-	"github.com/DataDog/datadog-agent/pkg/opentelemetry-mapping-go/otlp/attributes/source"
+	"github.com/DataDog/opentelemetry-mapping-go/pkg/otlp/attributes/source"
 )
 
 const (
@@ -49,22 +48,15 @@ func getClusterName(attrs pcommon.Map) (string, bool) {
 	return "", false
 }
 
-// hostnameFromAttributes tries to get a valid hostname from attributes by checking, in order:
-//
-//  1. the "host" attribute to avoid double tagging if present.
-//
-//  2. a custom Datadog hostname provided by the "datadog.host.name" attribute
-//
-//  3. cloud provider specific hostname for AWS, Azure or GCP,
-//
-//  4. the Kubernetes node name (and cluster name if available),
-//
-//  5. the cloud provider host ID and
-//
-//  6. the host.name attribute.
-//
-//     It returns a boolean value indicated if any name was found
+// hostnameFromAttributes tries to get a valid hostname from attributes
+// using the default hostname resolution chain (see DefaultHostnameResolvers).
+// It returns a boolean value indicated if any name was found
 func hostnameFromAttributes(attrs pcommon.Map) (string, bool) {
+	return validHostnameFromAttributes(attrs, defaultHostnameResolvers)
+}
+
+// validHostnameFromAttributes tries to get a valid hostname from attributes using the given resolvers.
+func validHostnameFromAttributes(attrs pcommon.Map, resolvers []HostnameResolver) (string, bool) {
 	// Check if the host is localhost or 0.0.0.0, if so discard it.
 	// We don't do the more strict validation done for metadata,
 	// to avoid breaking users existing invalid-but-accepted hostnames.
@@ -77,7 +69,7 @@ func hostnameFromAttributes(attrs pcommon.Map) (string, bool) {
 		"ip6-localhost":           {},
 	}
 
-	candidateHost, ok := unsanitizedHostnameFromAttributes(attrs)
+	candidateHost, ok := resolveHostname(attrs, resolvers)
 	if _, invalid := invalidHosts[candidateHost]; invalid {
 		return "", false
 	}
@@ -96,53 +88,6 @@ func k8sHostnameFromAttributes(attrs pcommon.Map) (string, bool) {
 	return node.Str(), true
 }
 
-func unsanitizedHostnameFromAttributes(attrs pcommon.Map) (string, bool) {
-	// Literal 'host' tag. Check and use to avoid double tagging.
-	if literalHost, ok := attrs.Get(AttributeHost); ok {
-		// Use even if not a string, so that we avoid double tagging if
-		// `resource_attributes_as_tags` is true and 'host' has a non-string value.
-		return literalHost.AsString(), true
-	}
-
-	// Custom hostname: useful for overriding in k8s/cloud envs
-	if customHostname, ok := attrs.Get(AttributeDatadogHostname); ok {
-		return customHostname.Str(), true
-	}
-
-	if launchType, ok := attrs.Get(string(semconv16.AWSECSLaunchtypeKey)); ok && launchType.Str() == semconv16.AWSECSLaunchtypeFargate.Value.AsString() {
-		// If on AWS ECS Fargate, we don't have a hostname
-		return "", false
-	}
-
-	cloudProvider, ok := attrs.Get(string(semconv16.CloudProviderKey))
-	switch {
-	case ok && cloudProvider.Str() == semconv16.CloudProviderAWS.Value.AsString():
-		return ec2.HostnameFromAttrs(attrs)
-	case ok && cloudProvider.Str() == semconv16.CloudProviderGCP.Value.AsString():
-		return gcp.HostnameFromAttrs(attrs)
-	case ok && cloudProvider.Str() == semconv16.CloudProviderAzure.Value.AsString():
-		return azure.HostnameFromAttrs(attrs)
-	}
-
-	// Kubernetes: node-cluster if cluster name is available, else node
-	k8sName, k8sOk := k8sHostnameFromAttributes(attrs)
-	if k8sOk {
-		return k8sName, true
-	}
-
-	// host id from cloud provider
-	if hostID, ok := attrs.Get(string(semconv16.HostIDKey)); ok {
-		return hostID.Str(), true
-	}
-
-	// hostname from cloud provider or OS
-	if hostName, ok := attrs.Get(string(semconv16.HostNameKey)); ok {
-		return hostName.Str(), true
-	}
-
-	return "", false
-}
-
 // HostFromAttributesHandler calls OnHost when a hostname is extracted from attributes.
 type HostFromAttributesHandler interface {
 	OnHost(string)
@@ -151,13 +96,29 @@ type HostFromAttributesHandler interface {
 // SourceFromAttrs gets a telemetry signal source from its attributes.
 // Deprecated: Use Translator.ResourceToSource or Translator.AttributesToSource instead.
 func SourceFromAttrs(attrs pcommon.Map, hostFromAttributesHandler HostFromAttributesHandler) (source.Source, bool) {
-	if launchType, ok := attrs.Get(string(semconv16.AWSECSLaunchtypeKey)); ok && launchType.Str() == semconv16.AWSECSLaunchtypeFargate.Value.AsString() {
-		if taskARN, ok := attrs.Get(string(semconv16.AWSECSTaskARNKey)); ok {
-			return source.Source{Kind: source.AWSECSFargateKind, Identifier: taskARN.Str()}, true
+	return sourceFromAttrs(attrs, defaultHostnameResolvers, hostFromAttributesHandler)
+}
+
+// hasExplicitHostname reports whether the user set a hostname with the "host" or "datadog.host.name" attribute.
+func hasExplicitHostname(attrs pcommon.Map) bool {
+	_, hasHost := attrs.Get(AttributeHost)
+	_, hasDatadogHostname := attrs.Get(AttributeDatadogHostname)
+	return hasHost || hasDatadogHostname
+}
+
+func sourceFromAttrs(attrs pcommon.Map, resolvers []HostnameResolver, hostFromAttributesHandler HostFromAttributesHandler) (source.Source, bool) {
+	if src, ok := ecsFargateSourceFromAttrs(attrs); ok {
+		return src, true
+	}
+	// An explicit hostname takes precedence over the other serverless sources,
+	// and is resolved by the hostname resolution chain.
+	if !hasExplicitHostname(attrs) {
+		if src, ok := serverlessSourceFromAttrs(attrs); ok {
+			return src, true
 		}
 	}
 
-	if host, ok := hostnameFromAttributes(attrs); ok {
+	if host, ok := validHostnameFromAttributes(attrs, resolvers); ok {
 		if hostFromAttributesHandler != nil {
 			hostFromAttributesHandler.OnHost(host)
 		}
@@ -27,8 +27,16 @@ const (
 	InvalidKind Kind = ""
 	// HostnameKind is a host source.
 	HostnameKind Kind = "host"
-	// AWSECSFargateKind is a serverless source on AWS ECS Fargate.
+	// AWSECSFargateKind is a serverless source on AWS ECS Fargate, identified by its task ARN.
 	AWSECSFargateKind Kind = "task_arn"
+	// AWSEKSFargateKind is a serverless source on AWS EKS Fargate, identified by the name of the node running its pod.
+	AWSEKSFargateKind Kind = "eks_fargate_node"
+	// AWSLambdaKind is a serverless source on AWS Lambda, identified by its function ARN.
+	AWSLambdaKind Kind = "function_arn"
+	// GCPCloudRunKind is a serverless source on GCP Cloud Run, identified by its service or job name.
+	GCPCloudRunKind Kind = "cloud_run"
+	// AzureContainerAppsKind is a serverless source on Azure Container Apps, identified by its container app name.
+	AzureContainerAppsKind Kind = "container_app"
 )
 
 // Source represents a telemetry source.
@@ -22,8 +22,8 @@ import (
 	semconv16 "go.opentelemetry.io/otel/semconv/v1.6.1"
 
 	"github.com/DataDog/datadog-agent/pkg/opentelemetry-mapping-go/otlp/attributes/azure"
-	"github.com/DataDog/datadog-agent/pkg/opentelemetry-mapping-go/otlp/attributes/source"
 	"github.com/DataDog/opentelemetry-mapping-go/pkg/otlp/attributes/internal/testutils"
+	"github.com/DataDog/opentelemetry-mapping-go/pkg/otlp/attributes/source"
 )
 
 const (
@@ -103,6 +103,104 @@ func TestSourceFromAttrs(t *testing.T) {
 			ok:  true,
 			src: source.Source{Kind: source.AWSECSFargateKind, Identifier: "example-task-ARN"},
 		},
+		{
+			name: "AWS Lambda",
+			attrs: testutils.NewAttributeMap(map[string]string{
+				string(semconv16.CloudProviderKey): semconv16.CloudProviderAWS.Value.AsString(),
+				string(semconv16.CloudPlatformKey): semconv16.CloudPlatformAWSLambda.Value.AsString(),
+				string(semconv16.FaaSNameKey):      "example-function",
+				"cloud.resource_id":                "arn:aws:lambda:us-east-1:123456789012:function:example-function",
+				string(semconv16.FaaSIDKey):        "arn:aws:lambda:us-east-1:123456789012:function:old",
+			}),
+			ok:  true,
+			src: source.Source{Kind: source.AWSLambdaKind, Identifier: "arn:aws:lambda:us-east-1:123456789012:function:example-function"},
+		},
+		{
+			name: "AWS Lambda with explicit hostname",
+			attrs: testutils.NewAttributeMap(map[string]string{
+				AttributeDatadogHostname:           testCustomName,
+				string(semconv16.CloudProviderKey): semconv16.CloudProviderAWS.Value.AsString(),
+				string(semconv16.CloudPlatformKey): semconv16.CloudPlatformAWSLambda.Value.AsString(),
+				"cloud.resource_id":                "arn:aws:lambda:us-east-1:123456789012:function:example-function",
+			}),
+			ok:  true,
+			src: source.Source{Kind: source.HostnameKind, Identifier: testCustomName},
+		},
+		{
+			name: "ECS Fargate with literal 'host' tag",
+			attrs: testutils.NewAttributeMap(map[string]string{
+				AttributeHost:                         testLiteralHost,
+				string(semconv16.CloudProviderKey):    semconv16.CloudProviderAWS.Value.AsString(),
+				string(semconv16.AWSECSTaskARNKey):    "example-task-ARN",
+				string(semconv16.AWSECSLaunchtypeKey): semconv16.AWSECSLaunchtypeFargate.Value.AsString(),
+			}),
+			ok:  true,
+			src: source.Source{Kind: source.AWSECSFargateKind, Identifier: "example-task-ARN"},
+		},
+		{
+			name: "AWS Lambda, faas.id",
+			attrs: testutils.NewAttributeMap(map[string]string{
+				string(semconv16.CloudProviderKey): semconv16.CloudProviderAWS.Value.AsString(),
+				string(semconv16.CloudPlatformKey): semconv16.CloudPlatformAWSLambda.Value.AsString(),
+				string(semconv16.FaaSIDKey):        "arn:aws:lambda:us-east-1:123456789012:function:old",
+			}),
+			ok:  true,
+			src: source.Source{Kind: source.AWSLambdaKind, Identifier: "arn:aws:lambda:us-east-1:123456789012:function:old"},
+		},
+		{
+			name: "AWS EKS Fargate",
+			attrs: testutils.NewAttributeMap(map[string]string{
+				string(semconv16.CloudProviderKey):  semconv16.CloudProviderAWS.Value.AsString(),
+				string(semconv16.CloudPlatformKey):  semconv16.CloudPlatformAWSEKS.Value.AsString(),
+				AttributeK8sNodeName:                "fargate-ip-10-0-1-2.ec2.internal",
+				string(semconv16.K8SClusterNameKey): testClusterName,
+			}),
+			ok:  true,
+			src: source.Source{Kind: source.AWSEKSFargateKind, Identifier: "fargate-ip-10-0-1-2.ec2.internal"},
+		},
+		{
+			name: "AWS EKS on EC2",
+			attrs: testutils.NewAttributeMap(map[string]string{
+				string(semconv16.CloudProviderKey): semconv16.CloudProviderAWS.Value.AsString(),
+				string(semconv16.CloudPlatformKey): semconv16.CloudPlatformAWSEKS.Value.AsString(),
+				AttributeK8sNodeName:               testNodeName,
+				string(semconv16.HostIDKey):        testHostID,
+			}),
+			ok:  true,
+			src: source.Source{Kind: source.HostnameKind, Identifier: testHostID},
+		},
+		{
+			name: "GCP Cloud Run service",
+			attrs: testutils.NewAttributeMap(map[string]string{
+				string(semconv16.CloudProviderKey): semconv16.CloudProviderGCP.Value.AsString(),
+				string(semconv16.CloudPlatformKey): semconv16.CloudPlatformGCPCloudRun.Value.AsString(),
+				string(semconv16.FaaSNameKey):      "example-service",
+				string(semconv16.FaaSVersionKey):   "example-service-00001-abc",
+			}),
+			ok:  true,
+			src: source.Source{Kind: source.GCPCloudRunKind, Identifier: "example-service"},
+		},
+		{
+			name: "GCP Cloud Run job",
+			attrs: testutils.NewAttributeMap(map[string]string{
+				string(semconv16.CloudProviderKey): semconv16.CloudProviderGCP.Value.AsString(),
+				string(semconv16.FaaSNameKey):      "example-job",
+				"gcp.cloud_run.job.execution":      "example-job-abcde",
+				"gcp.cloud_run.job.task_index":     "0",
+			}),
+			ok:  true,
+			src: source.Source{Kind: source.GCPCloudRunKind, Identifier: "example-job"},
+		},
+		{
+			name: "Azure Container Apps",
+			attrs: testutils.NewAttributeMap(map[string]string{
+				string(semconv16.CloudProviderKey): semconv16.CloudProviderAzure.Value.AsString(),
+				string(semconv16.CloudPlatformKey): "azure_container_apps",
+				string(semconv16.FaaSNameKey):      "example-app",
+			}),
+			ok:  true,
+			src: source.Source{Kind: source.AzureContainerAppsKind, Identifier: "example-app"},
+		},
 		{
 			name: "GCP",
 			attrs: testutils.NewAttributeMap(map[string]string{
@@ -252,3 +350,20 @@ func TestHostnameKubernetes(t *testing.T) {
 	// cluster name gets ignored, fallback to next option
 	assert.Equal(t, hostname, testHostID)
 }
+
+func TestSourceTag(t *testing.T) {
+	tests := []struct {
+		src source.Source
+		tag string
+	}{
+		{src: source.Source{Kind: source.HostnameKind, Identifier: "host"}, tag: "host:host"},
+		{src: source.Source{Kind: source.AWSECSFargateKind, Identifier: "arn"}, tag: "task_arn:arn"},
+		{src: source.Source{Kind: source.AWSEKSFargateKind, Identifier: "fargate-node"}, tag: "eks_fargate_node:fargate-node"},
+		{src: source.Source{Kind: source.AWSLambdaKind, Identifier: "arn"}, tag: "function_arn:arn"},
+		{src: source.Source{Kind: source.GCPCloudRunKind, Identifier: "service"}, tag: "cloud_run:service"},
+		{src: source.Source{Kind: source.AzureContainerAppsKind, Identifier: "app"}, tag: "container_app:app"},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.tag, tt.src.Tag())
+	}
+}
@@ -16,25 +16,69 @@ package attributes
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"slices"
 
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/pdata/pcommon"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/metric"
 
-	"github.com/DataDog/datadog-agent/pkg/opentelemetry-mapping-go/otlp/attributes/source"
+	"github.com/DataDog/opentelemetry-mapping-go/pkg/otlp/attributes/source"
 )
 
 const missingSourceMetricName string = "datadog.otlp_translator.resources.missing_source"
 
 // Translator of attributes.
 type Translator struct {
-	missingSources metric.Int64Counter
+	missingSources    metric.Int64Counter
+	hostnameResolvers []HostnameResolver
+}
+
+type translatorConfig struct {
+	hostnameResolvers []HostnameResolver
+}
+
+// TranslatorOption is a translator creation option.
+type TranslatorOption func(*translatorConfig) error
+
+// WithHostnameResolvers sets the ordered chain of strategies used to get the hostname
+// from resource attributes. The chain defaults to DefaultHostnameResolvers.
+//
+// To change the precedence of the default strategies, or to add custom ones, build a new chain, e.g.:
+//
+//	attributes.WithHostnameResolvers(
+//		attributes.AttributeHostnameResolver("nomad.node.name"),
+//		attributes.HostNameResolver,
+//		attributes.KubernetesNodeResolver,
+//	)
+func WithHostnameResolvers(resolvers ...HostnameResolver) TranslatorOption {
+	return func(cfg *translatorConfig) error {
+		if len(resolvers) == 0 {
+			return errors.New("hostname resolution chain must not be empty")
+		}
+		for i, resolver := range resolvers {
+			if resolver == nil {
+				return fmt.Errorf("hostname resolver %d is nil", i)
+			}
+		}
+		cfg.hostnameResolvers = slices.Clone(resolvers)
+		return nil
+	}
 }
 
 // NewTranslator returns a new attributes translator.
-func NewTranslator(set component.TelemetrySettings) (*Translator, error) {
+func NewTranslator(set component.TelemetrySettings, options ...TranslatorOption) (*Translator, error) {
+	cfg := translatorConfig{
+		hostnameResolvers: defaultHostnameResolvers,
+	}
+	for _, opt := range options {
+		if err := opt(&cfg); err != nil {
+			return nil, err
+		}
+	}
+
 	meter := set.MeterProvider.Meter("github.com/DataDog/datadog-agent/pkg/opentelemetry-mapping-go/otlp/attributes")
 	missingSources, err := meter.Int64Counter(
 		missingSourceMetricName,
@@ -46,13 +90,14 @@ func NewTranslator(set component.TelemetrySettings) (*Translator, error) {
 	}
 
 	return &Translator{
-		missingSources: missingSources,
+		missingSources:    missingSources,
+		hostnameResolvers: cfg.hostnameResolvers,
 	}, nil
 }
 
 // ResourceToSource gets a telemetry signal source from its resource attributes.
 func (p *Translator) ResourceToSource(ctx context.Context, res pcommon.Resource, set attribute.Set, hostFromAttributesHandler HostFromAttributesHandler) (source.Source, bool) {
-	src, ok := SourceFromAttrs(res.Attributes(), hostFromAttributesHandler)
+	src, ok := sourceFromAttrs(res.Attributes(), p.hostnameResolvers, hostFromAttributesHandler)
 	if !ok {
 		p.missingSources.Add(ctx, 1, metric.WithAttributeSet(set))
 	}
@@ -67,5 +112,5 @@ func (p *Translator) ResourceToSource(ctx context.Context, res pcommon.Resource,
 // because of a fallback logic that will be removed. The attributes detected are resource attributes,
 // not attributes from a telemetry signal.
 func (p *Translator) AttributesToSource(_ context.Context, attrs pcommon.Map) (source.Source, bool) {
-	return SourceFromAttrs(attrs, nil)
+	return sourceFromAttrs(attrs, p.hostnameResolvers, nil)
 }
@@ -0,0 +1,204 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logs
+
+import (
+	"fmt"
+	"maps"
+	"unicode/utf8"
+
+	"github.com/DataDog/datadog-api-client-go/v2/api/datadogV2"
+)
+
+// ddTruncated is the attribute set on logs whose message was truncated to fit the log size limit.
+const ddTruncated = ddNamespace + ".truncated"
+
+// BatchLimits are the limits of the Datadog Logs intake.
+type BatchLimits struct {
+	// MaxPayloadSize is the maximum size in bytes of the (uncompressed) JSON array of a batch.
+	MaxPayloadSize int
+	// MaxLogSize is the maximum size in bytes of a single JSON-encoded log.
+	MaxLogSize int
+	// MaxEntries is the maximum number of logs in a batch.
+	MaxEntries int
+}
+
+// DefaultBatchLimits returns the limits of the Datadog Logs intake:
+// 5MB per payload, 1MB per log and 1000 logs per payload.
+func DefaultBatchLimits() BatchLimits {
+	return BatchLimits{
+		MaxPayloadSize: 5_000_000,
+		MaxLogSize:     1_000_000,
+		MaxEntries:     1000,
+	}
+}
+
+// Validate the limits.
+func (l BatchLimits) Validate() error {
+	if l.MaxPayloadSize <= 0 {
+		return fmt.Errorf("max payload size must be positive: %d", l.MaxPayloadSize)
+	}
+	if l.MaxLogSize <= 0 {
+		return fmt.Errorf("max log size must be positive: %d", l.MaxLogSize)
+	}
+	if l.MaxEntries <= 0 {
+		return fmt.Errorf("max entries must be positive: %d", l.MaxEntries)
+	}
+	// A payload holds at least one log in a JSON array.
+	if l.MaxLogSize+2 > l.MaxPayloadSize {
+		return fmt.Errorf("max log size (%d) does not fit in max payload size (%d)", l.MaxLogSize, l.MaxPayloadSize)
+	}
+	return nil
+}
+
+// BatchReport reports the logs that were changed to fit the limits.
+type BatchReport struct {
+	// Truncated are the indexes of the logs whose message was truncated.
+	// Truncated logs have the `dd.truncated` attribute set to true.
+	Truncated []int
+	// Dropped are the indexes of the logs that were dropped because they
+	// are over the log size limit even without a message.
+	Dropped []int
+}
+
+// BatchLogs splits the logs mapped by a Translator into batches that fit the given limits, in order.
+// Logs over the log size limit have their message truncated, or are dropped if this is not enough.
+// The input logs are not modified.
+func BatchLogs(items []datadogV2.HTTPLogItem, limits BatchLimits) ([][]datadogV2.HTTPLogItem, BatchReport, error) {
+	if err := limits.Validate(); err != nil {
+		return nil, BatchReport{}, err
+	}
+
+	var batches [][]datadogV2.HTTPLogItem
+	b := batcher{limits: limits}
+	for _, item := range items {
+		full, err := b.add(item)
+		if err != nil {
+			return nil, b.report, err
+		}
+		if full != nil {
+			batches = append(batches, full)
+		}
+	}
+	if last := b.flush(); last != nil {
+		batches = append(batches, last)
+	}
+	return batches, b.report, nil
+}
+
+// batcher groups logs into batches that fit the limits, one log at a time.
+type batcher struct {
+	limits BatchLimits
+	report BatchReport
+	// count is the number of logs added so far, which is the index of the next log.
+	count int
+
+	batch []datadogV2.HTTPLogItem
+	// size is the size of the batch: the size of its logs, the commas between them and the enclosing brackets.
+	size int
+}
+
+// add adds a log to the current batch. It returns the previous batch if the log does not fit in it.
+func (b *batcher) add(item datadogV2.HTTPLogItem) ([]datadogV2.HTTPLogItem, error) {
+	i := b.count
+	b.count++
+	data, err := item.MarshalJSON()
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal log %d: %w", i, err)
+	}
+	size := len(data)
+	if size > b.limits.MaxLogSize {
+		var ok bool
+		item, size, ok, err = truncateLog(item, b.limits.MaxLogSize)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal log %d: %w", i, err)
+		}
+		if !ok {
+			b.report.Dropped = append(b.report.Dropped, i)
+			return nil, nil
+		}
+		b.report.Truncated = append(b.report.Truncated, i)
+	}
+
+	var full []datadogV2.HTTPLogItem
+	if len(b.batch) > 0 && (len(b.batch) == b.limits.MaxEntries || b.size+1+size > b.limits.MaxPayloadSize) {
+		full = b.flush()
+	}
+	if len(b.batch) == 0 {
+		b.size = 2 + size
+	} else {
+		b.size += 1 + size
+	}
+	b.batch = append(b.batch, item)
+	return full, nil
+}
+
+// flush returns the current batch, if any, and starts a new one.
+func (b *batcher) flush() []datadogV2.HTTPLogItem {
+	batch := b.batch
+	b.batch, b.size = nil, 0
+	return batch
+}
+
+// truncateLog truncates the message of a log to the longest prefix that fits in maxSize bytes once encoded.
+// It returns the truncated log and its size, or false if the log does not fit even without a message.
+func truncateLog(item datadogV2.HTTPLogItem, maxSize int) (datadogV2.HTTPLogItem, int, bool, error) {
+	item.AdditionalProperties = maps.Clone(item.AdditionalProperties)
+	if item.AdditionalProperties == nil {
+		item.AdditionalProperties = make(map[string]interface{}, 1)
+	}
+	item.AdditionalProperties[ddTruncated] = true
+
+	message := item.Message
+	// sizeWith returns the encoded size of the log with the message cut to the given length.
+	sizeWith := func(cut int) (int, error) {
+		item.Message = message[:cut]
+		data, err := item.MarshalJSON()
+		return len(data), err
+	}
+	runeStart := func(cut int) int {
+		for cut > 0 && cut < len(message) && !utf8.RuneStart(message[cut]) {
+			cut--
+		}
+		return cut
+	}
+
+	size, err := sizeWith(len(message))
+	if err != nil {
+		return item, 0, false, err
+	}
+	// Every byte removed from the message removes at least one byte from the encoded log,
+	// so removing the excess is enough: lo fits and hi does not.
+	lo, hi := runeStart(max(len(message)-(size-maxSize), 0)), len(message)
+	if size, err = sizeWith(lo); err != nil || size > maxSize {
+		return item, 0, false, err
+	}
+	for {
+		mid := runeStart(lo + (hi-lo)/2)
+		if mid == lo {
+			break
+		}
+		if size, err = sizeWith(mid); err != nil {
+			return item, 0, false, err
+		}
+		if size <= maxSize {
+			lo = mid
+		} else {
+			hi = mid
+		}
+	}
+	size, err = sizeWith(lo)
+	return item, size, err == nil, err
+}
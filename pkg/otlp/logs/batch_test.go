@@ -0,0 +1,141 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logs
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/DataDog/datadog-api-client-go/v2/api/datadogV2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestLogItem(message string) datadogV2.HTTPLogItem {
+	return datadogV2.HTTPLogItem{
+		Message:              message,
+		AdditionalProperties: map[string]interface{}{"status": "info"},
+	}
+}
+
+func TestBatchLimitsValidate(t *testing.T) {
+	assert.NoError(t, DefaultBatchLimits().Validate())
+	assert.EqualError(t, BatchLimits{MaxLogSize: 10, MaxEntries: 1}.Validate(), "max payload size must be positive: 0")
+	assert.EqualError(t, BatchLimits{MaxPayloadSize: 10, MaxEntries: 1}.Validate(), "max log size must be positive: 0")
+	assert.EqualError(t, BatchLimits{MaxPayloadSize: 10, MaxLogSize: 5}.Validate(), "max entries must be positive: 0")
+	assert.EqualError(t, BatchLimits{MaxPayloadSize: 10, MaxLogSize: 10, MaxEntries: 1}.Validate(), "max log size (10) does not fit in max payload size (10)")
+}
+
+func TestBatchLogs(t *testing.T) {
+	item := newTestLogItem("hello")
+	data, err := item.MarshalJSON()
+	require.NoError(t, err)
+	size := len(data)
+
+	tests := []struct {
+		name   string
+		count  int
+		limits BatchLimits
+		sizes  []int
+	}{
+		{
+			name:   "single batch",
+			count:  5,
+			limits: DefaultBatchLimits(),
+			sizes:  []int{5},
+		},
+		{
+			name:   "max entries",
+			count:  5,
+			limits: BatchLimits{MaxPayloadSize: 1000, MaxLogSize: 500, MaxEntries: 2},
+			sizes:  []int{2, 2, 1},
+		},
+		{
+			name:  "max payload size",
+			count: 5,
+			// Exactly 3 logs: 2 brackets and 2 commas.
+			limits: BatchLimits{MaxPayloadSize: 3*size + 4, MaxLogSize: size, MaxEntries: 1000},
+			sizes:  []int{3, 2},
+		},
+		{
+			name:   "empty",
+			limits: DefaultBatchLimits(),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var items []datadogV2.HTTPLogItem
+			for i := 0; i < tt.count; i++ {
+				items = append(items, newTestLogItem("hello"))
+			}
+			batches, report, err := BatchLogs(items, tt.limits)
+			require.NoError(t, err)
+			assert.Empty(t, report.Truncated)
+			assert.Empty(t, report.Dropped)
+
+			var sizes []int
+			for _, batch := range batches {
+				sizes = append(sizes, len(batch))
+				data, err := json.Marshal(batch)
+				require.NoError(t, err)
+				assert.LessOrEqual(t, len(data), tt.limits.MaxPayloadSize)
+			}
+			assert.Equal(t, tt.sizes, sizes)
+		})
+	}
+}
+
+func TestBatchLogsTruncation(t *testing.T) {
+	limits := BatchLimits{MaxPayloadSize: 1000, MaxLogSize: 200, MaxEntries: 1000}
+	items := []datadogV2.HTTPLogItem{
+		newTestLogItem("short"),
+		newTestLogItem(strings.Repeat("é\"", 100)),
+		newTestLogItem(""),
+	}
+	// Too large even without a message.
+	items[2].AdditionalProperties["attribute"] = strings.Repeat("a", 300)
+
+	batches, report, err := BatchLogs(items, limits)
+	require.NoError(t, err)
+	assert.Equal(t, []int{1}, report.Truncated)
+	assert.Equal(t, []int{2}, report.Dropped)
+
+	require.Len(t, batches, 1)
+	require.Len(t, batches[0], 2)
+	assert.Equal(t, "short", batches[0][0].Message)
+	assert.NotContains(t, batches[0][0].AdditionalProperties, ddTruncated)
+
+	truncated := batches[0][1]
+	assert.Equal(t, true, truncated.AdditionalProperties[ddTruncated])
+	assert.True(t, utf8.ValidString(truncated.Message))
+	assert.True(t, strings.HasPrefix(items[1].Message, truncated.Message))
+	data, err := truncated.MarshalJSON()
+	require.NoError(t, err)
+	assert.LessOrEqual(t, len(data), limits.MaxLogSize)
+	// Only what is needed is truncated.
+	assert.Greater(t, len(data), limits.MaxLogSize-len(`é\"`)-2)
+
+	// The input is not modified.
+	assert.Equal(t, strings.Repeat("é\"", 100), items[1].Message)
+	assert.NotContains(t, items[1].AdditionalProperties, ddTruncated)
+}
+
+func TestBatchLogsInvalidLimits(t *testing.T) {
+	_, _, err := BatchLogs([]datadogV2.HTTPLogItem{newTestLogItem("hello")}, BatchLimits{})
+	assert.EqualError(t, err, fmt.Sprintf("max payload size must be positive: %d", 0))
+}
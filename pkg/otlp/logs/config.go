@@ -0,0 +1,171 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logs
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/DataDog/opentelemetry-mapping-go/pkg/otlp/rum"
+)
+
+type translatorConfig struct {
+	// structuredBodyParsing reports whether map bodies, and string bodies holding a JSON object,
+	// are flattened into log attributes.
+	structuredBodyParsing bool
+
+	// traceID128Bit reports whether the high 64 bits of trace IDs are sent.
+	traceID128Bit bool
+
+	// remapTargets are the Datadog log fields log attributes are remapped to, by lowercase key.
+	remapTargets map[string]remapTarget
+
+	// severityTable normalizes severities to Datadog log statuses, if set.
+	severityTable *severityTable
+
+	// attributeLimits limits the attributes of logs.
+	attributeLimits AttributeLimits
+
+	// rumForwarder configures the forwarding of RUM events by MapLogsAndRouteRUMEvents.
+	rumForwarder rum.ForwarderConfig
+
+	// rumEventErrorHandler is called with the errors of the RUM events that could not be forwarded, if set.
+	rumEventErrorHandler RUMEventErrorHandler
+}
+
+// TranslatorOption is a translator creation option.
+type TranslatorOption func(*translatorConfig) error
+
+// WithStructuredBodyParsing enables flattening map bodies, and string bodies holding a JSON object,
+// into log attributes instead of sending them as the log message. The keys of the body are remapped
+// like those of the log attributes (e.g. `msg` is used as the log message).
+func WithStructuredBodyParsing() TranslatorOption {
+	return func(cfg *translatorConfig) error {
+		cfg.structuredBodyParsing = true
+		return nil
+	}
+}
+
+// WithAttributeRemapper sets the log attributes that are remapped to Datadog log fields.
+// It replaces DefaultAttributeRemapper: to extend it, add keys to the default remapper, e.g.:
+//
+//	remapper := logs.DefaultAttributeRemapper()
+//	remapper.MessageKeys = append(remapper.MessageKeys, "@m")
+//	remapper.ErrorStackKeys = append(remapper.ErrorStackKeys, "exception.stacktrace")
+//	logs.WithAttributeRemapper(remapper)
+func WithAttributeRemapper(remapper AttributeRemapper) TranslatorOption {
+	return func(cfg *translatorConfig) error {
+		targets, err := remapper.targets()
+		if err != nil {
+			return fmt.Errorf("invalid attribute remapper: %w", err)
+		}
+		cfg.remapTargets = targets
+		return nil
+	}
+}
+
+// With128BitTraceIDs enables sending the high 64 bits of 128-bit trace IDs in the `_dd.p.tid` attribute,
+// as 16 hex characters, so that logs are correlated with the right trace in Datadog.
+// The `dd.trace_id` attribute always holds the low 64 bits.
+func With128BitTraceIDs() TranslatorOption {
+	return func(cfg *translatorConfig) error {
+		cfg.traceID128Bit = true
+		return nil
+	}
+}
+
+// WithSeverityMapping enables normalizing log severities to Datadog log statuses with the given mappings,
+// e.g. to map the syslog `crit` and the Python `CRITICAL` severities to the `critical` status.
+// The mappings are merged in order, so later mappings override earlier ones:
+//
+//	logs.WithSeverityMapping(
+//		logs.SyslogSeverityMapping(),
+//		logs.PythonSeverityMapping(),
+//		logs.SeverityMapping{Texts: map[string]string{"success": "ok"}},
+//	)
+//
+// Without this option, severity texts are sent as is.
+func WithSeverityMapping(mappings ...SeverityMapping) TranslatorOption {
+	return func(cfg *translatorConfig) error {
+		table, err := newSeverityTable(mappings)
+		if err != nil {
+			return fmt.Errorf("invalid severity mapping: %w", err)
+		}
+		cfg.severityTable = table
+		return nil
+	}
+}
+
+// WithAttributeLimits sets limits on the attributes of logs. It replaces DefaultAttributeLimits:
+// to keep flattening maps up to the default depth, start from the default limits, e.g.:
+//
+//	limits := logs.DefaultAttributeLimits()
+//	limits.MaxAttributes = 256
+//	limits.MaxValueLength = 4096
+//	logs.WithAttributeLimits(limits)
+func WithAttributeLimits(limits AttributeLimits) TranslatorOption {
+	return func(cfg *translatorConfig) error {
+		if err := limits.Validate(); err != nil {
+			return fmt.Errorf("invalid attribute limits: %w", err)
+		}
+		cfg.attributeLimits = limits
+		return nil
+	}
+}
+
+// WithRUMForwarderConfig sets how RUM events are forwarded to the RUM intake by MapLogsAndRouteRUMEvents:
+// the number of events sent concurrently, how failed requests are retried, and whether events are validated.
+// It defaults to rum.DefaultForwarderConfig.
+func WithRUMForwarderConfig(forwarderConfig rum.ForwarderConfig) TranslatorOption {
+	return func(cfg *translatorConfig) error {
+		if err := forwarderConfig.Validate(); err != nil {
+			return fmt.Errorf("invalid RUM forwarder config: %w", err)
+		}
+		cfg.rumForwarder = forwarderConfig
+		return nil
+	}
+}
+
+// RUMEventErrorHandler handles the error of a RUM event that could not be forwarded to the RUM intake.
+// The error of an invalid event wraps a *rum.PayloadError.
+type RUMEventErrorHandler func(ctx context.Context, err rum.EventError)
+
+// WithRUMEventErrorHandler sets a handler called by MapLogsAndRouteRUMEvents and StreamLogsAndRouteRUMEvents
+// for every RUM event that could not be forwarded, e.g. to count or report invalid events.
+// These errors do not fail the mapping, and are logged whether or not a handler is set.
+func WithRUMEventErrorHandler(handler RUMEventErrorHandler) TranslatorOption {
+	return func(cfg *translatorConfig) error {
+		cfg.rumEventErrorHandler = handler
+		return nil
+	}
+}
+
+func defaultTranslatorConfig() translatorConfig {
+	return translatorConfig{
+		remapTargets:    defaultRemapTargets,
+		attributeLimits: DefaultAttributeLimits(),
+		rumForwarder:    rum.DefaultForwarderConfig(),
+	}
+}
+
+func newTranslatorConfig(options []TranslatorOption) (translatorConfig, error) {
+	cfg := defaultTranslatorConfig()
+	for _, opt := range options {
+		if err := opt(&cfg); err != nil {
+			return translatorConfig{}, err
+		}
+	}
+	return cfg, nil
+}
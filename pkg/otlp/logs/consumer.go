@@ -0,0 +1,93 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logs
+
+import (
+	"context"
+
+	"github.com/DataDog/datadog-api-client-go/v2/api/datadogV2"
+)
+
+// Consumer consumes the logs mapped by a Translator, one at a time.
+type Consumer interface {
+	// ConsumeLog consumes a log. An error stops the mapping and is returned by the Translator.
+	ConsumeLog(ctx context.Context, log datadogV2.HTTPLogItem) error
+}
+
+// ConsumerFunc is a function implementing Consumer.
+type ConsumerFunc func(ctx context.Context, log datadogV2.HTTPLogItem) error
+
+// ConsumeLog calls f.
+func (f ConsumerFunc) ConsumeLog(ctx context.Context, log datadogV2.HTTPLogItem) error {
+	return f(ctx, log)
+}
+
+// BatchConsumer consumes batches of logs that fit the limits of the Datadog Logs intake.
+type BatchConsumer interface {
+	// ConsumeLogs consumes a batch of logs.
+	ConsumeLogs(ctx context.Context, logs []datadogV2.HTTPLogItem) error
+}
+
+// BatchConsumerFunc is a function implementing BatchConsumer.
+type BatchConsumerFunc func(ctx context.Context, logs []datadogV2.HTTPLogItem) error
+
+// ConsumeLogs calls f.
+func (f BatchConsumerFunc) ConsumeLogs(ctx context.Context, logs []datadogV2.HTTPLogItem) error {
+	return f(ctx, logs)
+}
+
+var _ Consumer = (*BatchingConsumer)(nil)
+
+// BatchingConsumer is a Consumer that groups logs into batches, like BatchLogs,
+// and passes every full batch to a BatchConsumer.
+// Flush must be called once all logs are consumed to pass the last batch.
+type BatchingConsumer struct {
+	next    BatchConsumer
+	batcher batcher
+}
+
+// NewBatchingConsumer returns a BatchingConsumer passing batches that fit the given limits to next.
+func NewBatchingConsumer(next BatchConsumer, limits BatchLimits) (*BatchingConsumer, error) {
+	if err := limits.Validate(); err != nil {
+		return nil, err
+	}
+	return &BatchingConsumer{
+		next:    next,
+		batcher: batcher{limits: limits},
+	}, nil
+}
+
+// ConsumeLog implements Consumer.
+func (c *BatchingConsumer) ConsumeLog(ctx context.Context, log datadogV2.HTTPLogItem) error {
+	full, err := c.batcher.add(log)
+	if err != nil || full == nil {
+		return err
+	}
+	return c.next.ConsumeLogs(ctx, full)
+}
+
+// Flush passes the current batch, if any, to the BatchConsumer.
+func (c *BatchingConsumer) Flush(ctx context.Context) error {
+	if batch := c.batcher.flush(); batch != nil {
+		return c.next.ConsumeLogs(ctx, batch)
+	}
+	return nil
+}
+
+// Report returns the logs consumed so far that were changed to fit the limits.
+// Indexes count the logs in the order they were consumed.
+func (c *BatchingConsumer) Report() BatchReport {
+	return c.batcher.report
+}
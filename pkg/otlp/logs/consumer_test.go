@@ -0,0 +1,186 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/DataDog/datadog-api-client-go/v2/api/datadogV2"
+	"github.com/DataDog/opentelemetry-mapping-go/pkg/otlp/attributes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.uber.org/zap/zaptest"
+)
+
+func newTestLogs(count int) plog.Logs {
+	logs := plog.NewLogs()
+	lrs := logs.ResourceLogs().AppendEmpty().ScopeLogs().AppendEmpty().LogRecords()
+	for i := 0; i < count; i++ {
+		lrs.AppendEmpty().Body().SetStr(fmt.Sprintf("log %d", i))
+	}
+	return logs
+}
+
+func newTestStreamingTranslator(t *testing.T) *Translator {
+	set := componenttest.NewNopTelemetrySettings()
+	set.Logger = zaptest.NewLogger(t)
+	attributesTranslator, err := attributes.NewTranslator(set)
+	require.NoError(t, err)
+	translator, err := NewTranslatorWithHTTPClient(set, attributesTranslator, "test", &mockHTTPClient{})
+	require.NoError(t, err)
+	return translator
+}
+
+func TestStreamLogsAndRouteRUMEvents(t *testing.T) {
+	translator := newTestStreamingTranslator(t)
+	logs := newTestLogs(3)
+
+	var messages []string
+	err := translator.StreamLogsAndRouteRUMEvents(context.Background(), logs, nil, false, "", ConsumerFunc(func(_ context.Context, log datadogV2.HTTPLogItem) error {
+		messages = append(messages, log.Message)
+		assert.Equal(t, "otel_source:test", log.GetDdtags())
+		return nil
+	}))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"log 0", "log 1", "log 2"}, messages)
+
+	// The mapped logs are the same as those returned by MapLogsAndRouteRUMEvents.
+	payloads, err := translator.MapLogsAndRouteRUMEvents(context.Background(), logs, nil, false, "")
+	require.NoError(t, err)
+	require.Len(t, payloads, 3)
+	for i, payload := range payloads {
+		assert.Equal(t, messages[i], payload.Message)
+	}
+}
+
+func TestStreamLogsAndRouteRUMEventsConsumerError(t *testing.T) {
+	translator := newTestStreamingTranslator(t)
+	errConsumer := errors.New("consumer error")
+
+	var count int
+	err := translator.StreamLogsAndRouteRUMEvents(context.Background(), newTestLogs(3), nil, false, "", ConsumerFunc(func(context.Context, datadogV2.HTTPLogItem) error {
+		count++
+		if count == 2 {
+			return errConsumer
+		}
+		return nil
+	}))
+	assert.ErrorIs(t, err, errConsumer)
+	// Mapping stops at the first error.
+	assert.Equal(t, 2, count)
+}
+
+func TestStreamLogsAndRouteRUMEventsConsumerErrorForwardsRUMEvents(t *testing.T) {
+	var sessions []string
+	var mu sync.Mutex
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload map[string]any
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&payload))
+		mu.Lock()
+		defer mu.Unlock()
+		session, _ := payload["session"].(map[string]any)
+		sessions = append(sessions, fmt.Sprint(session["id"]))
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	set := componenttest.NewNopTelemetrySettings()
+	attributesTranslator, err := attributes.NewTranslator(set)
+	require.NoError(t, err)
+	translator, err := NewTranslatorWithHTTPClient(set, attributesTranslator, "test", server.Client())
+	require.NoError(t, err)
+
+	logs := plog.NewLogs()
+	lrs := logs.ResourceLogs().AppendEmpty().ScopeLogs().AppendEmpty().LogRecords()
+	lrs.AppendEmpty().Attributes().PutStr("session.id", "session-1")
+	lrs.AppendEmpty().Body().SetStr("log")
+	lrs.AppendEmpty().Attributes().PutStr("session.id", "session-2")
+
+	errConsumer := errors.New("consumer error")
+	err = translator.StreamLogsAndRouteRUMEvents(context.Background(), logs, nil, true, server.URL, ConsumerFunc(func(context.Context, datadogV2.HTTPLogItem) error {
+		return errConsumer
+	}))
+	assert.ErrorIs(t, err, errConsumer)
+	// The RUM event collected before the error is forwarded, mapping stops before the second one.
+	assert.Equal(t, []string{"session-1"}, sessions)
+}
+
+func TestStreamLogsAndRouteRUMEventsNoHTTPClient(t *testing.T) {
+	set := componenttest.NewNopTelemetrySettings()
+	attributesTranslator, err := attributes.NewTranslator(set)
+	require.NoError(t, err)
+	translator, err := NewTranslator(set, attributesTranslator, "test")
+	require.NoError(t, err)
+
+	err = translator.StreamLogsAndRouteRUMEvents(context.Background(), newTestLogs(1), nil, false, "", ConsumerFunc(func(context.Context, datadogV2.HTTPLogItem) error {
+		t.Fatal("no log should be consumed")
+		return nil
+	}))
+	assert.EqualError(t, err, "httpClient is nil")
+}
+
+func TestBatchingConsumer(t *testing.T) {
+	translator := newTestStreamingTranslator(t)
+
+	var batches [][]datadogV2.HTTPLogItem
+	consumer, err := NewBatchingConsumer(BatchConsumerFunc(func(_ context.Context, logs []datadogV2.HTTPLogItem) error {
+		batches = append(batches, logs)
+		return nil
+	}), BatchLimits{MaxPayloadSize: 10_000, MaxLogSize: 200, MaxEntries: 2})
+	require.NoError(t, err)
+
+	logs := newTestLogs(5)
+	logs.ResourceLogs().At(0).ScopeLogs().At(0).LogRecords().At(3).Body().SetStr(strings.Repeat("a", 300))
+	require.NoError(t, translator.StreamLogsAndRouteRUMEvents(context.Background(), logs, nil, false, "", consumer))
+	// Full batches are consumed as soon as they are full.
+	require.Len(t, batches, 2)
+	require.NoError(t, consumer.Flush(context.Background()))
+	require.Len(t, batches, 3)
+	// Flushing again is a no-op.
+	require.NoError(t, consumer.Flush(context.Background()))
+	require.Len(t, batches, 3)
+
+	var sizes []int
+	for _, batch := range batches {
+		sizes = append(sizes, len(batch))
+	}
+	assert.Equal(t, []int{2, 2, 1}, sizes)
+	assert.Equal(t, BatchReport{Truncated: []int{3}}, consumer.Report())
+	assert.Equal(t, true, batches[1][1].AdditionalProperties[ddTruncated])
+}
+
+func TestBatchingConsumerErrors(t *testing.T) {
+	_, err := NewBatchingConsumer(BatchConsumerFunc(func(context.Context, []datadogV2.HTTPLogItem) error { return nil }), BatchLimits{})
+	assert.EqualError(t, err, "max payload size must be positive: 0")
+
+	errConsumer := errors.New("consumer error")
+	consumer, err := NewBatchingConsumer(BatchConsumerFunc(func(context.Context, []datadogV2.HTTPLogItem) error {
+		return errConsumer
+	}), BatchLimits{MaxPayloadSize: 10_000, MaxLogSize: 200, MaxEntries: 1})
+	require.NoError(t, err)
+	require.NoError(t, consumer.ConsumeLog(context.Background(), newTestLogItem("first")))
+	assert.ErrorIs(t, consumer.ConsumeLog(context.Background(), newTestLogItem("second")), errConsumer)
+	assert.ErrorIs(t, consumer.Flush(context.Background()), errConsumer)
+}
@@ -0,0 +1,251 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logs
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"maps"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/DataDog/datadog-api-client-go/v2/api/datadogV2"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+	semconv16 "go.opentelemetry.io/otel/semconv/v1.6.1"
+)
+
+// ddSource is the log record attribute holding the source of a Datadog log.
+const ddSource = "ddsource"
+
+// tagAttributes are the resource attributes of Datadog tags, by tag key.
+// Other tags are mapped to resource attributes of the same key.
+var tagAttributes = map[string]string{
+	"env":     string(semconv16.DeploymentEnvironmentKey),
+	"version": string(semconv16.ServiceVersionKey),
+}
+
+// statusSeverityNumbers are the severity numbers of Datadog log statuses, following the OpenTelemetry
+// mapping of syslog severities.
+var statusSeverityNumbers = map[string]plog.SeverityNumber{
+	logLevelTrace:     plog.SeverityNumberTrace,
+	logLevelDebug:     plog.SeverityNumberDebug,
+	logLevelInfo:      plog.SeverityNumberInfo,
+	"ok":              plog.SeverityNumberInfo,
+	logLevelNotice:    plog.SeverityNumberInfo2,
+	logLevelWarn:      plog.SeverityNumberWarn,
+	"warning":         plog.SeverityNumberWarn,
+	logLevelError:     plog.SeverityNumberError,
+	logLevelCritical:  plog.SeverityNumberError2,
+	logLevelAlert:     plog.SeverityNumberError3,
+	logLevelEmergency: plog.SeverityNumberFatal,
+	logLevelFatal:     plog.SeverityNumberFatal,
+}
+
+// resourceKey identifies the resource of a Datadog log.
+type resourceKey struct {
+	hostname string
+	service  string
+	ddtags   string
+}
+
+// FromHTTPLogItems rebuilds OTLP logs from logs in the Datadog HTTP intake format, such as those
+// sent by Datadog Agents or mapped by a Translator. Logs with the same hostname, service and tags
+// share a resource:
+//   - the hostname and the service are mapped to the `host.name` and `service.name` resource attributes,
+//   - tags are mapped to resource attributes, `env` and `version` to their OpenTelemetry counterparts.
+//     Tags with the same key are mapped to a slice.
+//
+// The status is mapped to the severity, `otel.timestamp` or else `@timestamp` to the timestamp,
+// and `otel.trace_id`/`otel.span_id` or else `dd.trace_id`/`dd.span_id` to the trace and span IDs.
+// Attributes that are mapped are removed, unless they cannot be decoded. Other attributes are kept as log attributes.
+func FromHTTPLogItems(items []datadogV2.HTTPLogItem) plog.Logs {
+	logs := plog.NewLogs()
+	scopeLogs := make(map[resourceKey]plog.ScopeLogs)
+	for _, item := range items {
+		key := resourceKey{
+			hostname: item.GetHostname(),
+			service:  item.GetService(),
+			ddtags:   item.GetDdtags(),
+		}
+		sl, ok := scopeLogs[key]
+		if !ok {
+			rl := logs.ResourceLogs().AppendEmpty()
+			setResourceAttributes(rl.Resource().Attributes(), key)
+			sl = rl.ScopeLogs().AppendEmpty()
+			scopeLogs[key] = sl
+		}
+		fromHTTPLogItem(item, sl.LogRecords().AppendEmpty())
+	}
+	return logs
+}
+
+// setResourceAttributes sets the resource attributes of the logs with the given resource key.
+func setResourceAttributes(attrs pcommon.Map, key resourceKey) {
+	for _, tag := range strings.Split(key.ddtags, ",") {
+		if tag == "" {
+			continue
+		}
+		k, v, _ := strings.Cut(tag, ":")
+		if attr, ok := tagAttributes[k]; ok {
+			k = attr
+		}
+		existing, ok := attrs.Get(k)
+		switch {
+		case !ok:
+			attrs.PutStr(k, v)
+		case existing.Type() == pcommon.ValueTypeSlice:
+			existing.Slice().AppendEmpty().SetStr(v)
+		default:
+			first := existing.Str()
+			values := existing.SetEmptySlice()
+			values.AppendEmpty().SetStr(first)
+			values.AppendEmpty().SetStr(v)
+		}
+	}
+	if key.hostname != "" {
+		attrs.PutStr(string(semconv16.HostNameKey), key.hostname)
+	}
+	if key.service != "" {
+		attrs.PutStr(string(semconv16.ServiceNameKey), key.service)
+	}
+}
+
+// fromHTTPLogItem sets the log record lr from a Datadog log.
+func fromHTTPLogItem(item datadogV2.HTTPLogItem, lr plog.LogRecord) {
+	lr.Body().SetStr(item.Message)
+	if item.HasDdsource() {
+		lr.Attributes().PutStr(ddSource, item.GetDdsource())
+	}
+
+	props := maps.Clone(item.AdditionalProperties)
+	// take decodes the string value of an attribute, and removes the attribute if decoding succeeds.
+	take := func(k string, decode func(string) error) {
+		v, ok := props[k]
+		if !ok {
+			return
+		}
+		s, ok := v.(string)
+		if !ok {
+			s = fmt.Sprint(v)
+		}
+		if decode(s) == nil {
+			delete(props, k)
+		}
+	}
+
+	take(otelSeverityNumber, func(s string) error {
+		n, err := strconv.ParseInt(s, 10, 32)
+		if err != nil || n < int64(plog.SeverityNumberTrace) || n > int64(plog.SeverityNumberFatal4) {
+			return fmt.Errorf("invalid severity number %q", s)
+		}
+		lr.SetSeverityNumber(plog.SeverityNumber(n))
+		return nil
+	})
+	take(otelSeverityText, func(s string) error {
+		lr.SetSeverityText(s)
+		return nil
+	})
+	take(ddStatus, func(s string) error {
+		if lr.SeverityText() == "" {
+			lr.SetSeverityText(s)
+		}
+		if lr.SeverityNumber() == plog.SeverityNumberUnspecified {
+			lr.SetSeverityNumber(statusSeverityNumbers[strings.ToLower(s)])
+		}
+		return nil
+	})
+
+	take(otelTimestamp, func(s string) error {
+		ns, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			return err
+		}
+		lr.SetTimestamp(pcommon.Timestamp(ns))
+		return nil
+	})
+	take(ddTimestamp, func(s string) error {
+		ts, err := time.Parse(time.RFC3339Nano, s)
+		if err != nil {
+			return err
+		}
+		if lr.Timestamp() == 0 {
+			lr.SetTimestamp(pcommon.NewTimestampFromTime(ts))
+		}
+		return nil
+	})
+
+	take(otelTraceID, func(s string) error {
+		traceID, err := decodeTraceID(s)
+		if err != nil {
+			return err
+		}
+		lr.SetTraceID(traceID)
+		return nil
+	})
+	take(ddTraceID, func(s string) error {
+		traceID, err := decodeDatadogTraceID(s)
+		if err != nil {
+			return err
+		}
+		if lr.TraceID().IsEmpty() {
+			lr.SetTraceID(traceID)
+		}
+		return nil
+	})
+	take(ddTraceIDHigh, func(s string) error {
+		var high [8]byte
+		if len(s) != hex.EncodedLen(len(high)) {
+			return fmt.Errorf("invalid trace ID high bits %q", s)
+		}
+		if _, err := hex.Decode(high[:], []byte(s)); err != nil {
+			return err
+		}
+		traceID := lr.TraceID()
+		if !traceID.IsEmpty() && traceIDHighToUint64(traceID) == 0 {
+			copy(traceID[:8], high[:])
+			lr.SetTraceID(traceID)
+		} else if traceIDHighToUint64(traceID) != binary.BigEndian.Uint64(high[:]) {
+			return fmt.Errorf("trace ID high bits %q do not match the trace ID", s)
+		}
+		return nil
+	})
+	take(otelSpanID, func(s string) error {
+		spanID, err := decodeSpanID(s)
+		if err != nil {
+			return err
+		}
+		lr.SetSpanID(spanID)
+		return nil
+	})
+	take(ddSpanID, func(s string) error {
+		spanID, err := decodeDatadogSpanID(s)
+		if err != nil {
+			return err
+		}
+		if lr.SpanID().IsEmpty() {
+			lr.SetSpanID(spanID)
+		}
+		return nil
+	})
+
+	for k, v := range props {
+		if err := lr.Attributes().PutEmpty(k).FromRaw(v); err != nil {
+			lr.Attributes().PutStr(k, fmt.Sprint(v))
+		}
+	}
+}
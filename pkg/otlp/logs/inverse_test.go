@@ -0,0 +1,135 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logs
+
+import (
+	"testing"
+	"time"
+
+	"github.com/DataDog/datadog-api-client-go/v2/api/datadog"
+	"github.com/DataDog/datadog-api-client-go/v2/api/datadogV2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestFromHTTPLogItems(t *testing.T) {
+	items := []datadogV2.HTTPLogItem{
+		{
+			Hostname: datadog.PtrString("host-a"),
+			Service:  datadog.PtrString("checkout"),
+			Ddtags:   datadog.PtrString("env:prod,version:1.2.3,team:a,team:b,flag"),
+			Ddsource: datadog.PtrString("nginx"),
+			Message:  "agent log",
+			AdditionalProperties: map[string]interface{}{
+				"status":      "Warning",
+				"@timestamp":  "2024-05-06T07:08:09.123Z",
+				"dd.trace_id": "9532127138774266268",
+				"_dd.p.tid":   "0af7651916cd43dd",
+				"dd.span_id":  "13235353014750950193",
+				"http": map[string]interface{}{
+					"status_code": float64(200),
+				},
+			},
+		},
+		{
+			Hostname: datadog.PtrString("host-b"),
+			Message:  "other host",
+			AdditionalProperties: map[string]interface{}{
+				"status":      "critical",
+				"dd.trace_id": "not a trace id",
+			},
+		},
+		{
+			Hostname:             datadog.PtrString("host-a"),
+			Service:              datadog.PtrString("checkout"),
+			Ddtags:               datadog.PtrString("env:prod,version:1.2.3,team:a,team:b,flag"),
+			Message:              "same resource",
+			AdditionalProperties: map[string]interface{}{"count": 3},
+		},
+	}
+
+	logs := FromHTTPLogItems(items)
+	require.Equal(t, 2, logs.ResourceLogs().Len())
+
+	rl := logs.ResourceLogs().At(0)
+	assert.Equal(t, map[string]any{
+		"host.name":              "host-a",
+		"service.name":           "checkout",
+		"deployment.environment": "prod",
+		"service.version":        "1.2.3",
+		"team":                   []any{"a", "b"},
+		"flag":                   "",
+	}, rl.Resource().Attributes().AsRaw())
+	lrs := rl.ScopeLogs().At(0).LogRecords()
+	require.Equal(t, 2, lrs.Len())
+
+	lr := lrs.At(0)
+	assert.Equal(t, "agent log", lr.Body().Str())
+	assert.Equal(t, "Warning", lr.SeverityText())
+	assert.Equal(t, plog.SeverityNumberWarn, lr.SeverityNumber())
+	assert.Equal(t, time.Date(2024, 5, 6, 7, 8, 9, 123_000_000, time.UTC), lr.Timestamp().AsTime())
+	assert.Equal(t, pcommon.TraceID{0x0a, 0xf7, 0x65, 0x19, 0x16, 0xcd, 0x43, 0xdd, 0x84, 0x48, 0xeb, 0x21, 0x1c, 0x80, 0x31, 0x9c}, lr.TraceID())
+	assert.Equal(t, pcommon.SpanID{0xb7, 0xad, 0x6b, 0x71, 0x69, 0x20, 0x33, 0x31}, lr.SpanID())
+	assert.Equal(t, map[string]any{
+		"ddsource": "nginx",
+		"http":     map[string]any{"status_code": float64(200)},
+	}, lr.Attributes().AsRaw())
+
+	assert.Equal(t, "same resource", lrs.At(1).Body().Str())
+	assert.Equal(t, map[string]any{"count": int64(3)}, lrs.At(1).Attributes().AsRaw())
+
+	rl = logs.ResourceLogs().At(1)
+	assert.Equal(t, map[string]any{"host.name": "host-b"}, rl.Resource().Attributes().AsRaw())
+	lr = rl.ScopeLogs().At(0).LogRecords().At(0)
+	assert.Equal(t, plog.SeverityNumberError2, lr.SeverityNumber())
+	assert.True(t, lr.TraceID().IsEmpty())
+	// Attributes that cannot be decoded are kept.
+	assert.Equal(t, map[string]any{"dd.trace_id": "not a trace id"}, lr.Attributes().AsRaw())
+}
+
+func TestFromHTTPLogItemsRoundTrip(t *testing.T) {
+	lr := plog.NewLogRecord()
+	lr.Body().SetStr("hello")
+	lr.SetTimestamp(pcommon.Timestamp(1_714_979_289_123_456_789))
+	lr.SetSeverityText("WARN")
+	lr.SetSeverityNumber(plog.SeverityNumberWarn2)
+	lr.SetTraceID(pcommon.TraceID{0x0a, 0xf7, 0x65, 0x19, 0x16, 0xcd, 0x43, 0xdd, 0x84, 0x48, 0xeb, 0x21, 0x1c, 0x80, 0x31, 0x9c})
+	lr.SetSpanID(pcommon.SpanID{0xb7, 0xad, 0x6b, 0x71, 0x69, 0x20, 0x33, 0x31})
+	lr.Attributes().PutStr("key", "value")
+	res := pcommon.NewResource()
+	res.Attributes().PutStr("service.name", "checkout")
+
+	cfg, err := newTranslatorConfig([]TranslatorOption{With128BitTraceIDs()})
+	require.NoError(t, err)
+	item := transform(lr, "host", "checkout", res, pcommon.NewInstrumentationScope(), cfg, zaptest.NewLogger(t))
+
+	logs := FromHTTPLogItems([]datadogV2.HTTPLogItem{item})
+	require.Equal(t, 1, logs.LogRecordCount())
+	rl := logs.ResourceLogs().At(0)
+	assert.Equal(t, "host", rl.Resource().Attributes().AsRaw()["host.name"])
+	assert.Equal(t, "checkout", rl.Resource().Attributes().AsRaw()["service.name"])
+
+	got := rl.ScopeLogs().At(0).LogRecords().At(0)
+	assert.Equal(t, lr.Body().AsRaw(), got.Body().AsRaw())
+	assert.Equal(t, lr.Timestamp(), got.Timestamp())
+	assert.Equal(t, lr.SeverityText(), got.SeverityText())
+	assert.Equal(t, lr.SeverityNumber(), got.SeverityNumber())
+	assert.Equal(t, lr.TraceID(), got.TraceID())
+	assert.Equal(t, lr.SpanID(), got.SpanID())
+	assert.Equal(t, map[string]any{"key": "value", "service.name": "checkout"}, got.Attributes().AsRaw())
+}
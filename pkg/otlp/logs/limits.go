@@ -0,0 +1,133 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logs
+
+import (
+	"fmt"
+	"unicode/utf8"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+)
+
+// ddAttributesTruncated is the attribute set on logs whose attributes were truncated to fit the attribute limits.
+const ddAttributesTruncated = ddNamespace + ".attributes_truncated"
+
+// AttributeLimits limits the attributes of a log, to avoid creating many facets from a single log.
+// A zero limit means no limit. Logs with attributes that were dropped or truncated to fit the limits
+// have the `dd.attributes_truncated` attribute set to true.
+type AttributeLimits struct {
+	// MaxDepth is the maximum depth of flattened map attributes, e.g. `a.b.c` has depth 3.
+	// Maps at this depth are sent as JSON strings instead of being flattened, which does not drop data.
+	MaxDepth int
+	// MaxAttributes is the maximum number of attributes of a log, from the log record, its body,
+	// its resource and its scope. Attributes over the limit are dropped.
+	// Attributes set by the Translator (e.g. `status` or `dd.trace_id`) are not limited.
+	MaxAttributes int
+	// MaxValueLength is the maximum length in bytes of string attribute values, including
+	// those in arrays. Longer values are truncated.
+	MaxValueLength int
+}
+
+// DefaultAttributeLimits returns the limits used by default: maps are flattened up to a depth of 10.
+func DefaultAttributeLimits() AttributeLimits {
+	return AttributeLimits{
+		MaxDepth: 10,
+	}
+}
+
+// Validate the limits.
+func (l AttributeLimits) Validate() error {
+	if l.MaxDepth < 0 {
+		return fmt.Errorf("max depth must not be negative: %d", l.MaxDepth)
+	}
+	if l.MaxAttributes < 0 {
+		return fmt.Errorf("max attributes must not be negative: %d", l.MaxAttributes)
+	}
+	if l.MaxValueLength < 0 {
+		return fmt.Errorf("max value length must not be negative: %d", l.MaxValueLength)
+	}
+	return nil
+}
+
+// attributeLimiter sets the attributes of a log within the attribute limits.
+type attributeLimiter struct {
+	limits AttributeLimits
+	props  map[string]any
+	// count is the number of attributes set.
+	count int
+	// truncated reports whether an attribute was truncated or dropped.
+	truncated bool
+}
+
+// set sets an attribute, unless the log has too many attributes.
+func (a *attributeLimiter) set(k string, v any) {
+	if _, ok := a.props[k]; !ok {
+		if a.limits.MaxAttributes > 0 && a.count >= a.limits.MaxAttributes {
+			a.truncated = true
+			return
+		}
+		a.count++
+	}
+	a.props[k] = v
+}
+
+// setString sets a string attribute, truncated to the maximum value length.
+func (a *attributeLimiter) setString(k string, v string) {
+	a.set(k, a.truncate(v))
+}
+
+// flatten sets the attributes of a value: maps are flattened into an attribute per key,
+// prefixed by the key of the map, and other values are set as is.
+func (a *attributeLimiter) flatten(key string, val pcommon.Value, depth int) {
+	if val.Type() != pcommon.ValueTypeMap || depth == a.limits.MaxDepth {
+		a.set(key, a.raw(val))
+		return
+	}
+	val.Map().Range(func(k string, v pcommon.Value) bool {
+		a.flatten(key+"."+k, v, depth+1)
+		return true
+	})
+}
+
+// raw returns the value of an attribute that is not flattened: slices are JSON arrays,
+// and maps are JSON strings.
+func (a *attributeLimiter) raw(val pcommon.Value) any {
+	switch val.Type() {
+	case pcommon.ValueTypeStr:
+		return a.truncate(val.Str())
+	case pcommon.ValueTypeInt, pcommon.ValueTypeBool, pcommon.ValueTypeDouble:
+		return val.AsRaw()
+	case pcommon.ValueTypeSlice:
+		values := make([]any, 0, val.Slice().Len())
+		for _, v := range val.Slice().All() {
+			values = append(values, a.raw(v))
+		}
+		return values
+	}
+	return a.truncate(val.AsString())
+}
+
+// truncate truncates a string value to the maximum value length, on a UTF-8 character boundary.
+func (a *attributeLimiter) truncate(s string) string {
+	if a.limits.MaxValueLength == 0 || len(s) <= a.limits.MaxValueLength {
+		return s
+	}
+	a.truncated = true
+	cut := a.limits.MaxValueLength
+	for cut > 0 && !utf8.RuneStart(s[cut]) {
+		cut--
+	}
+	return s[:cut]
+}
@@ -0,0 +1,130 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestWithAttributeLimitsValidation(t *testing.T) {
+	_, err := newTranslatorConfig([]TranslatorOption{WithAttributeLimits(AttributeLimits{MaxDepth: -1})})
+	assert.EqualError(t, err, "invalid attribute limits: max depth must not be negative: -1")
+	_, err = newTranslatorConfig([]TranslatorOption{WithAttributeLimits(AttributeLimits{MaxAttributes: -1})})
+	assert.EqualError(t, err, "invalid attribute limits: max attributes must not be negative: -1")
+	_, err = newTranslatorConfig([]TranslatorOption{WithAttributeLimits(AttributeLimits{MaxValueLength: -1})})
+	assert.EqualError(t, err, "invalid attribute limits: max value length must not be negative: -1")
+}
+
+func TestTransformAttributeLimits(t *testing.T) {
+	newLogRecord := func(t *testing.T) plog.LogRecord {
+		lr := plog.NewLogRecord()
+		lr.SetSeverityNumber(plog.SeverityNumberInfo)
+		// Attributes are set one by one to keep their order.
+		lr.Attributes().PutEmptyMap("a").PutEmptyMap("b").PutStr("c", "deep")
+		lr.Attributes().PutStr("long", "héllo world")
+		require.NoError(t, lr.Attributes().PutEmptySlice("list").FromRaw([]any{"héllo world", int64(1), true, map[string]any{"k": "v"}}))
+		lr.Attributes().PutStr("other", "value")
+		return lr
+	}
+	newResource := func() pcommon.Resource {
+		res := pcommon.NewResource()
+		res.Attributes().PutStr("service.name", "checkout")
+		return res
+	}
+
+	tests := []struct {
+		name      string
+		limits    AttributeLimits
+		want      map[string]any
+		truncated bool
+	}{
+		{
+			name:   "default",
+			limits: DefaultAttributeLimits(),
+			want: map[string]any{
+				"a.b.c":        "deep",
+				"long":         "héllo world",
+				"list":         []any{"héllo world", int64(1), true, `{"k":"v"}`},
+				"other":        "value",
+				"service.name": "checkout",
+			},
+		},
+		{
+			name:   "max depth",
+			limits: AttributeLimits{MaxDepth: 2},
+			want: map[string]any{
+				"a.b":          `{"c":"deep"}`,
+				"long":         "héllo world",
+				"list":         []any{"héllo world", int64(1), true, `{"k":"v"}`},
+				"other":        "value",
+				"service.name": "checkout",
+			},
+		},
+		{
+			name:   "max value length",
+			limits: AttributeLimits{MaxDepth: 10, MaxValueLength: 2},
+			want: map[string]any{
+				"a.b.c": "de",
+				// Values are truncated on a character boundary.
+				"long":         "h",
+				"list":         []any{"h", int64(1), true, `{"`},
+				"other":        "va",
+				"service.name": "ch",
+			},
+			truncated: true,
+		},
+		{
+			name:   "max attributes",
+			limits: AttributeLimits{MaxDepth: 10, MaxAttributes: 3},
+			// Attributes are added in order: log attributes, then resource attributes.
+			want: map[string]any{
+				"a.b.c": "deep",
+				"long":  "héllo world",
+				"list":  []any{"héllo world", int64(1), true, `{"k":"v"}`},
+			},
+			truncated: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, err := newTranslatorConfig([]TranslatorOption{WithAttributeLimits(tt.limits)})
+			require.NoError(t, err)
+
+			got := transform(newLogRecord(t), "", "", newResource(), pcommon.NewInstrumentationScope(), cfg, zaptest.NewLogger(t))
+			// Attributes set by the translator are not limited.
+			assert.Equal(t, "info", got.AdditionalProperties[ddStatus])
+			assert.Equal(t, "9", got.AdditionalProperties[otelSeverityNumber])
+			for k, v := range tt.want {
+				assert.Equal(t, v, got.AdditionalProperties[k], k)
+			}
+			for _, k := range []string{"a.b.c", "a.b", "long", "list", "other", "service.name"} {
+				if _, ok := tt.want[k]; !ok {
+					assert.NotContains(t, got.AdditionalProperties, k)
+				}
+			}
+			if tt.truncated {
+				assert.Equal(t, true, got.AdditionalProperties[ddAttributesTruncated])
+			} else {
+				assert.NotContains(t, got.AdditionalProperties, ddAttributesTruncated)
+			}
+		})
+	}
+}
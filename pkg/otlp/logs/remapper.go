@@ -0,0 +1,139 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logs
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// remapTarget is the Datadog log field an attribute is remapped to.
+type remapTarget int
+
+const (
+	remapTargetNone remapTarget = iota
+	remapTargetMessage
+	remapTargetStatus
+	remapTargetTraceID
+	remapTargetSpanID
+	remapTargetTimestamp
+	remapTargetErrorMessage
+	remapTargetErrorStack
+	remapTargetErrorKind
+	remapTargetTags
+)
+
+func (t remapTarget) String() string {
+	switch t {
+	case remapTargetMessage:
+		return "message"
+	case remapTargetStatus:
+		return "status"
+	case remapTargetTraceID:
+		return "trace ID"
+	case remapTargetSpanID:
+		return "span ID"
+	case remapTargetTimestamp:
+		return "timestamp"
+	case remapTargetErrorMessage:
+		return "error message"
+	case remapTargetErrorStack:
+		return "error stack"
+	case remapTargetErrorKind:
+		return "error kind"
+	case remapTargetTags:
+		return "tags"
+	}
+	return "none"
+}
+
+// AttributeRemapper declares which log attributes are remapped to Datadog log fields.
+// Keys are matched case-insensitively, and remapped attributes are not sent as log attributes.
+type AttributeRemapper struct {
+	// MessageKeys are remapped to the log message.
+	MessageKeys []string
+	// StatusKeys are remapped to the log status.
+	StatusKeys []string
+	// TraceIDKeys are remapped to the trace ID. Their value is either 32 or 16 hex characters,
+	// a decimal number as set by Datadog tracers, or a W3C traceparent, which also sets the span ID.
+	// The value of `dd.trace_id` is read as a decimal number unless it is 32 characters long.
+	TraceIDKeys []string
+	// SpanIDKeys are remapped to the span ID. Their value is either 16 hex characters
+	// or a decimal number as set by Datadog tracers. The value of `dd.span_id` is always read as a decimal number.
+	SpanIDKeys []string
+	// TimestampKeys are remapped to the log timestamp. Their value is either
+	// an RFC 3339 date or a number of milliseconds since the Unix epoch.
+	TimestampKeys []string
+	// ErrorMessageKeys are remapped to the `error.message` attribute.
+	ErrorMessageKeys []string
+	// ErrorStackKeys are remapped to the `error.stack` attribute.
+	ErrorStackKeys []string
+	// ErrorKindKeys are remapped to the `error.kind` attribute.
+	ErrorKindKeys []string
+	// TagsKeys are remapped to the log tags. Their value is a comma-separated list of tags.
+	TagsKeys []string
+}
+
+// DefaultAttributeRemapper returns the remapper used by default, which follows the Datadog backend.
+func DefaultAttributeRemapper() AttributeRemapper {
+	return AttributeRemapper{
+		MessageKeys: []string{"msg", "message", "log"},
+		StatusKeys:  []string{"status", "severity", "level", "syslog.severity"},
+		TraceIDKeys: []string{"traceid", "trace_id", "contextmap.traceid", "oteltraceid", "dd.trace_id", "traceparent"},
+		SpanIDKeys:  []string{"spanid", "span_id", "contextmap.spanid", "otelspanid", "dd.span_id"},
+		TagsKeys:    []string{"ddtags"},
+	}
+}
+
+// targets returns the remap target of every key.
+func (r AttributeRemapper) targets() (map[string]remapTarget, error) {
+	targets := make(map[string]remapTarget)
+	for _, group := range []struct {
+		keys   []string
+		target remapTarget
+	}{
+		{r.MessageKeys, remapTargetMessage},
+		{r.StatusKeys, remapTargetStatus},
+		{r.TraceIDKeys, remapTargetTraceID},
+		{r.SpanIDKeys, remapTargetSpanID},
+		{r.TimestampKeys, remapTargetTimestamp},
+		{r.ErrorMessageKeys, remapTargetErrorMessage},
+		{r.ErrorStackKeys, remapTargetErrorStack},
+		{r.ErrorKindKeys, remapTargetErrorKind},
+		{r.TagsKeys, remapTargetTags},
+	} {
+		for _, key := range group.keys {
+			if key == "" {
+				return nil, errors.New("remapped attribute keys must not be empty")
+			}
+			key = strings.ToLower(key)
+			if target, ok := targets[key]; ok && target != group.target {
+				return nil, fmt.Errorf("attribute %q is remapped to both %s and %s", key, target, group.target)
+			}
+			targets[key] = group.target
+		}
+	}
+	return targets, nil
+}
+
+// defaultRemapTargets are the remap targets of DefaultAttributeRemapper.
+var defaultRemapTargets = func() map[string]remapTarget {
+	targets, err := DefaultAttributeRemapper().targets()
+	if err != nil {
+		panic(err)
+	}
+	return targets
+}()
@@ -0,0 +1,111 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestWithAttributeRemapperValidation(t *testing.T) {
+	_, err := newTranslatorConfig([]TranslatorOption{WithAttributeRemapper(AttributeRemapper{
+		MessageKeys: []string{"msg"},
+		StatusKeys:  []string{"MSG"},
+	})})
+	assert.EqualError(t, err, `invalid attribute remapper: attribute "msg" is remapped to both message and status`)
+
+	_, err = newTranslatorConfig([]TranslatorOption{WithAttributeRemapper(AttributeRemapper{
+		TagsKeys: []string{""},
+	})})
+	assert.EqualError(t, err, "invalid attribute remapper: remapped attribute keys must not be empty")
+}
+
+func TestTransformAttributeRemapper(t *testing.T) {
+	remapper := DefaultAttributeRemapper()
+	remapper.MessageKeys = append(remapper.MessageKeys, "@m")
+	remapper.StatusKeys = append(remapper.StatusKeys, "@l")
+	remapper.TimestampKeys = []string{"@t"}
+	remapper.ErrorMessageKeys = []string{"exception.message"}
+	remapper.ErrorStackKeys = []string{"exception.stacktrace"}
+	remapper.ErrorKindKeys = []string{"exception.type"}
+	remapper.TagsKeys = []string{"tags"}
+	cfg, err := newTranslatorConfig([]TranslatorOption{WithAttributeRemapper(remapper)})
+	require.NoError(t, err)
+
+	lr := plog.NewLogRecord()
+	lr.SetTimestamp(pcommon.Timestamp(1_000_000_000))
+	require.NoError(t, lr.Attributes().FromRaw(map[string]any{
+		"@m":                   "user logged in",
+		"@l":                   "Warning",
+		"@t":                   "2024-05-06T07:08:09.123Z",
+		"exception.message":    "boom",
+		"exception.stacktrace": "at main()",
+		"exception.type":       "RuntimeError",
+		"tags":                 "team:logs",
+		"ddtags":               "not:remapped",
+	}))
+
+	got := transform(lr, "", "", pcommon.NewResource(), pcommon.NewInstrumentationScope(), cfg, zaptest.NewLogger(t))
+	assert.Equal(t, "user logged in", got.Message)
+	assert.Equal(t, "team:logs", got.GetDdtags())
+	for k, v := range map[string]any{
+		"status":        "Warning",
+		"error.message": "boom",
+		"error.stack":   "at main()",
+		"error.kind":    "RuntimeError",
+		"ddtags":        "not:remapped",
+		ddTimestamp:     "2024-05-06T07:08:09.123Z",
+		otelTimestamp:   "1000000000",
+	} {
+		assert.Equal(t, v, got.AdditionalProperties[k], k)
+	}
+	for _, k := range []string{"@m", "@l", "@t", "exception.message", "exception.stacktrace", "exception.type", "tags"} {
+		assert.NotContains(t, got.AdditionalProperties, k)
+	}
+}
+
+func TestParseTimestamp(t *testing.T) {
+	tests := []struct {
+		name  string
+		value func(pcommon.Value)
+		want  string
+		err   bool
+	}{
+		{name: "RFC 3339", value: func(v pcommon.Value) { v.SetStr("2024-05-06T09:08:09.123+02:00") }, want: "2024-05-06T07:08:09.123Z"},
+		{name: "milliseconds", value: func(v pcommon.Value) { v.SetInt(1714979289123) }, want: "2024-05-06T07:08:09.123Z"},
+		{name: "milliseconds string", value: func(v pcommon.Value) { v.SetStr("1714979289123") }, want: "2024-05-06T07:08:09.123Z"},
+		{name: "milliseconds double", value: func(v pcommon.Value) { v.SetDouble(1714979289123) }, want: "2024-05-06T07:08:09.123Z"},
+		{name: "invalid", value: func(v pcommon.Value) { v.SetStr("yesterday") }, err: true},
+		{name: "unsupported type", value: func(v pcommon.Value) { v.SetBool(true) }, err: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := pcommon.NewValueEmpty()
+			tt.value(v)
+			ts, err := parseTimestamp(v)
+			if tt.err {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, ts.UTC().Format(ddTimestampFormat))
+		})
+	}
+}
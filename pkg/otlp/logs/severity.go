@@ -0,0 +1,155 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logs
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"go.opentelemetry.io/collector/pdata/plog"
+)
+
+// SeverityMapping maps severity texts and numbers to Datadog log statuses.
+type SeverityMapping struct {
+	// Texts maps severity texts to statuses. Texts are matched case-insensitively,
+	// against both the log severity text and the remapped status attributes.
+	// Texts that are not in the mapping are sent as is.
+	Texts map[string]string
+	// Numbers maps OpenTelemetry severity numbers to statuses.
+	// Numbers that are not in the mapping are mapped by severity number range.
+	Numbers map[plog.SeverityNumber]string
+}
+
+// SyslogSeverityMapping returns the mapping of the syslog severity keywords (RFC 5424).
+func SyslogSeverityMapping() SeverityMapping {
+	return SeverityMapping{Texts: map[string]string{
+		"emerg":         logLevelEmergency,
+		"emergency":     logLevelEmergency,
+		"panic":         logLevelEmergency,
+		"alert":         logLevelAlert,
+		"crit":          logLevelCritical,
+		"critical":      logLevelCritical,
+		"err":           logLevelError,
+		"error":         logLevelError,
+		"warning":       logLevelWarn,
+		"warn":          logLevelWarn,
+		"notice":        logLevelNotice,
+		"info":          logLevelInfo,
+		"informational": logLevelInfo,
+		"debug":         logLevelDebug,
+	}}
+}
+
+// PythonSeverityMapping returns the mapping of the Python logging levels.
+func PythonSeverityMapping() SeverityMapping {
+	return SeverityMapping{Texts: map[string]string{
+		"critical": logLevelCritical,
+		// FATAL is an alias of CRITICAL.
+		"fatal":   logLevelCritical,
+		"error":   logLevelError,
+		"warning": logLevelWarn,
+		"warn":    logLevelWarn,
+		"info":    logLevelInfo,
+		"debug":   logLevelDebug,
+	}}
+}
+
+// Log4jSeverityMapping returns the mapping of the Log4j (and Logback) levels.
+func Log4jSeverityMapping() SeverityMapping {
+	return SeverityMapping{Texts: map[string]string{
+		"fatal": logLevelFatal,
+		"error": logLevelError,
+		"warn":  logLevelWarn,
+		"info":  logLevelInfo,
+		"debug": logLevelDebug,
+		"trace": logLevelTrace,
+	}}
+}
+
+// DotNetSeverityMapping returns the mapping of the .NET log levels, from both
+// Microsoft.Extensions.Logging and Serilog.
+func DotNetSeverityMapping() SeverityMapping {
+	return SeverityMapping{Texts: map[string]string{
+		"critical":    logLevelCritical,
+		"fatal":       logLevelFatal,
+		"error":       logLevelError,
+		"warning":     logLevelWarn,
+		"information": logLevelInfo,
+		"debug":       logLevelDebug,
+		"trace":       logLevelTrace,
+		"verbose":     logLevelTrace,
+	}}
+}
+
+// severityTable is the merged severity mappings of a Translator.
+type severityTable struct {
+	// texts maps lowercase severity texts to statuses.
+	texts   map[string]string
+	numbers map[plog.SeverityNumber]string
+}
+
+// newSeverityTable merges the given mappings. Later mappings override earlier ones.
+func newSeverityTable(mappings []SeverityMapping) (*severityTable, error) {
+	if len(mappings) == 0 {
+		return nil, errors.New("at least one severity mapping is required")
+	}
+	t := &severityTable{
+		texts:   make(map[string]string),
+		numbers: make(map[plog.SeverityNumber]string),
+	}
+	for _, mapping := range mappings {
+		for text, status := range mapping.Texts {
+			if text == "" {
+				return nil, errors.New("severity texts must not be empty")
+			}
+			if status == "" {
+				return nil, fmt.Errorf("severity text %q is mapped to an empty status", text)
+			}
+			t.texts[strings.ToLower(text)] = status
+		}
+		for number, status := range mapping.Numbers {
+			if number < plog.SeverityNumberTrace || number > plog.SeverityNumberFatal4 {
+				return nil, fmt.Errorf("severity number %d is out of range", number)
+			}
+			if status == "" {
+				return nil, fmt.Errorf("severity number %d is mapped to an empty status", number)
+			}
+			t.numbers[number] = status
+		}
+	}
+	return t, nil
+}
+
+// statusFromText returns the status of a severity text. A nil table keeps the text as is.
+func (t *severityTable) statusFromText(text string) string {
+	if t == nil {
+		return text
+	}
+	if status, ok := t.texts[strings.ToLower(text)]; ok {
+		return status
+	}
+	return text
+}
+
+// statusFromNumber returns the status of a severity number.
+func (t *severityTable) statusFromNumber(severity plog.SeverityNumber) string {
+	if t != nil {
+		if status, ok := t.numbers[severity]; ok {
+			return status
+		}
+	}
+	return statusFromSeverityNumber(severity)
+}
@@ -0,0 +1,164 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestWithSeverityMappingValidation(t *testing.T) {
+	tests := []struct {
+		name     string
+		mappings []SeverityMapping
+		err      string
+	}{
+		{
+			name: "no mappings",
+			err:  "invalid severity mapping: at least one severity mapping is required",
+		},
+		{
+			name:     "empty text",
+			mappings: []SeverityMapping{{Texts: map[string]string{"": "info"}}},
+			err:      "invalid severity mapping: severity texts must not be empty",
+		},
+		{
+			name:     "empty text status",
+			mappings: []SeverityMapping{{Texts: map[string]string{"notice": ""}}},
+			err:      `invalid severity mapping: severity text "notice" is mapped to an empty status`,
+		},
+		{
+			name:     "number out of range",
+			mappings: []SeverityMapping{{Numbers: map[plog.SeverityNumber]string{25: "info"}}},
+			err:      "invalid severity mapping: severity number 25 is out of range",
+		},
+		{
+			name:     "empty number status",
+			mappings: []SeverityMapping{{Numbers: map[plog.SeverityNumber]string{plog.SeverityNumberInfo: ""}}},
+			err:      "invalid severity mapping: severity number 9 is mapped to an empty status",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := newTranslatorConfig([]TranslatorOption{WithSeverityMapping(tt.mappings...)})
+			assert.EqualError(t, err, tt.err)
+		})
+	}
+}
+
+func TestTransformSeverityMapping(t *testing.T) {
+	tests := []struct {
+		name     string
+		mappings []SeverityMapping
+		text     string
+		number   plog.SeverityNumber
+		attrs    map[string]any
+		want     string
+	}{
+		{
+			name:     "syslog",
+			mappings: []SeverityMapping{SyslogSeverityMapping()},
+			text:     "crit",
+			want:     logLevelCritical,
+		},
+		{
+			name:     "syslog attribute",
+			mappings: []SeverityMapping{SyslogSeverityMapping()},
+			attrs:    map[string]any{"syslog.severity": "emerg"},
+			want:     logLevelEmergency,
+		},
+		{
+			name:     "Python",
+			mappings: []SeverityMapping{PythonSeverityMapping()},
+			attrs:    map[string]any{"level": "WARNING"},
+			want:     logLevelWarn,
+		},
+		{
+			name:     "Log4j",
+			mappings: []SeverityMapping{Log4jSeverityMapping()},
+			text:     "TRACE",
+			want:     logLevelTrace,
+		},
+		{
+			name:     ".NET",
+			mappings: []SeverityMapping{DotNetSeverityMapping()},
+			text:     "Information",
+			want:     logLevelInfo,
+		},
+		{
+			name:     "unknown text is kept",
+			mappings: []SeverityMapping{SyslogSeverityMapping()},
+			text:     "Custom",
+			number:   plog.SeverityNumberWarn,
+			want:     "Custom",
+		},
+		{
+			name:     "number range",
+			mappings: []SeverityMapping{SyslogSeverityMapping()},
+			number:   plog.SeverityNumberWarn2,
+			want:     logLevelWarn,
+		},
+		{
+			name: "overrides",
+			mappings: []SeverityMapping{
+				SyslogSeverityMapping(),
+				{
+					Texts:   map[string]string{"NOTICE": logLevelInfo},
+					Numbers: map[plog.SeverityNumber]string{plog.SeverityNumberFatal4: logLevelEmergency},
+				},
+			},
+			text: "notice",
+			want: logLevelInfo,
+		},
+		{
+			name: "number override",
+			mappings: []SeverityMapping{
+				{Numbers: map[plog.SeverityNumber]string{plog.SeverityNumberFatal4: logLevelEmergency}},
+			},
+			number: plog.SeverityNumberFatal4,
+			want:   logLevelEmergency,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, err := newTranslatorConfig([]TranslatorOption{WithSeverityMapping(tt.mappings...)})
+			require.NoError(t, err)
+
+			lr := plog.NewLogRecord()
+			lr.SetSeverityText(tt.text)
+			lr.SetSeverityNumber(tt.number)
+			require.NoError(t, lr.Attributes().FromRaw(tt.attrs))
+
+			got := transform(lr, "", "", pcommon.NewResource(), pcommon.NewInstrumentationScope(), cfg, zaptest.NewLogger(t))
+			assert.Equal(t, tt.want, got.AdditionalProperties[ddStatus])
+			if tt.text != "" {
+				// The original severity text is kept.
+				assert.Equal(t, tt.text, got.AdditionalProperties[otelSeverityText])
+			}
+		})
+	}
+
+	t.Run("disabled", func(t *testing.T) {
+		lr := plog.NewLogRecord()
+		lr.SetSeverityText("crit")
+		got := transform(lr, "", "", pcommon.NewResource(), pcommon.NewInstrumentationScope(), defaultTranslatorConfig(), zaptest.NewLogger(t))
+		assert.Equal(t, "crit", got.AdditionalProperties[ddStatus])
+	})
+}
@@ -17,14 +17,18 @@ package logs
 import (
 	"encoding/binary"
 	"encoding/hex"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"math/big"
 	"strconv"
 	"strings"
+	"time"
 
-	"github.com/DataDog/datadog-agent/pkg/opentelemetry-mapping-go/otlp/attributes"
-	"github.com/DataDog/datadog-agent/pkg/opentelemetry-mapping-go/otlp/attributes/source"
 	"github.com/DataDog/datadog-api-client-go/v2/api/datadog"
 	"github.com/DataDog/datadog-api-client-go/v2/api/datadogV2"
+	"github.com/DataDog/opentelemetry-mapping-go/pkg/otlp/attributes"
+	"github.com/DataDog/opentelemetry-mapping-go/pkg/otlp/attributes/source"
 	"go.opentelemetry.io/collector/pdata/pcommon"
 	"go.opentelemetry.io/collector/pdata/plog"
 	semconv16 "go.opentelemetry.io/otel/semconv/v1.6.1"
@@ -49,6 +53,14 @@ const (
 	ddSpanID    = ddNamespace + ".span_id"
 	ddStatus    = "status"
 	ddTimestamp = "@timestamp"
+	// ddTraceIDHigh holds the high 64 bits of 128-bit trace IDs as 16 hex characters.
+	ddTraceIDHigh = "_dd.p.tid"
+
+	ddErrorMessage = "error.message"
+	ddErrorStack   = "error.stack"
+	ddErrorKind    = "error.kind"
+
+	ddTimestampFormat = "2006-01-02T15:04:05.000Z07:00"
 )
 
 const (
@@ -58,6 +70,12 @@ const (
 	logLevelWarn  = "warn"
 	logLevelError = "error"
 	logLevelFatal = "fatal"
+
+	// Datadog log statuses that have no OpenTelemetry severity number range.
+	logLevelNotice    = "notice"
+	logLevelCritical  = "critical"
+	logLevelAlert     = "alert"
+	logLevelEmergency = "emergency"
 )
 
 // Transform converts the log record in lr, which came in with the resource in res to a Datadog log item.
@@ -65,10 +83,10 @@ const (
 // Deprecated: use Translator instead.
 func Transform(lr plog.LogRecord, res pcommon.Resource, logger *zap.Logger) datadogV2.HTTPLogItem {
 	host, service := extractHostNameAndServiceName(res.Attributes(), lr.Attributes())
-	return transform(lr, host, service, res, pcommon.NewInstrumentationScope(), logger)
+	return transform(lr, host, service, res, pcommon.NewInstrumentationScope(), defaultTranslatorConfig(), logger)
 }
 
-func transform(lr plog.LogRecord, host, service string, res pcommon.Resource, scope pcommon.InstrumentationScope, logger *zap.Logger) datadogV2.HTTPLogItem {
+func transform(lr plog.LogRecord, host, service string, res pcommon.Resource, scope pcommon.InstrumentationScope, cfg translatorConfig, logger *zap.Logger) datadogV2.HTTPLogItem {
 	l := datadogV2.HTTPLogItem{
 		AdditionalProperties: make(map[string]interface{}),
 	}
@@ -82,15 +100,30 @@ func transform(lr plog.LogRecord, host, service string, res pcommon.Resource, sc
 	// we need to set log attributes as AdditionalProperties
 	// AdditionalProperties are treated as Datadog Log Attributes
 	var status string
-	lr.Attributes().Range(func(k string, v pcommon.Value) bool {
-		switch strings.ToLower(k) {
-		// set of remapping are taken from Datadog Backend
-		case "msg", "message", "log":
+	var timestamp time.Time
+	attrs := attributeLimiter{limits: cfg.attributeLimits, props: l.AdditionalProperties}
+	remapAttribute := func(k string, v pcommon.Value) bool {
+		switch cfg.remapTargets[strings.ToLower(k)] {
+		case remapTargetMessage:
 			l.Message = v.AsString()
-		case "status", "severity", "level", "syslog.severity":
+		case remapTargetStatus:
 			status = v.AsString()
-		case "traceid", "trace_id", "contextmap.traceid", "oteltraceid":
-			traceID, err := decodeTraceID(v.AsString())
+		case remapTargetTraceID:
+			// A W3C traceparent holds both the trace and the span IDs.
+			if traceID, spanID, ok := decodeTraceparent(v.AsString()); ok {
+				if _, ok := l.AdditionalProperties[ddTraceID]; !ok {
+					setTraceID(l.AdditionalProperties, traceID, cfg.traceID128Bit)
+				}
+				if _, ok := l.AdditionalProperties[ddSpanID]; !ok {
+					setSpanID(l.AdditionalProperties, spanID)
+				}
+				break
+			}
+			decode := decodeTraceID
+			if strings.EqualFold(k, ddTraceID) {
+				decode = decodeDatadogTraceID
+			}
+			traceID, err := decode(v.AsString())
 			if err != nil {
 				logger.Warn("failed to decode trace id",
 					zap.String("trace_id", v.AsString()),
@@ -98,11 +131,14 @@ func transform(lr plog.LogRecord, host, service string, res pcommon.Resource, sc
 				break
 			}
 			if _, ok := l.AdditionalProperties[ddTraceID]; !ok {
-				l.AdditionalProperties[ddTraceID] = strconv.FormatUint(traceIDToUint64(traceID), 10)
-				l.AdditionalProperties[otelTraceID] = v.AsString()
+				setTraceID(l.AdditionalProperties, traceID, cfg.traceID128Bit)
 			}
-		case "spanid", "span_id", "contextmap.spanid", "otelspanid":
-			spanID, err := decodeSpanID(v.AsString())
+		case remapTargetSpanID:
+			decode := decodeSpanID
+			if strings.EqualFold(k, ddSpanID) {
+				decode = decodeDatadogSpanID
+			}
+			spanID, err := decode(v.AsString())
 			if err != nil {
 				logger.Warn("failed to decode span id",
 					zap.String("span_id", v.AsString()),
@@ -110,54 +146,79 @@ func transform(lr plog.LogRecord, host, service string, res pcommon.Resource, sc
 				break
 			}
 			if _, ok := l.AdditionalProperties[ddSpanID]; !ok {
-				l.AdditionalProperties[ddSpanID] = strconv.FormatUint(spanIDToUint64(spanID), 10)
-				l.AdditionalProperties[otelSpanID] = v.AsString()
+				setSpanID(l.AdditionalProperties, spanID)
+			}
+		case remapTargetTimestamp:
+			ts, err := parseTimestamp(v)
+			if err != nil {
+				logger.Warn("failed to parse timestamp",
+					zap.String("timestamp", v.AsString()),
+					zap.Error(err))
+				attrs.setString(k, v.AsString())
+				break
 			}
-		case "ddtags":
+			timestamp = ts
+		case remapTargetErrorMessage:
+			l.AdditionalProperties[ddErrorMessage] = v.AsString()
+		case remapTargetErrorStack:
+			l.AdditionalProperties[ddErrorStack] = v.AsString()
+		case remapTargetErrorKind:
+			l.AdditionalProperties[ddErrorKind] = v.AsString()
+		case remapTargetTags:
 			var tags = append(attributes.TagsFromAttributes(res.Attributes()), v.AsString())
 			tagStr := strings.Join(tags, ",")
 			l.Ddtags = datadog.PtrString(tagStr)
 		default:
-			m := flattenAttribute(k, v, 1)
-			for k, v := range m {
-				l.AdditionalProperties[k] = v
-			}
+			attrs.flatten(k, v, 1)
 		}
 		return true
-	})
+	}
+	var hasStructuredBody bool
+	if cfg.structuredBodyParsing {
+		// Body keys are remapped first, so that log attributes take precedence.
+		var body pcommon.Map
+		if body, hasStructuredBody = structuredBody(lr.Body()); hasStructuredBody {
+			body.Range(remapAttribute)
+		}
+	}
+	lr.Attributes().Range(remapAttribute)
 	res.Attributes().Range(func(k string, v pcommon.Value) bool {
 		// "hostname" and "service" are reserved keywords in HTTPLogItem
 		// Prefix the keys so they aren't overwritten when marshalling
 		if k == "hostname" || k == "service" {
-			l.AdditionalProperties["otel."+k] = v.AsString()
+			attrs.setString("otel."+k, v.AsString())
 		} else {
-			l.AdditionalProperties[k] = v.AsString()
+			attrs.setString(k, v.AsString())
 		}
 		return true
 	})
 	for k, v := range scope.Attributes().Range {
-		l.AdditionalProperties[k] = v.AsString()
+		attrs.setString(k, v.AsString())
+	}
+	if attrs.truncated {
+		l.AdditionalProperties[ddAttributesTruncated] = true
 	}
 	if traceID := lr.TraceID(); !traceID.IsEmpty() {
-		l.AdditionalProperties[ddTraceID] = strconv.FormatUint(traceIDToUint64(traceID), 10)
-		l.AdditionalProperties[otelTraceID] = hex.EncodeToString(traceID[:])
+		setTraceID(l.AdditionalProperties, traceID, cfg.traceID128Bit)
 	}
 	if spanID := lr.SpanID(); !spanID.IsEmpty() {
-		l.AdditionalProperties[ddSpanID] = strconv.FormatUint(spanIDToUint64(spanID), 10)
-		l.AdditionalProperties[otelSpanID] = hex.EncodeToString(spanID[:])
+		setSpanID(l.AdditionalProperties, spanID)
 	}
 
 	// we want to use the serverity that client has set on the log and let Datadog backend
 	// decide the appropriate level
+	if status != "" {
+		status = cfg.severityTable.statusFromText(status)
+	}
 	if lr.SeverityText() != "" {
 		if status == "" {
-			status = lr.SeverityText()
+			status = cfg.severityTable.statusFromText(lr.SeverityText())
 		}
 		l.AdditionalProperties[otelSeverityText] = lr.SeverityText()
 	}
 	if lr.SeverityNumber() != 0 {
 		if status == "" {
-			status = statusFromSeverityNumber(lr.SeverityNumber())
+			status = cfg.severityTable.statusFromNumber(lr.SeverityNumber())
 		}
 		l.AdditionalProperties[otelSeverityNumber] = strconv.Itoa(int(lr.SeverityNumber()))
 	}
@@ -166,9 +227,13 @@ func transform(lr plog.LogRecord, host, service string, res pcommon.Resource, sc
 	if lr.Timestamp() != 0 {
 		// we are retaining the nano second precision in this property
 		l.AdditionalProperties[otelTimestamp] = strconv.FormatInt(lr.Timestamp().AsTime().UnixNano(), 10)
-		l.AdditionalProperties[ddTimestamp] = lr.Timestamp().AsTime().Format("2006-01-02T15:04:05.000Z07:00")
+		l.AdditionalProperties[ddTimestamp] = lr.Timestamp().AsTime().Format(ddTimestampFormat)
 	}
-	if l.Message == "" {
+	if !timestamp.IsZero() {
+		// a remapped timestamp takes precedence over the timestamp of the log record
+		l.AdditionalProperties[ddTimestamp] = timestamp.Format(ddTimestampFormat)
+	}
+	if l.Message == "" && !hasStructuredBody {
 		// set the Message to the Body in case it wasn't already parsed as part of the attributes
 		l.Message = lr.Body().AsString()
 	}
@@ -182,31 +247,67 @@ func transform(lr plog.LogRecord, host, service string, res pcommon.Resource, sc
 	return l
 }
 
-func flattenAttribute(key string, val pcommon.Value, depth int) map[string]any {
-	result := make(map[string]any)
-
-	if val.Type() != pcommon.ValueTypeMap || depth == 10 {
-		if val.Type() == pcommon.ValueTypeStr ||
-			val.Type() == pcommon.ValueTypeInt ||
-			val.Type() == pcommon.ValueTypeBool ||
-			val.Type() == pcommon.ValueTypeDouble {
-			result[key] = val.AsRaw()
-		} else {
-			result[key] = val.AsString()
+// parseTimestamp parses an RFC 3339 date, or a number of milliseconds since the Unix epoch.
+func parseTimestamp(v pcommon.Value) (time.Time, error) {
+	switch v.Type() {
+	case pcommon.ValueTypeInt:
+		return time.UnixMilli(v.Int()).UTC(), nil
+	case pcommon.ValueTypeDouble:
+		return time.UnixMilli(int64(v.Double())).UTC(), nil
+	case pcommon.ValueTypeStr:
+		if ms, err := strconv.ParseInt(v.Str(), 10, 64); err == nil {
+			return time.UnixMilli(ms).UTC(), nil
 		}
-		return result
+		return time.Parse(time.RFC3339Nano, v.Str())
 	}
+	return time.Time{}, fmt.Errorf("unsupported timestamp type %s", v.Type())
+}
 
-	val.Map().Range(func(k string, v pcommon.Value) bool {
-		newKey := key + "." + k
-		nestedResult := flattenAttribute(newKey, v, depth+1)
-		for nk, nv := range nestedResult {
-			result[nk] = nv
+// structuredBody returns the attributes held by a map body, or by a string body holding a JSON object.
+func structuredBody(body pcommon.Value) (pcommon.Map, bool) {
+	switch body.Type() {
+	case pcommon.ValueTypeMap:
+		return body.Map(), true
+	case pcommon.ValueTypeStr:
+		str := strings.TrimSpace(body.Str())
+		if !strings.HasPrefix(str, "{") {
+			return pcommon.Map{}, false
 		}
-		return true
-	})
+		decoder := json.NewDecoder(strings.NewReader(str))
+		// Keep integers, such as Datadog trace IDs, exact.
+		decoder.UseNumber()
+		var raw map[string]any
+		if err := decoder.Decode(&raw); err != nil || decoder.More() {
+			return pcommon.Map{}, false
+		}
+		m := pcommon.NewMap()
+		if err := m.FromRaw(fromJSONNumbers(raw).(map[string]any)); err != nil {
+			return pcommon.Map{}, false
+		}
+		return m, true
+	}
+	return pcommon.Map{}, false
+}
 
-	return result
+// fromJSONNumbers converts the json.Number values decoded from a JSON document into int64 or float64 values.
+func fromJSONNumbers(v any) any {
+	switch v := v.(type) {
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return i
+		}
+		f, _ := v.Float64()
+		return f
+	case map[string]any:
+		for k, val := range v {
+			v[k] = fromJSONNumbers(val)
+		}
+	case []any:
+		for i, val := range v {
+			v[i] = fromJSONNumbers(val)
+		}
+	}
+	return v
 }
 
 func extractHostNameAndServiceName(resourceAttrs pcommon.Map, logAttrs pcommon.Map) (host string, service string) {
@@ -233,28 +334,109 @@ func extractHostNameAndServiceName(resourceAttrs pcommon.Map, logAttrs pcommon.M
 	return host, service
 }
 
+// decodeTraceID decodes a trace ID from any of the formats found in logs:
+//   - 32 hex characters, as used by OpenTelemetry,
+//   - a decimal number of at most 128 bits, as used by Datadog,
+//   - 16 hex characters, for 64-bit trace IDs.
+//
+// 64-bit trace IDs have their high 64 bits set to zero. A string of 16 digits is both a valid decimal
+// and a valid hex ID: like decodeDatadogTraceID, it is read as a decimal number, since Datadog tracers
+// send 64-bit trace IDs in decimal. 32 characters are always read as hex first.
 func decodeTraceID(traceIDStr string) (pcommon.TraceID, error) {
 	var id pcommon.TraceID
-	if hex.DecodedLen(len(traceIDStr)) != len(id) {
-		return pcommon.TraceID{}, errors.New("trace ids must be 32 hex characters")
+	if len(traceIDStr) == hex.EncodedLen(len(id)) {
+		if _, err := hex.Decode(id[:], []byte(traceIDStr)); err == nil {
+			return id, nil
+		}
 	}
-	_, err := hex.Decode(id[:], []byte(traceIDStr))
-	if err != nil {
-		return pcommon.TraceID{}, err
+	if n, ok := decodeDecimalID(traceIDStr, 128); ok {
+		n.FillBytes(id[:])
+		return id, nil
+	}
+	if len(traceIDStr) == hex.EncodedLen(len(id)/2) {
+		if _, err := hex.Decode(id[len(id)/2:], []byte(traceIDStr)); err != nil {
+			return pcommon.TraceID{}, err
+		}
+		return id, nil
 	}
-	return id, nil
+	return pcommon.TraceID{}, errors.New("trace ids must be 32 or 16 hex characters, or a 128-bit decimal number")
 }
 
+// decodeSpanID decodes a span ID, either as 16 hex characters or as a decimal number, as used by Datadog.
 func decodeSpanID(spanIDStr string) (pcommon.SpanID, error) {
 	var id pcommon.SpanID
-	if hex.DecodedLen(len(spanIDStr)) != len(id) {
-		return pcommon.SpanID{}, errors.New("span ids must be 16 hex characters")
+	if len(spanIDStr) == hex.EncodedLen(len(id)) {
+		if _, err := hex.Decode(id[:], []byte(spanIDStr)); err != nil {
+			return pcommon.SpanID{}, err
+		}
+		return id, nil
+	}
+	if n, ok := decodeDecimalID(spanIDStr, 64); ok {
+		n.FillBytes(id[:])
+		return id, nil
+	}
+	return pcommon.SpanID{}, errors.New("span ids must be 16 hex characters or a 64-bit decimal number")
+}
+
+// decodeDatadogTraceID decodes a trace ID set by a Datadog tracer, which is either
+// a decimal number or, for 128-bit trace IDs, 32 hex characters.
+// Unlike decodeTraceID, 16 hex characters are not a valid trace ID.
+func decodeDatadogTraceID(traceIDStr string) (pcommon.TraceID, error) {
+	var id pcommon.TraceID
+	if len(traceIDStr) == hex.EncodedLen(len(id)) {
+		if _, err := hex.Decode(id[:], []byte(traceIDStr)); err == nil {
+			return id, nil
+		}
+	}
+	if n, ok := decodeDecimalID(traceIDStr, 128); ok {
+		n.FillBytes(id[:])
+		return id, nil
+	}
+	return pcommon.TraceID{}, errors.New("datadog trace ids must be 32 hex characters or a 128-bit decimal number")
+}
+
+// decodeDatadogSpanID decodes a span ID set by a Datadog tracer, which is a decimal number.
+func decodeDatadogSpanID(spanIDStr string) (pcommon.SpanID, error) {
+	var id pcommon.SpanID
+	if n, ok := decodeDecimalID(spanIDStr, 64); ok {
+		n.FillBytes(id[:])
+		return id, nil
 	}
-	_, err := hex.Decode(id[:], []byte(spanIDStr))
-	if err != nil {
-		return pcommon.SpanID{}, err
+	return pcommon.SpanID{}, errors.New("datadog span ids must be a 64-bit decimal number")
+}
+
+// decodeDecimalID decodes an ID from a decimal number of at most bits bits.
+func decodeDecimalID(idStr string, bits int) (*big.Int, bool) {
+	if idStr == "" || strings.TrimLeft(idStr, "0123456789") != "" {
+		return nil, false
+	}
+	n, ok := new(big.Int).SetString(idStr, 10)
+	if !ok || n.BitLen() > bits {
+		return nil, false
+	}
+	return n, true
+}
+
+// decodeTraceparent decodes the trace and span IDs of a W3C traceparent, e.g.
+// 00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01.
+func decodeTraceparent(traceparent string) (pcommon.TraceID, pcommon.SpanID, bool) {
+	parts := strings.Split(traceparent, "-")
+	// Future versions may have more fields.
+	if len(parts) < 4 || len(parts[0]) != 2 || parts[0] == "ff" || len(parts[3]) != 2 || (parts[0] == "00" && len(parts) != 4) {
+		return pcommon.TraceID{}, pcommon.SpanID{}, false
+	}
+	var traceID pcommon.TraceID
+	var spanID pcommon.SpanID
+	if len(parts[1]) != hex.EncodedLen(len(traceID)) || len(parts[2]) != hex.EncodedLen(len(spanID)) {
+		return pcommon.TraceID{}, pcommon.SpanID{}, false
+	}
+	if _, err := hex.Decode(traceID[:], []byte(parts[1])); err != nil {
+		return pcommon.TraceID{}, pcommon.SpanID{}, false
 	}
-	return id, nil
+	if _, err := hex.Decode(spanID[:], []byte(parts[2])); err != nil {
+		return pcommon.TraceID{}, pcommon.SpanID{}, false
+	}
+	return traceID, spanID, true
 }
 
 // traceIDToUint64 converts 128bit traceId to 64 bit uint64
@@ -262,6 +444,29 @@ func traceIDToUint64(b [16]byte) uint64 {
 	return binary.BigEndian.Uint64(b[len(b)-8:])
 }
 
+// traceIDHighToUint64 returns the high 64 bits of a 128bit traceId
+func traceIDHighToUint64(b [16]byte) uint64 {
+	return binary.BigEndian.Uint64(b[:8])
+}
+
+// setSpanID sets the Datadog and OpenTelemetry span ID attributes of a log.
+func setSpanID(props map[string]any, spanID pcommon.SpanID) {
+	props[ddSpanID] = strconv.FormatUint(spanIDToUint64(spanID), 10)
+	props[otelSpanID] = hex.EncodeToString(spanID[:])
+}
+
+// setTraceID sets the Datadog and OpenTelemetry trace ID attributes of a log.
+// With traceID128Bit, the high 64 bits of the trace ID are also set in the format used by Datadog.
+func setTraceID(props map[string]any, traceID pcommon.TraceID, traceID128Bit bool) {
+	props[ddTraceID] = strconv.FormatUint(traceIDToUint64(traceID), 10)
+	props[otelTraceID] = hex.EncodeToString(traceID[:])
+	if high := traceIDHighToUint64(traceID); traceID128Bit && high != 0 {
+		props[ddTraceIDHigh] = fmt.Sprintf("%016x", high)
+	} else {
+		delete(props, ddTraceIDHigh)
+	}
+}
+
 // spanIDToUint64 converts byte array to uint64
 func spanIDToUint64(b [8]byte) uint64 {
 	return binary.BigEndian.Uint64(b[:])
@@ -20,18 +20,24 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
 	"testing"
+	"time"
 
-	"github.com/DataDog/datadog-agent/pkg/opentelemetry-mapping-go/otlp/attributes"
 	"github.com/DataDog/datadog-api-client-go/v2/api/datadog"
 	"github.com/DataDog/datadog-api-client-go/v2/api/datadogV2"
+	"github.com/DataDog/opentelemetry-mapping-go/pkg/otlp/attributes"
+	"github.com/DataDog/opentelemetry-mapping-go/pkg/otlp/rum"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/collector/component/componenttest"
 	"go.opentelemetry.io/collector/pdata/pcommon"
 	"go.opentelemetry.io/collector/pdata/plog"
 	semconv16 "go.opentelemetry.io/otel/semconv/v1.6.1"
+	"go.uber.org/zap"
 	"go.uber.org/zap/zaptest"
+	"go.uber.org/zap/zaptest/observer"
 )
 
 type translatorTestCase struct {
@@ -305,6 +311,9 @@ func generateTranslatorTestCases(traceID [16]byte, spanID [8]byte, ddTr uint64,
 					"status":           "debug",
 					otelSeverityNumber: "5",
 					"service.name":     "otlp_col",
+					// The decimal span ID is valid, unlike the trace ID.
+					ddSpanID:   "2023675201651514964",
+					otelSpanID: "1c1589dfbf349e54",
 				},
 			},
 		},
@@ -767,6 +776,458 @@ func TestTranslatorWithRUMRouting(t *testing.T) {
 	}
 }
 
+func TestTranslatorRUMForwardingErrors(t *testing.T) {
+	var attempts atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		// The first event is rejected, the second one is accepted after a retry.
+		switch attempts.Add(1) {
+		case 1:
+			w.WriteHeader(http.StatusBadRequest)
+		case 2:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		default:
+			w.WriteHeader(http.StatusAccepted)
+		}
+	}))
+	defer server.Close()
+
+	set := componenttest.NewNopTelemetrySettings()
+	core, observed := observer.New(zap.ErrorLevel)
+	set.Logger = zap.New(core)
+	attributesTranslator, err := attributes.NewTranslator(set)
+	require.NoError(t, err)
+	translator, err := NewTranslatorWithHTTPClient(set, attributesTranslator, "test", server.Client(), WithRUMForwarderConfig(rum.ForwarderConfig{
+		Workers:        1,
+		MaxRetries:     1,
+		InitialBackoff: time.Millisecond,
+		MaxBackoff:     time.Millisecond,
+	}))
+	require.NoError(t, err)
+
+	logs := plog.NewLogs()
+	lrs := logs.ResourceLogs().AppendEmpty().ScopeLogs().AppendEmpty().LogRecords()
+	lrs.AppendEmpty().Attributes().PutStr("session.id", "rejected")
+	lrs.AppendEmpty().Body().SetStr("log")
+	lrs.AppendEmpty().Attributes().PutStr("session.id", "retried")
+
+	payloads, err := translator.MapLogsAndRouteRUMEvents(context.Background(), logs, nil, true, server.URL)
+	require.NoError(t, err)
+	require.Len(t, payloads, 1)
+	assert.Equal(t, "log", payloads[0].Message)
+	assert.EqualValues(t, 3, attempts.Load())
+
+	logged := observed.FilterMessage("Failed to forward RUM event").All()
+	require.Len(t, logged, 1)
+	assert.Contains(t, logged[0].ContextMap()["error"], "failed to forward RUM event 0: received non-OK response: status: 400")
+}
+
+func TestTranslatorRUMInvalidEvents(t *testing.T) {
+	var attempts atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		attempts.Add(1)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	set := componenttest.NewNopTelemetrySettings()
+	core, observed := observer.New(zap.WarnLevel)
+	set.Logger = zap.New(core)
+	attributesTranslator, err := attributes.NewTranslator(set)
+	require.NoError(t, err)
+	forwarderConfig := rum.DefaultForwarderConfig()
+	forwarderConfig.ValidatePayloads = true
+	var handled []rum.EventError
+	translator, err := NewTranslatorWithHTTPClient(set, attributesTranslator, "test", server.Client(),
+		WithRUMForwarderConfig(forwarderConfig),
+		WithRUMEventErrorHandler(func(_ context.Context, err rum.EventError) {
+			handled = append(handled, err)
+		}),
+	)
+	require.NoError(t, err)
+
+	logs := plog.NewLogs()
+	logs.ResourceLogs().AppendEmpty().ScopeLogs().AppendEmpty().LogRecords().AppendEmpty().Attributes().PutStr("session.id", "invalid")
+
+	payloads, err := translator.MapLogsAndRouteRUMEvents(context.Background(), logs, nil, true, server.URL)
+	require.NoError(t, err)
+	assert.Empty(t, payloads)
+	assert.Zero(t, attempts.Load())
+
+	logged := observed.FilterMessage("Dropping invalid RUM event").All()
+	require.Len(t, logged, 1)
+	assert.Contains(t, logged[0].ContextMap()["error"], "date: missing required field")
+	assert.Empty(t, observed.FilterMessage("Failed to forward RUM event").All())
+
+	// The caller is also given the error of the invalid event.
+	require.Len(t, handled, 1)
+	assert.Equal(t, 0, handled[0].Index)
+	var payloadErr *rum.PayloadError
+	require.ErrorAs(t, handled[0], &payloadErr)
+	assert.ErrorIs(t, handled[0], rum.ErrMissingField)
+}
+
+func TestTranslatorRUMEventsNotValidatedByDefault(t *testing.T) {
+	var attempts atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		attempts.Add(1)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	set := componenttest.NewNopTelemetrySettings()
+	attributesTranslator, err := attributes.NewTranslator(set)
+	require.NoError(t, err)
+	var handled []rum.EventError
+	translator, err := NewTranslatorWithHTTPClient(set, attributesTranslator, "test", server.Client(),
+		WithRUMEventErrorHandler(func(_ context.Context, err rum.EventError) {
+			handled = append(handled, err)
+		}),
+	)
+	require.NoError(t, err)
+
+	logs := plog.NewLogs()
+	logs.ResourceLogs().AppendEmpty().ScopeLogs().AppendEmpty().LogRecords().AppendEmpty().Attributes().PutStr("session.id", "incomplete")
+
+	_, err = translator.MapLogsAndRouteRUMEvents(context.Background(), logs, nil, true, server.URL)
+	require.NoError(t, err)
+	// The event is sent even though it is missing fields required by the RUM schema.
+	assert.EqualValues(t, 1, attempts.Load())
+	assert.Empty(t, handled)
+}
+
+func TestTranslatorHostnameResolvers(t *testing.T) {
+	set := componenttest.NewNopTelemetrySettings()
+	attributesTranslator, err := attributes.NewTranslator(set,
+		attributes.WithHostnameResolvers(attributes.HostNameResolver, attributes.KubernetesNodeResolver),
+	)
+	require.NoError(t, err)
+	translator, err := NewTranslator(set, attributesTranslator, "test")
+	require.NoError(t, err)
+
+	logs := plog.NewLogs()
+	rl := logs.ResourceLogs().AppendEmpty()
+	rl.Resource().Attributes().PutStr("k8s.node.name", "node")
+	rl.Resource().Attributes().PutStr("host.name", "resource-host")
+	lrs := rl.ScopeLogs().AppendEmpty().LogRecords()
+	lrs.AppendEmpty().Body().SetStr("from resource")
+
+	// The fallback on log attributes also uses the configured chain.
+	rl = logs.ResourceLogs().AppendEmpty()
+	lr := rl.ScopeLogs().AppendEmpty().LogRecords().AppendEmpty()
+	lr.Body().SetStr("from log attributes")
+	lr.Attributes().PutStr("k8s.node.name", "node")
+	lr.Attributes().PutStr("host.name", "log-host")
+
+	payloads := translator.MapLogs(context.Background(), logs, nil)
+	require.Len(t, payloads, 2)
+	assert.Equal(t, "resource-host", payloads[0].GetHostname())
+	assert.Equal(t, "log-host", payloads[1].GetHostname())
+}
+
+func TestTransformStructuredBody(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    func(pcommon.Value)
+		attrs   map[string]any
+		message string
+		props   map[string]any
+	}{
+		{
+			name: "map body",
+			body: func(v pcommon.Value) {
+				m := v.SetEmptyMap()
+				m.PutStr("msg", "hello world")
+				m.PutStr("level", "warn")
+				m.PutStr("trace_id", "437ab46e0ab6ad1dc4efd3e8a3b2a1f8")
+				m.PutEmptyMap("http").PutInt("status_code", 200)
+			},
+			message: "hello world",
+			props: map[string]any{
+				"status":           "warn",
+				ddTraceID:          "14190793946999988728",
+				otelTraceID:        "437ab46e0ab6ad1dc4efd3e8a3b2a1f8",
+				"http.status_code": int64(200),
+			},
+		},
+		{
+			name: "JSON string body",
+			body: func(v pcommon.Value) {
+				v.SetStr(`{"message": "hello world", "severity": "error", "count": 3, "ratio": 0.5, "dd.trace_id": 1234567890123456789}`)
+			},
+			message: "hello world",
+			props: map[string]any{
+				"status":    "error",
+				"count":     int64(3),
+				"ratio":     0.5,
+				ddTraceID:   "1234567890123456789",
+				otelTraceID: "0000000000000000112210f47de98115",
+			},
+		},
+		{
+			name: "attributes take precedence",
+			body: func(v pcommon.Value) {
+				v.SetStr(`{"msg": "from body", "key": "body"}`)
+			},
+			attrs:   map[string]any{"msg": "from attributes", "key": "attributes"},
+			message: "from attributes",
+			props:   map[string]any{"key": "attributes"},
+		},
+		{
+			name: "no message",
+			body: func(v pcommon.Value) {
+				v.SetStr(`{"key": "value"}`)
+			},
+			props: map[string]any{"key": "value"},
+		},
+		{
+			name: "plain string body",
+			body: func(v pcommon.Value) {
+				v.SetStr("hello {world}")
+			},
+			message: "hello {world}",
+		},
+		{
+			name: "invalid JSON body",
+			body: func(v pcommon.Value) {
+				v.SetStr(`{"key": "value"} trailing`)
+			},
+			message: `{"key": "value"} trailing`,
+		},
+	}
+
+	cfg, err := newTranslatorConfig([]TranslatorOption{WithStructuredBodyParsing()})
+	require.NoError(t, err)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lr := plog.NewLogRecord()
+			tt.body(lr.Body())
+			require.NoError(t, lr.Attributes().FromRaw(tt.attrs))
+
+			got := transform(lr, "", "", pcommon.NewResource(), pcommon.NewInstrumentationScope(), cfg, zaptest.NewLogger(t))
+			assert.Equal(t, tt.message, got.Message)
+			for k, v := range tt.props {
+				assert.Equal(t, v, got.AdditionalProperties[k], k)
+			}
+
+			// The body is kept as is when parsing is disabled.
+			got = transform(lr, "", "", pcommon.NewResource(), pcommon.NewInstrumentationScope(), defaultTranslatorConfig(), zaptest.NewLogger(t))
+			if msg, ok := tt.attrs["msg"]; ok {
+				assert.Equal(t, msg, got.Message)
+			} else {
+				assert.Equal(t, lr.Body().AsString(), got.Message)
+			}
+		})
+	}
+}
+
+func TestDecodeTraceID(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want pcommon.TraceID
+		err  bool
+	}{
+		{
+			name: "hex",
+			in:   "0af7651916cd43dd8448eb211c80319c",
+			want: pcommon.TraceID{0x0a, 0xf7, 0x65, 0x19, 0x16, 0xcd, 0x43, 0xdd, 0x84, 0x48, 0xeb, 0x21, 0x1c, 0x80, 0x31, 0x9c},
+		},
+		{
+			name: "128-bit decimal",
+			// 0x0af7651916cd43dd8448eb211c80319c
+			in:   "14576827793038113322513871894673895836",
+			want: pcommon.TraceID{0x0a, 0xf7, 0x65, 0x19, 0x16, 0xcd, 0x43, 0xdd, 0x84, 0x48, 0xeb, 0x21, 0x1c, 0x80, 0x31, 0x9c},
+		},
+		{
+			name: "64-bit hex",
+			in:   "8448eb211c80319c",
+			want: pcommon.TraceID{8: 0x84, 0x48, 0xeb, 0x21, 0x1c, 0x80, 0x31, 0x9c},
+		},
+		{
+			name: "64-bit decimal",
+			in:   "9532127138774266268",
+			want: pcommon.TraceID{8: 0x84, 0x48, 0xeb, 0x21, 0x1c, 0x80, 0x31, 0x9c},
+		},
+		{
+			// 16 digits are also valid hex characters: they are read as a decimal number.
+			name: "64-bit decimal of 16 digits",
+			in:   "1234567890123456",
+			want: pcommon.TraceID{8: 0x00, 0x04, 0x62, 0xd5, 0x3c, 0x8a, 0xba, 0xc0},
+		},
+		{
+			name: "max 128-bit decimal",
+			in:   "340282366920938463463374607431768211455",
+			want: pcommon.TraceID{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff},
+		},
+		{
+			name: "decimal over 128 bits",
+			in:   "340282366920938463463374607431768211456",
+			err:  true,
+		},
+		{
+			name: "negative decimal",
+			in:   "-1",
+			err:  true,
+		},
+		{
+			name: "invalid",
+			in:   "not-a-trace-id",
+			err:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := decodeTraceID(tt.in)
+			if tt.err {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestDecodeSpanID(t *testing.T) {
+	want := pcommon.SpanID{0xb7, 0xad, 0x6b, 0x71, 0x69, 0x20, 0x33, 0x31}
+	got, err := decodeSpanID("b7ad6b7169203331")
+	require.NoError(t, err)
+	assert.Equal(t, want, got)
+
+	got, err = decodeSpanID("13235353014750950193")
+	require.NoError(t, err)
+	assert.Equal(t, want, got)
+
+	_, err = decodeSpanID("18446744073709551616")
+	assert.Error(t, err)
+
+	// Datadog span IDs are always decimal, even when they are 16 characters long.
+	got, err = decodeDatadogSpanID("1234567890123456")
+	require.NoError(t, err)
+	assert.Equal(t, pcommon.SpanID{1: 0x04, 0x62, 0xd5, 0x3c, 0x8a, 0xba, 0xc0}, got)
+
+	_, err = decodeDatadogSpanID("b7ad6b7169203331")
+	assert.Error(t, err)
+}
+
+func TestDecodeTraceparent(t *testing.T) {
+	traceID, spanID, ok := decodeTraceparent("00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01")
+	require.True(t, ok)
+	assert.Equal(t, pcommon.TraceID{0x0a, 0xf7, 0x65, 0x19, 0x16, 0xcd, 0x43, 0xdd, 0x84, 0x48, 0xeb, 0x21, 0x1c, 0x80, 0x31, 0x9c}, traceID)
+	assert.Equal(t, pcommon.SpanID{0xb7, 0xad, 0x6b, 0x71, 0x69, 0x20, 0x33, 0x31}, spanID)
+
+	// Future versions may have more fields.
+	_, _, ok = decodeTraceparent("01-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01-extra")
+	assert.True(t, ok)
+
+	for _, in := range []string{
+		"0af7651916cd43dd8448eb211c80319c",
+		"00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01-extra",
+		"ff-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01",
+		"00-0af7651916cd43dd-b7ad6b7169203331-01",
+		"00-0af7651916cd43dd8448eb211c80319c-b7ad6b71692033zz-01",
+	} {
+		_, _, ok = decodeTraceparent(in)
+		assert.False(t, ok, in)
+	}
+}
+
+func TestTransformTraceIDFormats(t *testing.T) {
+	const (
+		otelTraceID64  = "00000000000000008448eb211c80319c"
+		otelTraceID128 = "0af7651916cd43dd8448eb211c80319c"
+		ddSpanIDValue  = "13235353014750950193"
+		otelSpanIDVal  = "b7ad6b7169203331"
+	)
+	tests := []struct {
+		name        string
+		attrs       map[string]any
+		otelTraceID string
+	}{
+		{
+			name:        "hex-128",
+			attrs:       map[string]any{"trace_id": "0AF7651916CD43DD8448EB211C80319C", "span_id": otelSpanIDVal},
+			otelTraceID: otelTraceID128,
+		},
+		{
+			name:        "hex-64",
+			attrs:       map[string]any{"trace_id": "8448eb211c80319c", "span_id": otelSpanIDVal},
+			otelTraceID: otelTraceID64,
+		},
+		{
+			name:        "Datadog decimal",
+			attrs:       map[string]any{"dd.trace_id": "9532127138774266268", "dd.span_id": ddSpanIDValue},
+			otelTraceID: otelTraceID64,
+		},
+		{
+			name:        "Datadog 128-bit hex",
+			attrs:       map[string]any{"dd.trace_id": otelTraceID128, "dd.span_id": ddSpanIDValue},
+			otelTraceID: otelTraceID128,
+		},
+		{
+			name:        "W3C traceparent",
+			attrs:       map[string]any{"traceparent": "00-" + otelTraceID128 + "-" + otelSpanIDVal + "-01"},
+			otelTraceID: otelTraceID128,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lr := plog.NewLogRecord()
+			require.NoError(t, lr.Attributes().FromRaw(tt.attrs))
+
+			got := transform(lr, "", "", pcommon.NewResource(), pcommon.NewInstrumentationScope(), defaultTranslatorConfig(), zaptest.NewLogger(t))
+			assert.Equal(t, "9532127138774266268", got.AdditionalProperties[ddTraceID])
+			assert.Equal(t, tt.otelTraceID, got.AdditionalProperties[otelTraceID])
+			assert.Equal(t, ddSpanIDValue, got.AdditionalProperties[ddSpanID])
+			assert.Equal(t, otelSpanIDVal, got.AdditionalProperties[otelSpanID])
+			for k := range tt.attrs {
+				if k != ddTraceID && k != ddSpanID {
+					assert.NotContains(t, got.AdditionalProperties, k)
+				}
+			}
+		})
+	}
+}
+
+func TestTransform128BitTraceIDs(t *testing.T) {
+	traceID := pcommon.TraceID{0x0a, 0xf7, 0x65, 0x19, 0x16, 0xcd, 0x43, 0xdd, 0x84, 0x48, 0xeb, 0x21, 0x1c, 0x80, 0x31, 0x9c}
+	cfg, err := newTranslatorConfig([]TranslatorOption{With128BitTraceIDs()})
+	require.NoError(t, err)
+
+	t.Run("log record trace ID", func(t *testing.T) {
+		lr := plog.NewLogRecord()
+		lr.SetTraceID(traceID)
+
+		got := transform(lr, "", "", pcommon.NewResource(), pcommon.NewInstrumentationScope(), cfg, zaptest.NewLogger(t))
+		assert.Equal(t, "9532127138774266268", got.AdditionalProperties[ddTraceID])
+		assert.Equal(t, "0af7651916cd43dd", got.AdditionalProperties[ddTraceIDHigh])
+		assert.Equal(t, "0af7651916cd43dd8448eb211c80319c", got.AdditionalProperties[otelTraceID])
+
+		got = transform(lr, "", "", pcommon.NewResource(), pcommon.NewInstrumentationScope(), defaultTranslatorConfig(), zaptest.NewLogger(t))
+		assert.Equal(t, "9532127138774266268", got.AdditionalProperties[ddTraceID])
+		assert.NotContains(t, got.AdditionalProperties, ddTraceIDHigh)
+	})
+
+	t.Run("decimal attribute", func(t *testing.T) {
+		lr := plog.NewLogRecord()
+		lr.Attributes().PutStr("trace_id", "14576827793038113322513871894673895836")
+
+		got := transform(lr, "", "", pcommon.NewResource(), pcommon.NewInstrumentationScope(), cfg, zaptest.NewLogger(t))
+		assert.Equal(t, "9532127138774266268", got.AdditionalProperties[ddTraceID])
+		assert.Equal(t, "0af7651916cd43dd", got.AdditionalProperties[ddTraceIDHigh])
+	})
+
+	t.Run("64-bit log record trace ID overrides attribute", func(t *testing.T) {
+		lr := plog.NewLogRecord()
+		lr.Attributes().PutStr("trace_id", "0af7651916cd43dd8448eb211c80319c")
+		lr.SetTraceID(pcommon.TraceID{15: 1})
+
+		got := transform(lr, "", "", pcommon.NewResource(), pcommon.NewInstrumentationScope(), cfg, zaptest.NewLogger(t))
+		assert.Equal(t, "1", got.AdditionalProperties[ddTraceID])
+		assert.NotContains(t, got.AdditionalProperties, ddTraceIDHigh)
+	})
+}
+
 func TestDeriveStatus(t *testing.T) {
 	type args struct {
 		severity plog.SeverityNumber
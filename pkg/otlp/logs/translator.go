@@ -15,17 +15,15 @@
 package logs
 
 import (
-	"bytes"
 	"context"
-	"encoding/json"
+	"errors"
 	"fmt"
-	"io"
 	"net/http"
 	"time"
 
-	"github.com/DataDog/datadog-agent/pkg/opentelemetry-mapping-go/otlp/attributes"
-	"github.com/DataDog/datadog-agent/pkg/opentelemetry-mapping-go/otlp/attributes/source"
 	"github.com/DataDog/datadog-api-client-go/v2/api/datadogV2"
+	"github.com/DataDog/opentelemetry-mapping-go/pkg/otlp/attributes"
+	"github.com/DataDog/opentelemetry-mapping-go/pkg/otlp/attributes/source"
 	"github.com/DataDog/opentelemetry-mapping-go/pkg/otlp/rum"
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/pdata/pcommon"
@@ -48,28 +46,42 @@ type Translator struct {
 	set                  component.TelemetrySettings
 	attributesTranslator *attributes.Translator
 	otelTag              string
-	httpClient           HTTPClient
+	rumForwarder         *rum.Forwarder
+	cfg                  translatorConfig
 }
 
 // NewTranslator returns a new Translator
-func NewTranslator(set component.TelemetrySettings, attributesTranslator *attributes.Translator, otelSource string) (*Translator, error) {
+func NewTranslator(set component.TelemetrySettings, attributesTranslator *attributes.Translator, otelSource string, options ...TranslatorOption) (*Translator, error) {
+	cfg, err := newTranslatorConfig(options)
+	if err != nil {
+		return nil, err
+	}
 	return &Translator{
 		set:                  set,
 		attributesTranslator: attributesTranslator,
 		otelTag:              "otel_source:" + otelSource,
-		httpClient:           nil,
+		cfg:                  cfg,
 	}, nil
 }
 
-func NewTranslatorWithHTTPClient(set component.TelemetrySettings, attributesTranslator *attributes.Translator, otelSource string, client HTTPClient) (*Translator, error) {
+func NewTranslatorWithHTTPClient(set component.TelemetrySettings, attributesTranslator *attributes.Translator, otelSource string, client HTTPClient, options ...TranslatorOption) (*Translator, error) {
 	if client == nil {
 		client = &http.Client{Timeout: 10 * time.Second}
 	}
+	cfg, err := newTranslatorConfig(options)
+	if err != nil {
+		return nil, err
+	}
+	rumForwarder, err := rum.NewForwarder(client, cfg.rumForwarder, set.Logger)
+	if err != nil {
+		return nil, err
+	}
 	return &Translator{
 		set:                  set,
 		attributesTranslator: attributesTranslator,
 		otelTag:              "otel_source:" + otelSource,
-		httpClient:           client,
+		rumForwarder:         rumForwarder,
+		cfg:                  cfg,
 	}, nil
 }
 
@@ -91,13 +103,65 @@ func (t *Translator) hostFromAttributes(ctx context.Context, attrs pcommon.Map)
 }
 
 // MapLogsAndRouteRUMEvents from OTLP format to Datadog format if shouldForwardOTLPRUMToDDRUM is true.
+// RUM events are forwarded to the RUM intake once all logs are mapped, see StreamLogsAndRouteRUMEvents.
 func (t *Translator) MapLogsAndRouteRUMEvents(ctx context.Context, ld plog.Logs, hostFromAttributesHandler attributes.HostFromAttributesHandler, shouldForwardOTLPRUMToDDRUM bool, rumIntakeUrl string) ([]datadogV2.HTTPLogItem, error) {
-	if t.httpClient == nil {
-		return nil, fmt.Errorf("httpClient is nil")
+	var payloads []datadogV2.HTTPLogItem
+	err := t.StreamLogsAndRouteRUMEvents(ctx, ld, hostFromAttributesHandler, shouldForwardOTLPRUMToDDRUM, rumIntakeUrl, ConsumerFunc(func(_ context.Context, payload datadogV2.HTTPLogItem) error {
+		payloads = append(payloads, payload)
+		return nil
+	}))
+	if err != nil {
+		return []datadogV2.HTTPLogItem{}, err
 	}
+	return payloads, nil
+}
 
-	rsl := ld.ResourceLogs()
+// StreamLogsAndRouteRUMEvents is like MapLogsAndRouteRUMEvents, but passes every log to the consumer
+// as soon as it is mapped instead of returning all of them, so that callers can send logs incrementally.
+// It stops at the first error from the consumer.
+//
+// If shouldForwardOTLPRUMToDDRUM is true, log records holding RUM events are not mapped to logs. Once all logs
+// are mapped, the RUM events are sent concurrently to the RUM intake, with retries. Events that cannot be sent,
+// or that are invalid (see rum.ValidatePayload), are logged and passed to the handler set with
+// WithRUMEventErrorHandler, if any. They do not fail the mapping. If the consumer fails, the RUM events
+// collected before its error are still sent before the error is returned.
+func (t *Translator) StreamLogsAndRouteRUMEvents(ctx context.Context, ld plog.Logs, hostFromAttributesHandler attributes.HostFromAttributesHandler, shouldForwardOTLPRUMToDDRUM bool, rumIntakeUrl string, consumer Consumer) error {
+	if t.rumForwarder == nil {
+		return fmt.Errorf("httpClient is nil")
+	}
+	var rumEvents []rum.Event
+	// The events collected before an error of the consumer are forwarded anyway, since they are not retried with the logs.
+	err := t.mapLogs(ctx, ld, hostFromAttributesHandler, shouldForwardOTLPRUMToDDRUM, &rumEvents, consumer)
+	for _, eventErr := range t.rumForwarder.Forward(ctx, rumIntakeUrl, rumEvents) {
+		var payloadErr *rum.PayloadError
+		if errors.As(eventErr, &payloadErr) {
+			t.set.Logger.Warn("Dropping invalid RUM event", zap.Error(eventErr))
+		} else {
+			t.set.Logger.Error("Failed to forward RUM event", zap.Error(eventErr))
+		}
+		if t.cfg.rumEventErrorHandler != nil {
+			t.cfg.rumEventErrorHandler(ctx, eventErr)
+		}
+	}
+	return err
+}
+
+// MapLogs from OTLP format to Datadog format.
+// Deprecated: Deprecated in favor of MapLogsAndRouteRUMEvents.
+func (t *Translator) MapLogs(ctx context.Context, ld plog.Logs, hostFromAttributesHandler attributes.HostFromAttributesHandler) []datadogV2.HTTPLogItem {
 	var payloads []datadogV2.HTTPLogItem
+	// Without RUM routing and with a consumer that never fails, mapping cannot fail.
+	_ = t.mapLogs(ctx, ld, hostFromAttributesHandler, false, nil, ConsumerFunc(func(_ context.Context, payload datadogV2.HTTPLogItem) error {
+		payloads = append(payloads, payload)
+		return nil
+	}))
+	return payloads
+}
+
+// mapLogs passes the logs mapped from ld to the consumer. If shouldForwardOTLPRUMToDDRUM is true,
+// the log records holding RUM events are appended to rumEvents instead.
+func (t *Translator) mapLogs(ctx context.Context, ld plog.Logs, hostFromAttributesHandler attributes.HostFromAttributesHandler, shouldForwardOTLPRUMToDDRUM bool, rumEvents *[]rum.Event, consumer Consumer) error {
+	rsl := ld.ResourceLogs()
 	for i := 0; i < rsl.Len(); i++ {
 		rl := rsl.At(i)
 		sls := rl.ScopeLogs()
@@ -112,56 +176,10 @@ func (t *Translator) MapLogsAndRouteRUMEvents(ctx context.Context, ld plog.Logs,
 				logRecord := lsl.At(k)
 				if shouldForwardOTLPRUMToDDRUM {
 					if _, isRum := logRecord.Attributes().Get("session.id"); isRum {
-						rattr := rl.Resource().Attributes()
-						lattr := logRecord.Attributes()
-
-						// build the Datadog intake URL
-						pathAndParams := rum.BuildIntakeUrlPathAndParameters(rattr, lattr)
-						outUrlString := rumIntakeUrl + pathAndParams
-
-						rumPayload := rum.ConstructRumPayloadFromOTLP(lattr)
-						byts, err := json.Marshal(rumPayload)
-						if err != nil {
-							return []datadogV2.HTTPLogItem{}, fmt.Errorf("failed to marshal RUM payload: %w", err)
-						}
-
-						req, err := http.NewRequest("POST", outUrlString, bytes.NewBuffer(byts))
-						if err != nil {
-							return []datadogV2.HTTPLogItem{}, fmt.Errorf("failed to create request: %w", err)
-						}
-
-						// add X-Forwarded-For header containing the request client IP address
-						ip, ok := lattr.Get("client.address")
-						if ok {
-							req.Header.Add("X-Forwarded-For", ip.AsString())
-						}
-
-						req.Header.Set("Content-Type", "text/plain;charset=UTF-8")
-
-						// send the request to the Datadog intake URL
-						resp, err := t.httpClient.Do(req)
-						if err != nil {
-							return []datadogV2.HTTPLogItem{}, fmt.Errorf("failed to send request: %w", err)
-						}
-						if resp != nil && resp.Body != nil {
-							defer func() {
-								if cerr := resp.Body.Close(); cerr != nil {
-									t.set.Logger.Error("failed to close response body: %v", zap.Error(cerr))
-								}
-							}()
-						}
-
-						// read the response body
-						body, err := io.ReadAll(resp.Body)
-						if err != nil {
-							return []datadogV2.HTTPLogItem{}, fmt.Errorf("failed to read response: %w", err)
-						}
-
-						// check the status code of the response
-						if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusAccepted {
-							return []datadogV2.HTTPLogItem{}, fmt.Errorf("received non-OK response: status: %s, body: %s", resp.Status, string(body))
-						}
-						t.set.Logger.Info("Response:", zap.String("body", string(body)))
+						*rumEvents = append(*rumEvents, rum.Event{
+							ResourceAttributes: res.Attributes(),
+							LogAttributes:      logRecord.Attributes(),
+						})
 						continue
 					}
 				}
@@ -176,58 +194,18 @@ func (t *Translator) MapLogsAndRouteRUMEvents(ctx context.Context, ld plog.Logs,
 					}
 				}
 
-				payload := transform(logRecord, host, service, res, scope, t.set.Logger)
+				payload := transform(logRecord, host, service, res, scope, t.cfg, t.set.Logger)
 				ddtags := payload.GetDdtags()
 				if ddtags != "" {
 					payload.SetDdtags(ddtags + "," + t.otelTag)
 				} else {
 					payload.SetDdtags(t.otelTag)
 				}
-				payloads = append(payloads, payload)
-			}
-		}
-	}
-	return payloads, nil
-}
-
-// MapLogs from OTLP format to Datadog format.
-// Deprecated: Deprecated in favor of MapLogsAndRouteRUMEvents.
-func (t *Translator) MapLogs(ctx context.Context, ld plog.Logs, hostFromAttributesHandler attributes.HostFromAttributesHandler) []datadogV2.HTTPLogItem {
-	rsl := ld.ResourceLogs()
-	var payloads []datadogV2.HTTPLogItem
-	for i := 0; i < rsl.Len(); i++ {
-		rl := rsl.At(i)
-		sls := rl.ScopeLogs()
-		res := rl.Resource()
-		host, service := t.hostNameAndServiceNameFromResource(ctx, res, hostFromAttributesHandler)
-		for j := 0; j < sls.Len(); j++ {
-			sl := sls.At(j)
-			lsl := sl.LogRecords()
-			scope := sl.Scope()
-			// iterate over Logs
-			for k := 0; k < lsl.Len(); k++ {
-				log := lsl.At(k)
-				// HACK: Check for host and service in log record attributes
-				// This is not aligned with the specification and will be removed in the future.
-				if host == "" {
-					host = t.hostFromAttributes(ctx, log.Attributes())
-				}
-				if service == "" {
-					if s, ok := log.Attributes().Get(string(conventions.ServiceNameKey)); ok {
-						service = s.AsString()
-					}
-				}
-
-				payload := transform(log, host, service, res, scope, t.set.Logger)
-				ddtags := payload.GetDdtags()
-				if ddtags != "" {
-					payload.SetDdtags(ddtags + "," + t.otelTag)
-				} else {
-					payload.SetDdtags(t.otelTag)
+				if err := consumer.ConsumeLog(ctx, payload); err != nil {
+					return err
 				}
-				payloads = append(payloads, payload)
 			}
 		}
 	}
-	return payloads
+	return nil
 }
@@ -0,0 +1,271 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2023-present Datadog, Inc.
+
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/DataDog/datadog-agent/pkg/util/quantile"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/otel/attribute"
+	otelmetric "go.opentelemetry.io/otel/metric"
+)
+
+const cardinalityLimitedPointsMetricName string = "datadog.otlp_translator.metrics.cardinality_limited_points"
+
+// defaultOverflowTags are the tags of the timeseries overflowing points are folded into
+// when no CardinalityLimit.OverflowTags are set.
+var defaultOverflowTags = []string{"otel_overflow:true"}
+
+// CardinalityLimitAction is the action taken on points of timeseries over a CardinalityLimit.
+type CardinalityLimitAction string
+
+const (
+	// CardinalityLimitActionDrop drops the points of timeseries over the limit.
+	CardinalityLimitActionDrop CardinalityLimitAction = "drop"
+	// CardinalityLimitActionFold replaces the tags of timeseries over the limit by the tags of their
+	// resource and scope and the overflow tags, folding all of them into a single timeseries per metric
+	// and resource.
+	CardinalityLimitActionFold CardinalityLimitAction = "fold"
+)
+
+// CardinalityLimit limits the number of distinct timeseries of every Datadog metric produced by the translator.
+//
+// A timeseries is active from the time it is last seen until Window has elapsed. Once a metric has
+// MaxContexts active timeseries, points of any new timeseries are dropped or folded until some of
+// the active timeseries expire. The limit applies to the points passed to the Consumer, once deltas
+// are computed from cumulative timeseries: every bucket of a histogram is a distinct timeseries of its
+// `.bucket` metric.
+//
+// With CardinalityLimitActionDrop, the limit also applies to the OTLP data points of cumulative timeseries
+// before their deltas are computed, so that the cache used for computing deltas stays bounded: once an
+// OTLP metric has MaxContexts active cumulative timeseries, points of new ones are dropped. With
+// CardinalityLimitActionFold, cumulative timeseries over the limit are still stored in the cache, since
+// their deltas are folded into the overflow timeseries.
+type CardinalityLimit struct {
+	// MaxContexts is the maximum number of active timeseries per metric name.
+	MaxContexts int
+	// Window is the time a timeseries stays active after its last point.
+	Window time.Duration
+	// Action is the action taken on points of timeseries over the limit.
+	Action CardinalityLimitAction
+	// OverflowTags are the tags added to the tags of the resource and scope of the timeseries points are
+	// folded into when using CardinalityLimitActionFold. Defaults to `otel_overflow:true`.
+	OverflowTags []string
+}
+
+// Validate checks that the limit is well-formed.
+func (l CardinalityLimit) Validate() error {
+	if l.MaxContexts <= 0 {
+		return fmt.Errorf("cardinality limit must be positive: %d", l.MaxContexts)
+	}
+	if l.Window <= 0 {
+		return fmt.Errorf("cardinality limit window must be positive: %s", l.Window)
+	}
+	switch l.Action {
+	case CardinalityLimitActionDrop:
+		if len(l.OverflowTags) > 0 {
+			return fmt.Errorf("overflow tags can only be set with the %q cardinality limit action", CardinalityLimitActionFold)
+		}
+	case CardinalityLimitActionFold:
+	default:
+		return fmt.Errorf("unknown cardinality limit action: %q", l.Action)
+	}
+	return nil
+}
+
+// metricContexts are the active timeseries of a metric.
+type metricContexts struct {
+	// lastSeen is the time a timeseries was last seen in nanoseconds since epoch, keyed by Dimensions.String().
+	lastSeen map[string]int64
+	// oldest is a lower bound of the values in lastSeen.
+	oldest int64
+}
+
+// cardinalityLimiter enforces a CardinalityLimit.
+type cardinalityLimiter struct {
+	maxContexts  int
+	window       int64
+	fold         bool
+	overflowTags []string
+	attrs        otelmetric.MeasurementOption
+	limited      otelmetric.Int64Counter
+	// now is the current time, overridable in tests.
+	now func() time.Time
+
+	mu      sync.Mutex
+	metrics map[string]*metricContexts
+	// cumulative are the active cumulative timeseries stored in the cache used for computing deltas,
+	// by OTLP metric name. They are only limited with CardinalityLimitActionDrop.
+	cumulative map[string]*metricContexts
+	// lastSweep is the time metrics without active timeseries were last removed, in nanoseconds since epoch.
+	lastSweep int64
+}
+
+func newCardinalityLimiter(set component.TelemetrySettings, limit CardinalityLimit) (*cardinalityLimiter, error) {
+	limited, err := set.MeterProvider.Meter(meterName).Int64Counter(
+		cardinalityLimitedPointsMetricName,
+		otelmetric.WithDescription("Points of timeseries over the cardinality limit of their metric"),
+		otelmetric.WithUnit("{point}"),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build cardinality limited points counter: %w", err)
+	}
+
+	l := &cardinalityLimiter{
+		maxContexts: limit.MaxContexts,
+		window:      int64(limit.Window),
+		fold:        limit.Action == CardinalityLimitActionFold,
+		attrs:       otelmetric.WithAttributeSet(attribute.NewSet(attribute.String("action", string(limit.Action)))),
+		limited:     limited,
+		now:         time.Now,
+		metrics:     make(map[string]*metricContexts),
+		cumulative:  make(map[string]*metricContexts),
+	}
+	if l.fold {
+		l.overflowTags = limit.OverflowTags
+		if len(l.overflowTags) == 0 {
+			l.overflowTags = defaultOverflowTags
+		}
+	}
+	return l, nil
+}
+
+// prune removes the timeseries of m which are no longer active.
+func (l *cardinalityLimiter) prune(m *metricContexts, now int64) {
+	oldest := now
+	for key, lastSeen := range m.lastSeen {
+		if now-lastSeen > l.window {
+			delete(m.lastSeen, key)
+		} else if lastSeen < oldest {
+			oldest = lastSeen
+		}
+	}
+	m.oldest = oldest
+}
+
+// sweep removes the metrics without active timeseries, so that metrics which are no longer
+// seen do not use memory forever. It runs at most once per window.
+func (l *cardinalityLimiter) sweep(now int64) {
+	if now-l.lastSweep <= l.window {
+		return
+	}
+	l.lastSweep = now
+	for _, metrics := range []map[string]*metricContexts{l.metrics, l.cumulative} {
+		for name, m := range metrics {
+			if now-m.oldest > l.window {
+				l.prune(m, now)
+			}
+			if len(m.lastSeen) == 0 {
+				delete(metrics, name)
+			}
+		}
+	}
+}
+
+// track marks the timeseries with the given dimensions as active in metrics, and reports
+// whether it is admitted, that is, whether it was already active or its metric is under the limit.
+func (l *cardinalityLimiter) track(metrics map[string]*metricContexts, dims *Dimensions) bool {
+	key := dims.String()
+	now := l.now().UnixNano()
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.sweep(now)
+	m, ok := metrics[dims.name]
+	if !ok {
+		m = &metricContexts{lastSeen: make(map[string]int64), oldest: now}
+		metrics[dims.name] = m
+	}
+	_, active := m.lastSeen[key]
+	if !active && len(m.lastSeen) >= l.maxContexts && now-m.oldest > l.window {
+		// Make room by removing inactive timeseries.
+		l.prune(m, now)
+	}
+	admitted := active || len(m.lastSeen) < l.maxContexts
+	if admitted {
+		m.lastSeen[key] = now
+	}
+	return admitted
+}
+
+// admit returns the dimensions to use for a point with the given dimensions, or false if the point
+// must be dropped because its metric is over the limit. baseTags are the tags of the resource and scope
+// of the point, kept when folding it.
+func (l *cardinalityLimiter) admit(ctx context.Context, dims *Dimensions, baseTags map[string]struct{}) (*Dimensions, bool) {
+	if l.track(l.metrics, dims) {
+		return dims, true
+	}
+
+	l.limited.Add(ctx, 1, l.attrs)
+	if !l.fold {
+		return nil, false
+	}
+	// Keep the base tags the point still has, since some of them may have been removed by a filter rule.
+	tags := make([]string, 0, len(baseTags)+len(l.overflowTags))
+	for _, tag := range dims.tags {
+		if _, ok := baseTags[tag]; ok {
+			tags = append(tags, tag)
+		}
+	}
+	return dims.withTags(append(tags, l.overflowTags...)), true
+}
+
+// admitCumulative reports whether a point of a cumulative timeseries, with the dimensions of its OTLP
+// data point, may be stored in the cache used for computing deltas. With CardinalityLimitActionDrop,
+// points of new timeseries of metrics over the limit are dropped before reaching the cache, so that
+// a tag explosion does not make the cache grow. With CardinalityLimitActionFold, all points are
+// admitted, since their deltas are folded into the overflow timeseries. If l is nil, all points are admitted.
+func (l *cardinalityLimiter) admitCumulative(ctx context.Context, dims *Dimensions) bool {
+	if l == nil || l.fold {
+		return true
+	}
+	if l.track(l.cumulative, dims) {
+		return true
+	}
+	l.limited.Add(ctx, 1, l.attrs)
+	return false
+}
+
+// limitingConsumer applies a cardinalityLimiter before passing metrics to the wrapped Consumer.
+type limitingConsumer struct {
+	Consumer
+	limiter  *cardinalityLimiter
+	baseTags map[string]struct{}
+}
+
+var _ Consumer = (*limitingConsumer)(nil)
+
+// wrap returns a Consumer that limits the cardinality of metrics before passing them to consumer.
+// baseTags are the tags of the resource and scope of the metrics. If l is nil, consumer is returned as is.
+func (l *cardinalityLimiter) wrap(consumer Consumer, baseTags []string) Consumer {
+	if l == nil {
+		return consumer
+	}
+	c := &limitingConsumer{Consumer: consumer, limiter: l}
+	if l.fold {
+		c.baseTags = make(map[string]struct{}, len(baseTags))
+		for _, tag := range baseTags {
+			c.baseTags[tag] = struct{}{}
+		}
+	}
+	return c
+}
+
+func (c *limitingConsumer) ConsumeTimeSeries(ctx context.Context, dimensions *Dimensions, typ DataType, timestamp uint64, interval int64, value float64) {
+	if dims, ok := c.limiter.admit(ctx, dimensions, c.baseTags); ok {
+		c.Consumer.ConsumeTimeSeries(ctx, dims, typ, timestamp, interval, value)
+	}
+}
+
+func (c *limitingConsumer) ConsumeSketch(ctx context.Context, dimensions *Dimensions, timestamp uint64, interval int64, sketch *quantile.Sketch) {
+	if dims, ok := c.limiter.admit(ctx, dimensions, c.baseTags); ok {
+		c.Consumer.ConsumeSketch(ctx, dims, timestamp, interval, sketch)
+	}
+}
@@ -0,0 +1,262 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2023-present Datadog, Inc.
+
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+func TestCardinalityLimitValidate(t *testing.T) {
+	tests := []struct {
+		name  string
+		limit CardinalityLimit
+		err   string
+	}{
+		{
+			name:  "valid drop",
+			limit: CardinalityLimit{MaxContexts: 10, Window: time.Minute, Action: CardinalityLimitActionDrop},
+		},
+		{
+			name:  "valid fold",
+			limit: CardinalityLimit{MaxContexts: 10, Window: time.Minute, Action: CardinalityLimitActionFold, OverflowTags: []string{"overflow:true"}},
+		},
+		{
+			name:  "zero limit",
+			limit: CardinalityLimit{Window: time.Minute, Action: CardinalityLimitActionDrop},
+			err:   "cardinality limit must be positive: 0",
+		},
+		{
+			name:  "zero window",
+			limit: CardinalityLimit{MaxContexts: 10, Action: CardinalityLimitActionDrop},
+			err:   "cardinality limit window must be positive: 0s",
+		},
+		{
+			name:  "unknown action",
+			limit: CardinalityLimit{MaxContexts: 10, Window: time.Minute, Action: "sample"},
+			err:   `unknown cardinality limit action: "sample"`,
+		},
+		{
+			name:  "overflow tags on drop",
+			limit: CardinalityLimit{MaxContexts: 10, Window: time.Minute, Action: CardinalityLimitActionDrop, OverflowTags: []string{"overflow:true"}},
+			err:   `overflow tags can only be set with the "fold" cardinality limit action`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.limit.Validate()
+			if tt.err == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.err)
+			}
+		})
+	}
+}
+
+func TestCardinalityLimiterAdmit(t *testing.T) {
+	set := componenttest.NewNopTelemetrySettings()
+	reader := sdkmetric.NewManualReader()
+	set.MeterProvider = sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+	limiter, err := newCardinalityLimiter(set, CardinalityLimit{MaxContexts: 2, Window: time.Minute, Action: CardinalityLimitActionDrop})
+	require.NoError(t, err)
+	now := time.Unix(1_000_000, 0)
+	limiter.now = func() time.Time { return now }
+
+	ctx := context.Background()
+	series := func(name string, i int) *Dimensions {
+		return &Dimensions{name: name, tags: []string{fmt.Sprintf("id:%d", i)}}
+	}
+
+	for i := 0; i < 2; i++ {
+		_, ok := limiter.admit(ctx, series("limited", i), nil)
+		assert.True(t, ok)
+	}
+	_, ok := limiter.admit(ctx, series("limited", 2), nil)
+	assert.False(t, ok, "third timeseries is over the limit")
+	_, ok = limiter.admit(ctx, series("limited", 0), nil)
+	assert.True(t, ok, "active timeseries are still admitted")
+	_, ok = limiter.admit(ctx, series("other", 0), nil)
+	assert.True(t, ok, "the limit is per metric name")
+
+	// Timeseries 1 expires, timeseries 0 was seen again since.
+	now = now.Add(40 * time.Second)
+	_, ok = limiter.admit(ctx, series("limited", 0), nil)
+	assert.True(t, ok)
+	now = now.Add(30 * time.Second)
+	_, ok = limiter.admit(ctx, series("limited", 2), nil)
+	assert.True(t, ok, "expired timeseries make room for new ones")
+	_, ok = limiter.admit(ctx, series("limited", 1), nil)
+	assert.False(t, ok, "expired timeseries count as new ones")
+	assert.Len(t, limiter.metrics["limited"].lastSeen, 2)
+
+	rm := &metricdata.ResourceMetrics{}
+	require.NoError(t, reader.Collect(ctx, rm))
+	var found bool
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			if m.Name != cardinalityLimitedPointsMetricName {
+				continue
+			}
+			found = true
+			dps := m.Data.(metricdata.Sum[int64]).DataPoints
+			require.Len(t, dps, 1)
+			assert.Equal(t, int64(2), dps[0].Value)
+			// The metric name is not an attribute, since it would make the cardinality of the counter unbounded.
+			assert.Equal(t, 1, dps[0].Attributes.Len())
+			action, _ := dps[0].Attributes.Value("action")
+			assert.Equal(t, "drop", action.AsString())
+		}
+	}
+	assert.True(t, found)
+}
+
+func TestCardinalityLimiterSweep(t *testing.T) {
+	limiter, err := newCardinalityLimiter(componenttest.NewNopTelemetrySettings(), CardinalityLimit{MaxContexts: 2, Window: time.Minute, Action: CardinalityLimitActionDrop})
+	require.NoError(t, err)
+	now := time.Unix(1_000_000, 0)
+	limiter.now = func() time.Time { return now }
+
+	ctx := context.Background()
+	for i := 0; i < 10; i++ {
+		_, ok := limiter.admit(ctx, &Dimensions{name: fmt.Sprintf("metric.%d", i)}, nil)
+		require.True(t, ok)
+	}
+	assert.Len(t, limiter.metrics, 10)
+
+	now = now.Add(30 * time.Second)
+	_, ok := limiter.admit(ctx, &Dimensions{name: "metric.0"}, nil)
+	require.True(t, ok)
+	assert.Len(t, limiter.metrics, 10, "metrics are not removed while their timeseries are active")
+
+	now = now.Add(45 * time.Second)
+	_, ok = limiter.admit(ctx, &Dimensions{name: "metric.10"}, nil)
+	require.True(t, ok)
+	assert.Len(t, limiter.metrics, 2, "metrics without active timeseries are removed")
+	assert.Contains(t, limiter.metrics, "metric.0")
+	assert.Contains(t, limiter.metrics, "metric.10")
+}
+
+func TestCardinalityLimiterFold(t *testing.T) {
+	limiter, err := newCardinalityLimiter(componenttest.NewNopTelemetrySettings(), CardinalityLimit{MaxContexts: 1, Window: time.Minute, Action: CardinalityLimitActionFold})
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	baseTags := map[string]struct{}{"env:prod": {}, "service:web": {}}
+	dims, ok := limiter.admit(ctx, &Dimensions{name: "metric", tags: []string{"id:0", "env:prod"}, host: "host"}, baseTags)
+	require.True(t, ok)
+	assert.Equal(t, []string{"id:0", "env:prod"}, dims.tags)
+
+	// service:web was removed by a filter rule.
+	dims, ok = limiter.admit(ctx, &Dimensions{name: "metric", tags: []string{"id:1", "env:prod"}, host: "host"}, baseTags)
+	require.True(t, ok)
+	assert.Equal(t, "metric", dims.name)
+	assert.Equal(t, "host", dims.host)
+	assert.Equal(t, []string{"env:prod", "otel_overflow:true"}, dims.tags)
+}
+
+func TestWithCardinalityLimit(t *testing.T) {
+	newMetrics := func(ts int, val float64) pmetric.Metrics {
+		md := pmetric.NewMetrics()
+		rm := md.ResourceMetrics().AppendEmpty()
+		rm.Resource().Attributes().PutStr("deployment.environment", "prod")
+		m := rm.ScopeMetrics().AppendEmpty().Metrics().AppendEmpty()
+		m.SetName("cumulative.sum")
+		sum := m.SetEmptySum()
+		sum.SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
+		sum.SetIsMonotonic(true)
+		for i := 0; i < 5; i++ {
+			dp := sum.DataPoints().AppendEmpty()
+			dp.SetStartTimestamp(seconds(1))
+			dp.SetTimestamp(seconds(ts))
+			dp.SetDoubleValue(val)
+			dp.Attributes().PutInt("id", int64(i))
+		}
+		return md
+	}
+
+	t.Run("drop", func(t *testing.T) {
+		translator := NewTestTranslator(t,
+			WithCardinalityLimit(CardinalityLimit{MaxContexts: 3, Window: time.Hour, Action: CardinalityLimitActionDrop}),
+		)
+		consumer := &mockFullConsumer{}
+		_, err := translator.MapMetrics(context.Background(), newMetrics(2, 10), consumer, nil)
+		require.NoError(t, err)
+		assert.Equal(t, 3, translator.prevPts.cache.ItemCount(), "timeseries over the limit are not stored in the cache")
+
+		_, err = translator.MapMetrics(context.Background(), newMetrics(3, 15), consumer, nil)
+		require.NoError(t, err)
+		var tags []string
+		for _, m := range consumer.metrics {
+			assert.Equal(t, 5.0, m.value)
+			tags = append(tags, m.tags...)
+		}
+		assert.ElementsMatch(t, []string{"env:prod", "id:0", "env:prod", "id:1", "env:prod", "id:2"}, tags)
+	})
+
+	t.Run("drop histograms", func(t *testing.T) {
+		translator := NewTestTranslator(t,
+			WithCardinalityLimit(CardinalityLimit{MaxContexts: 3, Window: time.Hour, Action: CardinalityLimitActionDrop}),
+		)
+		md := pmetric.NewMetrics()
+		m := md.ResourceMetrics().AppendEmpty().ScopeMetrics().AppendEmpty().Metrics().AppendEmpty()
+		m.SetName("cumulative.histogram")
+		hist := m.SetEmptyHistogram()
+		hist.SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
+		for i := 0; i < 100; i++ {
+			dp := hist.DataPoints().AppendEmpty()
+			dp.SetStartTimestamp(seconds(1))
+			dp.SetTimestamp(seconds(2))
+			dp.SetCount(1)
+			dp.SetSum(1)
+			dp.SetMin(1)
+			dp.ExplicitBounds().FromRaw([]float64{0})
+			dp.BucketCounts().FromRaw([]uint64{0, 1})
+			dp.Attributes().PutInt("id", int64(i))
+		}
+		_, err := translator.MapMetrics(context.Background(), md, &mockFullConsumer{}, nil)
+		require.NoError(t, err)
+		// count, sum, min and both buckets of 3 points.
+		assert.Equal(t, 15, translator.prevPts.cache.ItemCount())
+	})
+
+	t.Run("fold", func(t *testing.T) {
+		translator := NewTestTranslator(t,
+			WithCardinalityLimit(CardinalityLimit{MaxContexts: 3, Window: time.Hour, Action: CardinalityLimitActionFold}),
+		)
+		consumer := &mockFullConsumer{}
+		_, err := translator.MapMetrics(context.Background(), newMetrics(2, 10), consumer, nil)
+		require.NoError(t, err)
+		_, err = translator.MapMetrics(context.Background(), newMetrics(3, 15), consumer, nil)
+		require.NoError(t, err)
+
+		var overflow int
+		for _, m := range consumer.metrics {
+			// Folded points are deltas of their own timeseries.
+			assert.Equal(t, 5.0, m.value)
+			if slices.Contains(m.tags, "otel_overflow:true") {
+				overflow++
+				assert.ElementsMatch(t, []string{"env:prod", "otel_overflow:true"}, m.tags)
+			}
+		}
+		assert.Len(t, consumer.metrics, 5)
+		assert.Equal(t, 2, overflow)
+	})
+
+	_, err := NewTranslator(componenttest.NewNopTelemetrySettings(), nil, WithCardinalityLimit(CardinalityLimit{}))
+	assert.EqualError(t, err, "cardinality limit must be positive: 0")
+}
@@ -16,8 +16,9 @@ package metrics
 
 import (
 	"fmt"
+	"io"
 
-	"github.com/DataDog/datadog-agent/pkg/opentelemetry-mapping-go/otlp/attributes/source"
+	"github.com/DataDog/opentelemetry-mapping-go/pkg/otlp/attributes/source"
 )
 
 type translatorConfig struct {
@@ -26,6 +27,8 @@ type translatorConfig struct {
 	SendHistogramAggregations            bool
 	Quantiles                            bool
 	NumberMode                           NumberMode
+	NonMonotonicSumMode                  NonMonotonicSumMode
+	SummaryMode                          SummaryMode
 	InitialCumulMonoValueMode            InitialCumulMonoValueMode
 	InstrumentationLibraryMetadataAsTags bool
 	InstrumentationScopeMetadataAsTags   bool
@@ -43,9 +46,21 @@ type translatorConfig struct {
 	// Agent from computing metrics with the same names.
 	withOTelPrefix bool
 
+	// remappingRules are custom rules for computing new metrics from OTel metrics.
+	// They are applied in addition to the default rules, replacing the ones with the same Match and Name.
+	remappingRules []RemappingRule
+
+	// filterRules are the rules for filtering metrics and tags before they are consumed.
+	filterRules []FilterRule
+
+	// cardinalityLimit is the limit of timeseries per metric, if any.
+	cardinalityLimit *CardinalityLimit
+
 	// cache configuration
 	sweepInterval int64
 	deltaTTL      int64
+	// cacheSnapshot is restored into the cache when creating the translator.
+	cacheSnapshot *cacheSnapshot
 
 	fallbackSourceProvider source.Provider
 	// statsOut is the channel where the translator will send its APM statsPayload bytes
@@ -79,6 +94,26 @@ func WithOTelPrefix() TranslatorOption {
 	}
 }
 
+// WithRemappingRules adds custom rules for computing new metrics from OTEL metrics, in the same
+// way certain metrics are remapped to their Datadog counterparts with WithRemapping. Custom rules
+// are applied regardless of whether WithRemapping is set, after the default rules.
+// A custom rule replaces the default rules with the same Match and Name, and a custom rule with
+// Disable set removes them (e.g. to stop computing a Datadog runtime metric).
+//
+// See DefaultRemappingRules for the rules used by WithRemapping and LoadRemappingRules for
+// loading rules from a YAML or JSON document.
+func WithRemappingRules(rules ...RemappingRule) TranslatorOption {
+	return func(t *translatorConfig) error {
+		for _, rule := range rules {
+			if err := rule.Validate(); err != nil {
+				return err
+			}
+			t.remappingRules = append(t.remappingRules, rule.clone())
+		}
+		return nil
+	}
+}
+
 // WithDeltaTTL sets the delta TTL for cumulative metrics datapoints.
 // By default, 3600 seconds are used.
 func WithDeltaTTL(deltaTTL int64) TranslatorOption {
@@ -95,6 +130,54 @@ func WithDeltaTTL(deltaTTL int64) TranslatorOption {
 	}
 }
 
+// WithFilterRules sets rules for dropping metrics by name and removing tags by key from
+// the Datadog metrics produced by the translator, before they are passed to the Consumer.
+// See FilterRule for how rules are evaluated.
+//
+// Filtered metrics are still used for computing deltas from cumulative timeseries. Removing
+// tags may make points from different timeseries end up in the same Datadog timeseries.
+func WithFilterRules(rules ...FilterRule) TranslatorOption {
+	return func(t *translatorConfig) error {
+		t.filterRules = append(t.filterRules, rules...)
+		return nil
+	}
+}
+
+// WithCardinalityLimit limits the number of distinct timeseries of every Datadog metric.
+// Points of timeseries over the limit are dropped or folded into a single overflow timeseries
+// per metric and resource. See CardinalityLimit for details.
+//
+// The limit applies once deltas are computed from cumulative timeseries, so points folded into the
+// overflow timeseries are deltas of their original timeseries. The overflow timeseries may then
+// have several points with the same timestamp. Only CardinalityLimitActionDrop bounds the cache used
+// for computing these deltas.
+func WithCardinalityLimit(limit CardinalityLimit) TranslatorOption {
+	return func(t *translatorConfig) error {
+		if err := limit.Validate(); err != nil {
+			return err
+		}
+		limit.OverflowTags = append([]string(nil), limit.OverflowTags...)
+		t.cardinalityLimit = &limit
+		return nil
+	}
+}
+
+// WithCacheSnapshot restores a snapshot of the cumulative-to-delta cache taken with
+// Translator.SnapshotCache when creating the translator. This allows the translator to
+// keep computing deltas for cumulative timeseries across restarts.
+//
+// Cache entries that have expired since the snapshot was taken are not restored.
+func WithCacheSnapshot(r io.Reader) TranslatorOption {
+	return func(t *translatorConfig) error {
+		snapshot, err := decodeCacheSnapshot(r)
+		if err != nil {
+			return err
+		}
+		t.cacheSnapshot = snapshot
+		return nil
+	}
+}
+
 // WithFallbackSourceProvider sets the fallback source provider.
 // By default, an empty hostname is used as a fallback.
 func WithFallbackSourceProvider(provider source.Provider) TranslatorOption {
@@ -199,6 +282,61 @@ func WithNumberMode(mode NumberMode) TranslatorOption {
 	}
 }
 
+// NonMonotonicSumMode is an export mode for OTLP cumulative non-monotonic Sum metrics.
+type NonMonotonicSumMode string
+
+const (
+	// NonMonotonicSumModeRawValue reports the raw value of cumulative non-monotonic
+	// sums as a Datadog gauge.
+	NonMonotonicSumModeRawValue NonMonotonicSumMode = "raw_value"
+
+	// NonMonotonicSumModeCumulativeToDelta calculates the delta of cumulative
+	// non-monotonic sums in the client side and reports them as Datadog counts.
+	// Deltas may be negative.
+	NonMonotonicSumModeCumulativeToDelta NonMonotonicSumMode = "cumulative_to_delta"
+)
+
+// WithNonMonotonicSumMode sets the export mode for cumulative non-monotonic sums.
+// The default mode is NonMonotonicSumModeRawValue.
+func WithNonMonotonicSumMode(mode NonMonotonicSumMode) TranslatorOption {
+	return func(t *translatorConfig) error {
+		switch mode {
+		case NonMonotonicSumModeRawValue, NonMonotonicSumModeCumulativeToDelta:
+			t.NonMonotonicSumMode = mode
+		default:
+			return fmt.Errorf("unknown non-monotonic sum mode: %q", mode)
+		}
+		return nil
+	}
+}
+
+// SummaryMode is an export mode for OTLP Summary metrics.
+type SummaryMode string
+
+const (
+	// SummaryModeCountSum exports the count and sum of summaries as Datadog counts.
+	SummaryModeCountSum SummaryMode = "count_sum"
+
+	// SummaryModeDistributions exports summaries as Datadog distributions, approximated
+	// from their count, sum and quantiles. Summaries without quantiles are not exported.
+	SummaryModeDistributions SummaryMode = "distributions"
+)
+
+// WithSummaryMode sets the summaries mode. Quantiles are exported as Datadog gauges
+// in addition to the summary mode output when using WithQuantiles.
+// The default mode is SummaryModeCountSum.
+func WithSummaryMode(mode SummaryMode) TranslatorOption {
+	return func(t *translatorConfig) error {
+		switch mode {
+		case SummaryModeCountSum, SummaryModeDistributions:
+			t.SummaryMode = mode
+		default:
+			return fmt.Errorf("unknown summary mode: %q", mode)
+		}
+		return nil
+	}
+}
+
 // WithStatsOut sets the channel where the translator will send its APM statsPayload bytes
 func WithStatsOut(statsOut chan<- []byte) TranslatorOption {
 	return func(t *translatorConfig) error {
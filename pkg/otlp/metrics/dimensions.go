@@ -105,6 +105,19 @@ func (d *Dimensions) AddTags(tags ...string) *Dimensions {
 	}
 }
 
+// withTags creates a new dimensions struct with the given tags instead of the current ones.
+func (d *Dimensions) withTags(tags []string) *Dimensions {
+	return &Dimensions{
+		name:                d.name,
+		tags:                tags,
+		host:                d.host,
+		originID:            d.originID,
+		originProduct:       d.originProduct,
+		originSubProduct:    d.originSubProduct,
+		originProductDetail: d.originProductDetail,
+	}
+}
+
 // WithAttributeMap creates a new metricDimensions struct with additional tags from attributes.
 func (d *Dimensions) WithAttributeMap(labels pcommon.Map) *Dimensions {
 	return d.AddTags(getTags(labels)...)
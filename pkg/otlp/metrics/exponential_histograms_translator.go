@@ -42,14 +42,11 @@ func toStore(b pmetric.ExponentialHistogramDataPointBuckets) store.Store {
 	return store
 }
 
+// exponentialHistogramToDDSketch converts a delta ExponentialHistogram point into a DDSketch.
+// Cumulative points must be converted into delta points with ttlCache.ExponentialHistogramDiff first.
 func (t *Translator) exponentialHistogramToDDSketch(
 	p pmetric.ExponentialHistogramDataPoint,
-	delta bool,
 ) (*ddsketch.DDSketch, error) {
-	if !delta {
-		return nil, fmt.Errorf("cumulative exponential histograms are not supported")
-	}
-
 	// Create the DDSketch stores
 	positiveStore := toStore(p.Positive())
 	negativeStore := toStore(p.Negative())
@@ -82,6 +79,9 @@ func (t *Translator) exponentialHistogramToDDSketch(
 //   - a list of bucket counts
 //
 // - A count of zero values in the population
+//
+// Cumulative points are converted into delta points by diffing them with the previous point
+// of the same timeseries, which is kept in the translator cache.
 func (t *Translator) mapExponentialHistogramMetrics(
 	ctx context.Context,
 	consumer Consumer,
@@ -94,23 +94,26 @@ func (t *Translator) mapExponentialHistogramMetrics(
 		startTs := uint64(p.StartTimestamp())
 		ts := uint64(p.Timestamp())
 		pointDims := dims.WithAttributeMap(p.Attributes())
+		if !delta && !t.limiter.admitCumulative(ctx, pointDims) {
+			continue
+		}
 
 		histInfo := histogramInfo{ok: true}
 
 		countDims := pointDims.WithSuffix("count")
 		if delta {
 			histInfo.count = p.Count()
-		} else if dx, ok := t.prevPts.Diff(countDims, startTs, ts, float64(p.Count())); ok {
+		} else if dx, ok, _ := t.prevPts.Diff(countDims, startTs, ts, float64(p.Count())); ok {
 			histInfo.count = uint64(dx)
 		} else { // not ok
 			histInfo.ok = false
 		}
 
 		sumDims := pointDims.WithSuffix("sum")
-		if !t.isSkippable(sumDims.name, p.Sum()) {
+		if !t.isSkippable(ctx, sumDims.name, p.Sum()) {
 			if delta {
 				histInfo.sum = p.Sum()
-			} else if dx, ok := t.prevPts.Diff(sumDims, startTs, ts, p.Sum()); ok {
+			} else if dx, ok, _ := t.prevPts.Diff(sumDims, startTs, ts, p.Sum()); ok {
 				histInfo.sum = dx
 			} else { // not ok
 				histInfo.ok = false
@@ -119,24 +122,45 @@ func (t *Translator) mapExponentialHistogramMetrics(
 			histInfo.ok = false
 		}
 
+		minDims := pointDims.WithSuffix("min")
+		if p.HasMin() {
+			histInfo.hasMinFromLastTimeWindow = delta || t.prevPts.PutAndCheckMin(minDims, startTs, ts, p.Min())
+		}
+
+		maxDims := pointDims.WithSuffix("max")
+		if p.HasMax() {
+			histInfo.hasMaxFromLastTimeWindow = delta || t.prevPts.PutAndCheckMax(maxDims, startTs, ts, p.Max())
+		}
+
 		if t.cfg.SendHistogramAggregations && histInfo.ok {
 			// We only send the sum and count if both values were ok.
 			consumer.ConsumeTimeSeries(ctx, countDims, Count, ts, 0, float64(histInfo.count))
 			consumer.ConsumeTimeSeries(ctx, sumDims, Count, ts, 0, histInfo.sum)
 
 			if delta {
+				// See mapHistogramMetrics for why we only report min/max for delta points.
 				if p.HasMin() {
-					minDims := pointDims.WithSuffix("min")
 					consumer.ConsumeTimeSeries(ctx, minDims, Gauge, ts, 0, p.Min())
 				}
 				if p.HasMax() {
-					maxDims := pointDims.WithSuffix("max")
 					consumer.ConsumeTimeSeries(ctx, maxDims, Gauge, ts, 0, p.Max())
 				}
 			}
 		}
 
-		expHistDDSketch, err := t.exponentialHistogramToDDSketch(p, delta)
+		deltaPoint := p
+		if !delta {
+			var ok bool
+			var drop dropReason
+			if deltaPoint, ok, drop = t.prevPts.ExponentialHistogramDiff(pointDims, startTs, ts, p); !ok {
+				// First point of the timeseries, out of order point or reset:
+				// we don't have enough information to compute the buckets for the last time window.
+				t.telemetry.dropped(ctx, drop, 1)
+				continue
+			}
+		}
+
+		expHistDDSketch, err := t.exponentialHistogramToDDSketch(deltaPoint)
 		if err != nil {
 			t.logger.Debug("Failed to convert ExponentialHistogram into DDSketch",
 				zap.String("metric name", dims.name),
@@ -167,11 +191,21 @@ func (t *Translator) mapExponentialHistogramMetrics(
 				agentSketch.Basic.Max = histInfo.sum
 			}
 		}
-		if delta && p.HasMin() {
+
+		if histInfo.hasMinFromLastTimeWindow {
+			// We know exact minimum for the last time window.
 			agentSketch.Basic.Min = p.Min()
+		} else if p.HasMin() {
+			// Clamp minimum with the global minimum (p.Min()) to account for sketch mapping error.
+			agentSketch.Basic.Min = math.Max(p.Min(), agentSketch.Basic.Min)
 		}
-		if delta && p.HasMax() {
+
+		if histInfo.hasMaxFromLastTimeWindow {
+			// We know exact maximum for the last time window.
 			agentSketch.Basic.Max = p.Max()
+		} else if p.HasMax() {
+			// Clamp maximum with global maximum (p.Max()) to account for sketch mapping error.
+			agentSketch.Basic.Max = math.Min(p.Max(), agentSketch.Basic.Max)
 		}
 
 		consumer.ConsumeSketch(ctx, pointDims, ts, 0, agentSketch)
@@ -0,0 +1,276 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2023-present Datadog, Inc.
+
+package metrics
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/DataDog/datadog-agent/pkg/util/quantile"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/otel/attribute"
+	otelmetric "go.opentelemetry.io/otel/metric"
+)
+
+const (
+	filteredPointsMetricName string = "datadog.otlp_translator.metrics.filtered_points"
+	filteredTagsMetricName   string = "datadog.otlp_translator.metrics.filtered_tags"
+)
+
+// FilterAction is the action taken on the metrics matched by a FilterRule.
+type FilterAction string
+
+const (
+	// FilterActionInclude keeps the matched metrics, after applying the tag filters of the rule.
+	FilterActionInclude FilterAction = "include"
+	// FilterActionExclude drops the matched metrics.
+	FilterActionExclude FilterAction = "exclude"
+)
+
+// FilterMatchType is the type of the metric name patterns of a FilterRule.
+type FilterMatchType string
+
+const (
+	// FilterMatchTypeGlob matches metric names with glob patterns, where `*` matches
+	// any sequence of characters and `?` matches any single character.
+	FilterMatchTypeGlob FilterMatchType = "glob"
+	// FilterMatchTypeRegexp matches metric names with regular expressions.
+	// Regular expressions must match the whole metric name.
+	FilterMatchTypeRegexp FilterMatchType = "regexp"
+)
+
+// FilterRule is a rule for filtering the Datadog metrics produced by the translator
+// and the tags they have, before they are passed to the Consumer.
+//
+// Rules are evaluated in order, and the first rule matching the name of a metric decides
+// what happens to it. Metrics that no rule matches are kept as is. To only keep a set of
+// metrics, add an exclude rule with no patterns after the include rules.
+type FilterRule struct {
+	// Name identifies the rule in the translator telemetry. It must be unique.
+	Name string
+	// Action is the action taken on the metrics matched by the rule.
+	Action FilterAction
+	// MatchType is the type of the MetricNames patterns. Defaults to FilterMatchTypeGlob.
+	MatchType FilterMatchType
+	// MetricNames are the patterns the Datadog metric names are matched against.
+	// A rule without patterns matches all metrics.
+	MetricNames []string
+	// AllowTagKeys is the list of the only tag keys kept on the matched metrics.
+	// If empty, all tag keys are allowed. Only valid on include rules.
+	AllowTagKeys []string
+	// DenyTagKeys is the list of tag keys removed from the matched metrics.
+	// Only valid on include rules.
+	DenyTagKeys []string
+}
+
+// filterRule is the compiled version of a FilterRule.
+type filterRule struct {
+	name     string
+	exclude  bool
+	patterns []*regexp.Regexp
+	allow    map[string]struct{}
+	deny     map[string]struct{}
+	attrs    otelmetric.MeasurementOption
+}
+
+func globToRegexp(glob string) string {
+	var b strings.Builder
+	for _, c := range glob {
+		switch c {
+		case '*':
+			b.WriteString(".*")
+		case '?':
+			b.WriteString(".")
+		default:
+			b.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	return b.String()
+}
+
+func toSet(keys []string) map[string]struct{} {
+	if len(keys) == 0 {
+		return nil
+	}
+	set := make(map[string]struct{}, len(keys))
+	for _, key := range keys {
+		set[key] = struct{}{}
+	}
+	return set
+}
+
+func newFilterRule(r FilterRule) (filterRule, error) {
+	if r.Name == "" {
+		return filterRule{}, errors.New("filter rule must have a name")
+	}
+
+	rule := filterRule{
+		name:  r.Name,
+		allow: toSet(r.AllowTagKeys),
+		deny:  toSet(r.DenyTagKeys),
+		attrs: otelmetric.WithAttributeSet(attribute.NewSet(attribute.String("rule", r.Name))),
+	}
+
+	switch r.Action {
+	case FilterActionInclude:
+	case FilterActionExclude:
+		rule.exclude = true
+		if rule.allow != nil || rule.deny != nil {
+			return filterRule{}, fmt.Errorf("filter rule %q: tag keys can only be filtered on include rules", r.Name)
+		}
+	default:
+		return filterRule{}, fmt.Errorf("filter rule %q: unknown action %q", r.Name, r.Action)
+	}
+
+	for _, pattern := range r.MetricNames {
+		switch r.MatchType {
+		case FilterMatchTypeGlob, "":
+			pattern = globToRegexp(pattern)
+		case FilterMatchTypeRegexp:
+		default:
+			return filterRule{}, fmt.Errorf("filter rule %q: unknown match type %q", r.Name, r.MatchType)
+		}
+		re, err := regexp.Compile("^(?:" + pattern + ")$")
+		if err != nil {
+			return filterRule{}, fmt.Errorf("filter rule %q: invalid pattern: %w", r.Name, err)
+		}
+		rule.patterns = append(rule.patterns, re)
+	}
+	return rule, nil
+}
+
+func (r *filterRule) matches(name string) bool {
+	if len(r.patterns) == 0 {
+		return true
+	}
+	for _, re := range r.patterns {
+		if re.MatchString(name) {
+			return true
+		}
+	}
+	return false
+}
+
+// filterTags returns the tags of dims allowed by the rule and the number of tags removed.
+func (r *filterRule) filterTags(dims *Dimensions) (*Dimensions, int) {
+	if r.allow == nil && r.deny == nil {
+		return dims, 0
+	}
+
+	tags := make([]string, 0, len(dims.tags))
+	for _, tag := range dims.tags {
+		key, _, _ := strings.Cut(tag, ":")
+		if _, ok := r.allow[key]; r.allow != nil && !ok {
+			continue
+		}
+		if _, ok := r.deny[key]; ok {
+			continue
+		}
+		tags = append(tags, tag)
+	}
+
+	removed := len(dims.tags) - len(tags)
+	if removed == 0 {
+		return dims, 0
+	}
+	return dims.withTags(tags), removed
+}
+
+// metricFilter applies a list of filter rules.
+type metricFilter struct {
+	rules          []filterRule
+	filteredPoints otelmetric.Int64Counter
+	filteredTags   otelmetric.Int64Counter
+}
+
+func newMetricFilter(set component.TelemetrySettings, rules []FilterRule) (*metricFilter, error) {
+	f := &metricFilter{}
+	names := make(map[string]struct{}, len(rules))
+	for _, r := range rules {
+		if _, ok := names[r.Name]; ok {
+			return nil, fmt.Errorf("duplicate filter rule name %q", r.Name)
+		}
+		names[r.Name] = struct{}{}
+
+		rule, err := newFilterRule(r)
+		if err != nil {
+			return nil, err
+		}
+		f.rules = append(f.rules, rule)
+	}
+
+	meter := set.MeterProvider.Meter(meterName)
+	var err error
+	f.filteredPoints, err = meter.Int64Counter(
+		filteredPointsMetricName,
+		otelmetric.WithDescription("Datadog metric points dropped by a filter rule"),
+		otelmetric.WithUnit("{point}"),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build filtered points counter: %w", err)
+	}
+	f.filteredTags, err = meter.Int64Counter(
+		filteredTagsMetricName,
+		otelmetric.WithDescription("Tags removed from Datadog metric points by a filter rule"),
+		otelmetric.WithUnit("{tag}"),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build filtered tags counter: %w", err)
+	}
+	return f, nil
+}
+
+// apply returns the dimensions to consume, or false if the point must be dropped.
+func (f *metricFilter) apply(ctx context.Context, dims *Dimensions) (*Dimensions, bool) {
+	for i := range f.rules {
+		rule := &f.rules[i]
+		if !rule.matches(dims.name) {
+			continue
+		}
+		if rule.exclude {
+			f.filteredPoints.Add(ctx, 1, rule.attrs)
+			return nil, false
+		}
+		filtered, removed := rule.filterTags(dims)
+		if removed > 0 {
+			f.filteredTags.Add(ctx, int64(removed), rule.attrs)
+		}
+		return filtered, true
+	}
+	return dims, true
+}
+
+// filteringConsumer applies a metricFilter before passing metrics to the wrapped Consumer.
+type filteringConsumer struct {
+	Consumer
+	filter *metricFilter
+}
+
+var _ Consumer = (*filteringConsumer)(nil)
+
+// wrap returns a Consumer that filters metrics before passing them to consumer.
+// If f is nil, consumer is returned as is.
+func (f *metricFilter) wrap(consumer Consumer) Consumer {
+	if f == nil {
+		return consumer
+	}
+	return &filteringConsumer{Consumer: consumer, filter: f}
+}
+
+func (c *filteringConsumer) ConsumeTimeSeries(ctx context.Context, dimensions *Dimensions, typ DataType, timestamp uint64, interval int64, value float64) {
+	if dims, ok := c.filter.apply(ctx, dimensions); ok {
+		c.Consumer.ConsumeTimeSeries(ctx, dims, typ, timestamp, interval, value)
+	}
+}
+
+func (c *filteringConsumer) ConsumeSketch(ctx context.Context, dimensions *Dimensions, timestamp uint64, interval int64, sketch *quantile.Sketch) {
+	if dims, ok := c.filter.apply(ctx, dimensions); ok {
+		c.Consumer.ConsumeSketch(ctx, dims, timestamp, interval, sketch)
+	}
+}
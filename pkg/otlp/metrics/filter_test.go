@@ -0,0 +1,211 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2023-present Datadog, Inc.
+
+package metrics
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+// sumValues returns the values of the int64 sum metric with the given name, keyed by the value of the given attribute.
+func sumValues(t *testing.T, rm *metricdata.ResourceMetrics, name string, key attribute.Key) map[string]int64 {
+	values := map[string]int64{}
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			if m.Name != name {
+				continue
+			}
+			sum, ok := m.Data.(metricdata.Sum[int64])
+			require.True(t, ok, "metric %s is not an int64 sum", name)
+			for _, dp := range sum.DataPoints {
+				value, _ := dp.Attributes.Value(key)
+				values[value.AsString()] += dp.Value
+			}
+		}
+	}
+	return values
+}
+
+func TestNewMetricFilterErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		rules []FilterRule
+		err   string
+	}{
+		{
+			name:  "missing name",
+			rules: []FilterRule{{Action: FilterActionExclude}},
+			err:   "filter rule must have a name",
+		},
+		{
+			name: "duplicate name",
+			rules: []FilterRule{
+				{Name: "rule", Action: FilterActionExclude},
+				{Name: "rule", Action: FilterActionInclude},
+			},
+			err: `duplicate filter rule name "rule"`,
+		},
+		{
+			name:  "unknown action",
+			rules: []FilterRule{{Name: "rule", Action: "drop"}},
+			err:   `filter rule "rule": unknown action "drop"`,
+		},
+		{
+			name:  "unknown match type",
+			rules: []FilterRule{{Name: "rule", Action: FilterActionExclude, MatchType: "strict", MetricNames: []string{"foo"}}},
+			err:   `filter rule "rule": unknown match type "strict"`,
+		},
+		{
+			name:  "invalid regexp",
+			rules: []FilterRule{{Name: "rule", Action: FilterActionExclude, MatchType: FilterMatchTypeRegexp, MetricNames: []string{"foo("}}},
+			err:   `filter rule "rule": invalid pattern: error parsing regexp: missing closing ): ` + "`^(?:foo()$`",
+		},
+		{
+			name:  "tag keys on exclude rule",
+			rules: []FilterRule{{Name: "rule", Action: FilterActionExclude, DenyTagKeys: []string{"env"}}},
+			err:   `filter rule "rule": tag keys can only be filtered on include rules`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := newMetricFilter(componenttest.NewNopTelemetrySettings(), tt.rules)
+			assert.EqualError(t, err, tt.err)
+		})
+	}
+}
+
+func TestMetricFilterApply(t *testing.T) {
+	rules := []FilterRule{
+		{
+			Name:        "drop-debug",
+			Action:      FilterActionExclude,
+			MetricNames: []string{"*.debug", "internal.?"},
+		},
+		{
+			Name:         "http-allow",
+			Action:       FilterActionInclude,
+			MatchType:    FilterMatchTypeRegexp,
+			MetricNames:  []string{`http\.server\..+`},
+			AllowTagKeys: []string{"env", "http.method"},
+		},
+		{
+			Name:        "deny-pod",
+			Action:      FilterActionInclude,
+			MetricNames: []string{"k8s.*"},
+			DenyTagKeys: []string{"pod_name"},
+		},
+		{
+			Name:   "drop-rest",
+			Action: FilterActionExclude,
+		},
+	}
+
+	set := componenttest.NewNopTelemetrySettings()
+	reader := sdkmetric.NewManualReader()
+	set.MeterProvider = sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+	filter, err := newMetricFilter(set, rules)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name         string
+		dims         *Dimensions
+		expectedDrop bool
+		expectedTags []string
+	}{
+		{
+			name:         "glob exclude",
+			dims:         &Dimensions{name: "app.requests.debug", tags: []string{"env:prod"}},
+			expectedDrop: true,
+		},
+		{
+			name:         "glob single character",
+			dims:         &Dimensions{name: "internal.a"},
+			expectedDrop: true,
+		},
+		{
+			name:         "regexp include with allowed tag keys",
+			dims:         &Dimensions{name: "http.server.duration", tags: []string{"env:prod", "http.method:GET", "http.url:/foo", "team"}},
+			expectedTags: []string{"env:prod", "http.method:GET"},
+		},
+		{
+			name:         "glob include with denied tag keys",
+			dims:         &Dimensions{name: "k8s.pod.cpu", tags: []string{"env:prod", "pod_name:foo"}},
+			expectedTags: []string{"env:prod"},
+		},
+		{
+			name:         "include without removed tags",
+			dims:         &Dimensions{name: "k8s.node.cpu", tags: []string{"env:prod"}},
+			expectedTags: []string{"env:prod"},
+		},
+		{
+			name:         "catch-all exclude",
+			dims:         &Dimensions{name: "glob.not.matched", tags: []string{"env:prod"}},
+			expectedDrop: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dims, ok := filter.apply(context.Background(), tt.dims)
+			if tt.expectedDrop {
+				assert.False(t, ok)
+				return
+			}
+			require.True(t, ok)
+			assert.Equal(t, tt.dims.name, dims.name)
+			assert.Equal(t, tt.expectedTags, dims.tags)
+		})
+	}
+
+	rm := &metricdata.ResourceMetrics{}
+	require.NoError(t, reader.Collect(context.Background(), rm))
+	assert.Equal(t, map[string]int64{"drop-debug": 2, "drop-rest": 1}, sumValues(t, rm, filteredPointsMetricName, "rule"))
+	assert.Equal(t, map[string]int64{"http-allow": 2, "deny-pod": 1}, sumValues(t, rm, filteredTagsMetricName, "rule"))
+}
+
+func TestMetricFilterNoRules(t *testing.T) {
+	var filter *metricFilter
+	consumer := &mockFullConsumer{}
+	assert.Same(t, consumer, filter.wrap(consumer))
+}
+
+func TestWithFilterRules(t *testing.T) {
+	md := pmetric.NewMetrics()
+	ms := md.ResourceMetrics().AppendEmpty().ScopeMetrics().AppendEmpty().Metrics()
+	testMetric("app.kept", testPoint{f: 1, attrs: map[string]any{"env": "prod", "pod_name": "foo"}}).CopyTo(ms.AppendEmpty())
+	testMetric("app.dropped", testPoint{f: 2}).CopyTo(ms.AppendEmpty())
+	hist := ms.AppendEmpty()
+	hist.SetName("app.latency")
+	dp := hist.SetEmptyExponentialHistogram().DataPoints().AppendEmpty()
+	hist.ExponentialHistogram().SetAggregationTemporality(pmetric.AggregationTemporalityDelta)
+	dp.SetCount(1)
+	dp.Positive().BucketCounts().FromRaw([]uint64{1})
+
+	translator := NewTestTranslator(t, WithFilterRules(
+		FilterRule{Name: "deny-pod", Action: FilterActionInclude, MetricNames: []string{"app.kept"}, DenyTagKeys: []string{"pod_name"}},
+		FilterRule{Name: "drop", Action: FilterActionExclude, MetricNames: []string{"app.dropped", "app.latency"}},
+	))
+	consumer := &mockFullConsumer{}
+	_, err := translator.MapMetrics(context.Background(), md, consumer, nil)
+	require.NoError(t, err)
+
+	require.Len(t, consumer.metrics, 1)
+	assert.Equal(t, "app.kept", consumer.metrics[0].name)
+	assert.Equal(t, []string{"env:prod"}, consumer.metrics[0].tags)
+	assert.Empty(t, consumer.sketches)
+
+	_, err = NewTranslator(componenttest.NewNopTelemetrySettings(), nil, WithFilterRules(FilterRule{Action: FilterActionExclude}))
+	assert.EqualError(t, err, "filter rule must have a name")
+}
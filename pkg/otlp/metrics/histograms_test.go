@@ -10,8 +10,8 @@ import (
 	"os"
 	"testing"
 
-	"github.com/DataDog/datadog-agent/pkg/opentelemetry-mapping-go/otlp/attributes"
 	"github.com/DataDog/datadog-agent/pkg/util/quantile"
+	"github.com/DataDog/opentelemetry-mapping-go/pkg/otlp/attributes"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/collector/component/componenttest"
@@ -259,7 +259,6 @@ func TestExponentialHistogramTranslatorOptions(t *testing.T) {
 			otlpfile:                  "testdata/otlpdata/histogram/simple-exponential.json",
 			ddogfile:                  "testdata/datadogdata/histogram/simple-exponential.json",
 			expectedUnknownMetricType: 1,
-			expectedUnsupportedAggregationTemporality: 1,
 		},
 		{
 			// https://github.com/open-telemetry/opentelemetry-collector-contrib/issues/26103
@@ -272,7 +271,6 @@ func TestExponentialHistogramTranslatorOptions(t *testing.T) {
 			name:     "empty-cumulative-issue-26103",
 			otlpfile: "testdata/otlpdata/histogram/empty-cumulative-exponential.json",
 			ddogfile: "testdata/datadogdata/histogram/empty-cumulative-exponential.json",
-			expectedUnsupportedAggregationTemporality: 1,
 		},
 		{
 			name:                      "resource-attributes-as-tags",
@@ -280,7 +278,6 @@ func TestExponentialHistogramTranslatorOptions(t *testing.T) {
 			ddogfile:                  "testdata/datadogdata/histogram/simple-exponential_res-tags.json",
 			options:                   []TranslatorOption{},
 			expectedUnknownMetricType: 1,
-			expectedUnsupportedAggregationTemporality: 1,
 		},
 		{
 			name:     "count-sum",
@@ -289,8 +286,7 @@ func TestExponentialHistogramTranslatorOptions(t *testing.T) {
 			options: []TranslatorOption{
 				WithHistogramAggregations(),
 			},
-			expectedUnknownMetricType:                 1,
-			expectedUnsupportedAggregationTemporality: 1,
+			expectedUnknownMetricType: 1,
 		},
 		{
 			name:     "instrumentation-library-metadata-as-tags",
@@ -299,8 +295,7 @@ func TestExponentialHistogramTranslatorOptions(t *testing.T) {
 			options: []TranslatorOption{
 				WithInstrumentationLibraryMetadataAsTags(),
 			},
-			expectedUnknownMetricType:                 1,
-			expectedUnsupportedAggregationTemporality: 1,
+			expectedUnknownMetricType: 1,
 		},
 		{
 			name:     "instrumentation-scope-metadata-as-tags",
@@ -309,8 +304,7 @@ func TestExponentialHistogramTranslatorOptions(t *testing.T) {
 			options: []TranslatorOption{
 				WithInstrumentationScopeMetadataAsTags(),
 			},
-			expectedUnknownMetricType:                 1,
-			expectedUnsupportedAggregationTemporality: 1,
+			expectedUnknownMetricType: 1,
 		},
 		{
 			name:     "count-sum-instrumentation-library-metadata-as-tags",
@@ -320,8 +314,7 @@ func TestExponentialHistogramTranslatorOptions(t *testing.T) {
 				WithHistogramAggregations(),
 				WithInstrumentationLibraryMetadataAsTags(),
 			},
-			expectedUnknownMetricType:                 1,
-			expectedUnsupportedAggregationTemporality: 1,
+			expectedUnknownMetricType: 1,
 		},
 		{
 			name:     "resource-tags-instrumentation-library-metadata-as-tags",
@@ -330,8 +323,7 @@ func TestExponentialHistogramTranslatorOptions(t *testing.T) {
 			options: []TranslatorOption{
 				WithInstrumentationLibraryMetadataAsTags(),
 			},
-			expectedUnknownMetricType:                 1,
-			expectedUnsupportedAggregationTemporality: 1,
+			expectedUnknownMetricType: 1,
 		},
 		{
 			name:     "count-sum-resource-tags-instrumentation-library-metadata-as-tags",
@@ -341,8 +333,7 @@ func TestExponentialHistogramTranslatorOptions(t *testing.T) {
 				WithHistogramAggregations(),
 				WithInstrumentationLibraryMetadataAsTags(),
 			},
-			expectedUnknownMetricType:                 1,
-			expectedUnsupportedAggregationTemporality: 1,
+			expectedUnknownMetricType: 1,
 		},
 		{
 			name:     "with-all",
@@ -353,8 +344,20 @@ func TestExponentialHistogramTranslatorOptions(t *testing.T) {
 				WithInstrumentationLibraryMetadataAsTags(),
 				WithInstrumentationScopeMetadataAsTags(),
 			},
-			expectedUnknownMetricType:                 1,
-			expectedUnsupportedAggregationTemporality: 1,
+			expectedUnknownMetricType: 1,
+		},
+		{
+			name:     "cumulative",
+			otlpfile: "testdata/otlpdata/histogram/multiple-cumulative-exponential.json",
+			ddogfile: "testdata/datadogdata/histogram/multiple-cumulative-exponential.json",
+		},
+		{
+			name:     "cumulative-count-sum",
+			otlpfile: "testdata/otlpdata/histogram/multiple-cumulative-exponential.json",
+			ddogfile: "testdata/datadogdata/histogram/multiple-cumulative-exponential_cs.json",
+			options: []TranslatorOption{
+				WithHistogramAggregations(),
+			},
 		},
 		{
 			name:     "single-point-no-min-max",
@@ -1,10 +1,6 @@
 package metrics
 
-import (
-	"strings"
-
-	"go.opentelemetry.io/collector/pdata/pmetric"
-)
+import "go.opentelemetry.io/collector/pdata/pmetric"
 
 // kafkaMetricsToRename is a map of kafka metrics that should be renamed.
 var kafkaMetricsToRename = map[string]bool{
@@ -18,426 +14,476 @@ var kafkaMetricsToRename = map[string]bool{
 	"kafka.producer.record-error-rate":   true,
 }
 
+// kafkaRemappingRules contains the rules for computing Datadog Kafka metrics from their
+// OpenTelemetry counterparts.
+//
 // Note: `-` get converted into `_` which will result in some OTel and DD metric
 // having the same name. In order to prevent duplicate stats, prepend by `otel.`
 // in these cases.
-func remapKafkaMetrics(all pmetric.MetricSlice, m pmetric.Metric) {
-	name := m.Name()
-	if !strings.HasPrefix(name, "kafka.") {
-		// not a kafka metric
-		return
-	}
-	switch name {
+var kafkaRemappingRules = []RemappingRule{
 	// OOTB Kafka Dashboard
-	case "kafka.producer.request-rate":
-		copyMetricWithAttr(all, m, "kafka.producer.request_rate", 1,
-			attributesMapping{
-				fixed: map[string]string{"type": "producer-metrics"},
-			},
-		)
-	case "kafka.producer.response-rate":
-		copyMetricWithAttr(all, m, "kafka.producer.response_rate", 1,
-			attributesMapping{
-				fixed: map[string]string{"type": "producer-metrics"},
-			},
-		)
-	case "kafka.producer.request-latency-avg":
-		copyMetricWithAttr(all, m, "kafka.producer.request_latency_avg", 1,
-			attributesMapping{
-				fixed: map[string]string{"type": "producer-metrics"},
-			},
-		)
-	case "kafka.producer.outgoing-byte-rate":
-		copyMetricWithAttr(all, m, "kafka.producer.bytes_out", 1,
-			attributesMapping{
-				fixed: map[string]string{"type": "producer-metrics"},
-			},
-		)
-	case "kafka.producer.io-wait-time-ns-avg":
-		copyMetricWithAttr(all, m, "kafka.producer.io_wait", 1,
-			attributesMapping{
-				fixed: map[string]string{"type": "producer-metrics"},
-			},
-		)
-	case "kafka.producer.byte-rate":
-		copyMetricWithAttr(all, m, "kafka.producer.bytes_out", 1,
-			attributesMapping{
-				fixed:   map[string]string{"type": "producer-topic-metrics"},
-				dynamic: map[string]string{"client-id": "client"},
-			},
-		)
-	case "kafka.consumer.total.bytes-consumed-rate":
-		copyMetricWithAttr(all, m, "kafka.consumer.bytes_in", 1,
-			attributesMapping{
-				fixed: map[string]string{"type": "consumer-fetch-manager-metrics"},
-			},
-		)
-	case "kafka.consumer.total.records-consumed-rate":
-		copyMetricWithAttr(all, m, "kafka.consumer.messages_in", 1,
-			attributesMapping{
-				fixed: map[string]string{"type": "consumer-fetch-manager-metrics"},
-			},
-		)
-	case "kafka.network.io":
-		copyMetricWithAttr(all, m, "kafka.net.bytes_out.rate", 1,
-			attributesMapping{
-				fixed: map[string]string{
-					"type": "BrokerTopicMetrics",
-					"name": "BytesOutPerSec",
-				},
-			},
-			kv{"state", "out"},
-		)
-		copyMetricWithAttr(all, m, "kafka.net.bytes_in.rate", 1,
-			attributesMapping{
-				fixed: map[string]string{
-					"type": "BrokerTopicMetrics",
-					"name": "BytesInPerSec",
-				}},
-			kv{"state", "in"},
-		)
-	case "kafka.purgatory.size":
-		copyMetricWithAttr(all, m, "kafka.request.producer_request_purgatory.size", 1,
-			attributesMapping{
-				fixed: map[string]string{
-					"type":             "DelayedOperationPurgatory",
-					"name":             "PurgatorySize",
-					"delayedOperation": "Produce",
-				},
-			},
-			kv{"type", "produce"},
-		)
-		copyMetricWithAttr(all, m, "kafka.request.fetch_request_purgatory.size", 1,
-			attributesMapping{
-				fixed: map[string]string{
-					"type":             "DelayedOperationPurgatory",
-					"name":             "PurgatorySize",
-					"delayedOperation": "Fetch",
-				},
-			},
-			kv{"type", "fetch"},
-		)
-	case "kafka.partition.under_replicated":
-		copyMetricWithAttr(all, m, "kafka.replication.under_replicated_partitions", 1,
-			attributesMapping{
-				fixed: map[string]string{
-					"type": "ReplicaManager",
-					"name": "UnderReplicatedPartitions",
-				},
-			},
-		)
-	case "kafka.isr.operation.count":
-		copyMetricWithAttr(all, m, "kafka.replication.isr_shrinks.rate", 1,
-			attributesMapping{
-				fixed: map[string]string{
-					"type": "ReplicaManager",
-					"name": "IsrShrinksPerSec",
-				},
-			},
-			kv{"operation", "shrink"},
-		)
-		copyMetricWithAttr(all, m, "kafka.replication.isr_expands.rate", 1,
-			attributesMapping{
-				fixed: map[string]string{
-					"type": "ReplicaManager",
-					"name": "IsrExpandsPerSec",
-				},
-			},
-			kv{"operation", "expand"},
-		)
-	case "kafka.leader.election.rate":
-		copyMetricWithAttr(all, m, "kafka.replication.leader_elections.rate", 1,
-			attributesMapping{
-				fixed: map[string]string{
-					"type": "ControllerStats",
-					"name": "LeaderElectionRateAndTimeMs",
-				},
-			},
-		)
-	case "kafka.partition.offline":
-		copyMetricWithAttr(all, m, "kafka.replication.offline_partitions_count", 1,
-			attributesMapping{
-				fixed: map[string]string{
-					"type": "KafkaController",
-					"name": "OfflinePartitionsCount",
-				},
-			},
-		)
-	case "kafka.request.time.avg":
-		copyMetricWithAttr(all, m, "kafka.request.produce.time.avg", 1,
-			attributesMapping{
-				fixed: map[string]string{
-					"type":    "RequestMetrics",
-					"name":    "TotalTimeMs",
-					"request": "Produce",
-				},
-			},
-			kv{"type", "produce"},
-		)
-		copyMetricWithAttr(all, m, "kafka.request.fetch_consumer.time.avg", 1,
-			attributesMapping{
-				fixed: map[string]string{
-					"type":    "RequestMetrics",
-					"name":    "TotalTimeMs",
-					"request": "FetchConsumer",
-				},
-			},
-			kv{"type", "fetchconsumer"},
-		)
-		copyMetricWithAttr(all, m, "kafka.request.fetch_follower.time.avg", 1,
-			attributesMapping{
-				fixed: map[string]string{
-					"type":    "RequestMetrics",
-					"name":    "TotalTimeMs",
-					"request": "FetchFollower",
-				},
-			},
-			kv{"type", "fetchfollower"},
-		)
+	{
+		Match: "kafka.producer.request-rate",
+		Name:  "kafka.producer.request_rate",
+		FixedAttributes: map[string]string{
+			"type": "producer-metrics",
+		},
+	},
+	{
+		Match: "kafka.producer.response-rate",
+		Name:  "kafka.producer.response_rate",
+		FixedAttributes: map[string]string{
+			"type": "producer-metrics",
+		},
+	},
+	{
+		Match: "kafka.producer.request-latency-avg",
+		Name:  "kafka.producer.request_latency_avg",
+		FixedAttributes: map[string]string{
+			"type": "producer-metrics",
+		},
+	},
+	{
+		Match: "kafka.producer.outgoing-byte-rate",
+		Name:  "kafka.producer.bytes_out",
+		FixedAttributes: map[string]string{
+			"type": "producer-metrics",
+		},
+	},
+	{
+		Match: "kafka.producer.io-wait-time-ns-avg",
+		Name:  "kafka.producer.io_wait",
+		FixedAttributes: map[string]string{
+			"type": "producer-metrics",
+		},
+	},
+	{
+		Match: "kafka.producer.byte-rate",
+		Name:  "kafka.producer.bytes_out",
+		FixedAttributes: map[string]string{
+			"type": "producer-topic-metrics",
+		},
+		AttributesMapping: map[string]string{
+			"client-id": "client",
+		},
+	},
+	{
+		Match: "kafka.consumer.total.bytes-consumed-rate",
+		Name:  "kafka.consumer.bytes_in",
+		FixedAttributes: map[string]string{
+			"type": "consumer-fetch-manager-metrics",
+		},
+	},
+	{
+		Match: "kafka.consumer.total.records-consumed-rate",
+		Name:  "kafka.consumer.messages_in",
+		FixedAttributes: map[string]string{
+			"type": "consumer-fetch-manager-metrics",
+		},
+	},
+	{
+		Match: "kafka.network.io",
+		Name:  "kafka.net.bytes_out.rate",
+		Filters: []AttributeFilter{
+			{Key: "state", Value: "out"},
+		},
+		FixedAttributes: map[string]string{
+			"type": "BrokerTopicMetrics",
+			"name": "BytesOutPerSec",
+		},
+	},
+	{
+		Match: "kafka.network.io",
+		Name:  "kafka.net.bytes_in.rate",
+		Filters: []AttributeFilter{
+			{Key: "state", Value: "in"},
+		},
+		FixedAttributes: map[string]string{
+			"type": "BrokerTopicMetrics",
+			"name": "BytesInPerSec",
+		},
+	},
+	{
+		Match: "kafka.purgatory.size",
+		Name:  "kafka.request.producer_request_purgatory.size",
+		Filters: []AttributeFilter{
+			{Key: "type", Value: "produce"},
+		},
+		FixedAttributes: map[string]string{
+			"type":             "DelayedOperationPurgatory",
+			"name":             "PurgatorySize",
+			"delayedOperation": "Produce",
+		},
+	},
+	{
+		Match: "kafka.purgatory.size",
+		Name:  "kafka.request.fetch_request_purgatory.size",
+		Filters: []AttributeFilter{
+			{Key: "type", Value: "fetch"},
+		},
+		FixedAttributes: map[string]string{
+			"type":             "DelayedOperationPurgatory",
+			"name":             "PurgatorySize",
+			"delayedOperation": "Fetch",
+		},
+	},
+	{
+		Match: "kafka.partition.under_replicated",
+		Name:  "kafka.replication.under_replicated_partitions",
+		FixedAttributes: map[string]string{
+			"type": "ReplicaManager",
+			"name": "UnderReplicatedPartitions",
+		},
+	},
+	{
+		Match: "kafka.isr.operation.count",
+		Name:  "kafka.replication.isr_shrinks.rate",
+		Filters: []AttributeFilter{
+			{Key: "operation", Value: "shrink"},
+		},
+		FixedAttributes: map[string]string{
+			"type": "ReplicaManager",
+			"name": "IsrShrinksPerSec",
+		},
+	},
+	{
+		Match: "kafka.isr.operation.count",
+		Name:  "kafka.replication.isr_expands.rate",
+		Filters: []AttributeFilter{
+			{Key: "operation", Value: "expand"},
+		},
+		FixedAttributes: map[string]string{
+			"type": "ReplicaManager",
+			"name": "IsrExpandsPerSec",
+		},
+	},
+	{
+		Match: "kafka.leader.election.rate",
+		Name:  "kafka.replication.leader_elections.rate",
+		FixedAttributes: map[string]string{
+			"type": "ControllerStats",
+			"name": "LeaderElectionRateAndTimeMs",
+		},
+	},
+	{
+		Match: "kafka.partition.offline",
+		Name:  "kafka.replication.offline_partitions_count",
+		FixedAttributes: map[string]string{
+			"type": "KafkaController",
+			"name": "OfflinePartitionsCount",
+		},
+	},
+	{
+		Match: "kafka.request.time.avg",
+		Name:  "kafka.request.produce.time.avg",
+		Filters: []AttributeFilter{
+			{Key: "type", Value: "produce"},
+		},
+		FixedAttributes: map[string]string{
+			"type":    "RequestMetrics",
+			"name":    "TotalTimeMs",
+			"request": "Produce",
+		},
+	},
+	{
+		Match: "kafka.request.time.avg",
+		Name:  "kafka.request.fetch_consumer.time.avg",
+		Filters: []AttributeFilter{
+			{Key: "type", Value: "fetchconsumer"},
+		},
+		FixedAttributes: map[string]string{
+			"type":    "RequestMetrics",
+			"name":    "TotalTimeMs",
+			"request": "FetchConsumer",
+		},
+	},
+	{
+		Match: "kafka.request.time.avg",
+		Name:  "kafka.request.fetch_follower.time.avg",
+		Filters: []AttributeFilter{
+			{Key: "type", Value: "fetchfollower"},
+		},
+		FixedAttributes: map[string]string{
+			"type":    "RequestMetrics",
+			"name":    "TotalTimeMs",
+			"request": "FetchFollower",
+		},
+	},
 	// non-dashboard metrics
-	case "kafka.message.count":
-		copyMetricWithAttr(all, m, "kafka.messages_in.rate", 1,
-			attributesMapping{
-				fixed: map[string]string{
-					"type": "BrokerTopicMetrics",
-					"name": "MessagesInPerSec",
-				},
-			},
-		)
-	case "kafka.request.failed":
-		copyMetricWithAttr(all, m, "kafka.request.produce.failed.rate", 1,
-			attributesMapping{
-				fixed: map[string]string{
-					"type": "BrokerTopicMetrics",
-					"name": "FailedProduceRequestsPerSec",
-				},
-			},
-			kv{"type", "produce"},
-		)
-		copyMetricWithAttr(all, m, "kafka.request.fetch.failed.rate", 1,
-			attributesMapping{
-				fixed: map[string]string{
-					"type": "BrokerTopicMetrics",
-					"name": "FailedFetchRequestsPerSec",
-				}},
-			kv{"type", "fetch"},
-		)
-	case "kafka.request.time.99p":
-		copyMetricWithAttr(all, m, "kafka.request.produce.time.99percentile", 1,
-			attributesMapping{
-				fixed: map[string]string{
-					"type":    "RequestMetrics",
-					"name":    "TotalTimeMs",
-					"request": "Produce",
-				},
-			},
-			kv{"type", "produce"},
-		)
-		copyMetricWithAttr(all, m, "kafka.request.fetch_consumer.time.99percentile", 1,
-			attributesMapping{
-				fixed: map[string]string{
-					"type":    "RequestMetrics",
-					"name":    "TotalTimeMs",
-					"request": "FetchConsumer",
-				},
-			},
-			kv{"type", "fetchconsumer"},
-		)
-		copyMetricWithAttr(all, m, "kafka.request.fetch_follower.time.99percentile", 1,
-			attributesMapping{
-				fixed: map[string]string{
-					"type":    "RequestMetrics",
-					"name":    "TotalTimeMs",
-					"request": "FetchFollower",
-				},
-			},
-			kv{"type", "fetchfollower"},
-		)
-	case "kafka.partition.count":
-		copyMetricWithAttr(all, m, "kafka.replication.partition_count", 1,
-			attributesMapping{
-				fixed: map[string]string{
-					"type": "ReplicaManager",
-					"name": "PartitionCount",
-				},
-			},
-		)
-	case "kafka.max.lag":
-		copyMetricWithAttr(all, m, "kafka.replication.max_lag", 1,
-			attributesMapping{
-				fixed: map[string]string{
-					"type":     "ReplicaFetcherManager",
-					"name":     "MaxLag",
-					"clientId": "replica",
-				},
-			},
-		)
-	case "kafka.controller.active.count":
-		copyMetricWithAttr(all, m, "kafka.replication.active_controller_count", 1,
-			attributesMapping{
-				fixed: map[string]string{
-					"type": "KafkaController",
-					"name": "ActiveControllerCount",
-				},
-			},
-		)
-	case "kafka.unclean.election.rate":
-		copyMetricWithAttr(all, m, "kafka.replication.unclean_leader_elections.rate", 1,
-			attributesMapping{
-				fixed: map[string]string{
-					"type": "ControllerStats",
-					"name": "UncleanLeaderElectionsPerSec",
-				},
-			},
-		)
-	case "kafka.request.queue":
-		copyMetricWithAttr(all, m, "kafka.request.channel.queue.size", 1,
-			attributesMapping{
-				fixed: map[string]string{
-					"type": "RequestChannel",
-					"name": "RequestQueueSize",
-				},
-			},
-		)
-	case "kafka.logs.flush.time.count":
-		copyMetricWithAttr(all, m, "kafka.log.flush_rate.rate", 1,
-			attributesMapping{
-				fixed: map[string]string{
-					"type": "LogFlushStats",
-					"name": "LogFlushRateAndTimeMs",
-				},
-			},
-		)
-	case "kafka.consumer.bytes-consumed-rate":
-		copyMetricWithAttr(all, m, "kafka.consumer.bytes_consumed", 1,
-			attributesMapping{
-				fixed:   map[string]string{"type": "consumer-fetch-manager-metrics"},
-				dynamic: map[string]string{"client-id": "client"},
-			},
-		)
-	case "kafka.consumer.records-consumed-rate":
-		copyMetricWithAttr(all, m, "kafka.consumer.records_consumed", 1,
-			attributesMapping{
-				fixed: map[string]string{
-					"type": "consumer-fetch-manager-metrics",
-				},
-				dynamic: map[string]string{"client-id": "client"},
-			},
-		)
-	case "kafka.consumer.fetch-size-avg":
-		copyMetricWithAttr(all, m, "kafka.consumer.fetch_size_avg", 1,
-			attributesMapping{
-				fixed:   map[string]string{"type": "consumer-fetch-manager-metrics"},
-				dynamic: map[string]string{"client-id": "client"},
-			},
-		)
-	case "kafka.producer.compression-rate":
-		copyMetricWithAttr(all, m, "kafka.producer.compression_rate", 1,
-			attributesMapping{
-				fixed:   map[string]string{"type": "producer-topic-metrics"},
-				dynamic: map[string]string{"client-id": "client"},
-			},
-		)
-	case "kafka.producer.record-error-rate":
-		copyMetricWithAttr(all, m, "kafka.producer.record_error_rate", 1,
-			attributesMapping{
-				fixed:   map[string]string{"type": "producer-topic-metrics"},
-				dynamic: map[string]string{"client-id": "client"},
-			},
-		)
-	case "kafka.producer.record-retry-rate":
-		copyMetricWithAttr(all, m, "kafka.producer.record_retry_rate", 1,
-			attributesMapping{
-				fixed:   map[string]string{"type": "producer-topic-metrics"},
-				dynamic: map[string]string{"client-id": "client"},
-			},
-		)
-	case "kafka.producer.record-send-rate":
-		copyMetricWithAttr(all, m, "kafka.producer.record_send_rate", 1,
-			attributesMapping{
-				fixed:   map[string]string{"type": "producer-topic-metrics"},
-				dynamic: map[string]string{"client-id": "client"},
-			},
-		)
+	{
+		Match: "kafka.message.count",
+		Name:  "kafka.messages_in.rate",
+		FixedAttributes: map[string]string{
+			"type": "BrokerTopicMetrics",
+			"name": "MessagesInPerSec",
+		},
+	},
+	{
+		Match: "kafka.request.failed",
+		Name:  "kafka.request.produce.failed.rate",
+		Filters: []AttributeFilter{
+			{Key: "type", Value: "produce"},
+		},
+		FixedAttributes: map[string]string{
+			"type": "BrokerTopicMetrics",
+			"name": "FailedProduceRequestsPerSec",
+		},
+	},
+	{
+		Match: "kafka.request.failed",
+		Name:  "kafka.request.fetch.failed.rate",
+		Filters: []AttributeFilter{
+			{Key: "type", Value: "fetch"},
+		},
+		FixedAttributes: map[string]string{
+			"type": "BrokerTopicMetrics",
+			"name": "FailedFetchRequestsPerSec",
+		},
+	},
+	{
+		Match: "kafka.request.time.99p",
+		Name:  "kafka.request.produce.time.99percentile",
+		Filters: []AttributeFilter{
+			{Key: "type", Value: "produce"},
+		},
+		FixedAttributes: map[string]string{
+			"type":    "RequestMetrics",
+			"name":    "TotalTimeMs",
+			"request": "Produce",
+		},
+	},
+	{
+		Match: "kafka.request.time.99p",
+		Name:  "kafka.request.fetch_consumer.time.99percentile",
+		Filters: []AttributeFilter{
+			{Key: "type", Value: "fetchconsumer"},
+		},
+		FixedAttributes: map[string]string{
+			"type":    "RequestMetrics",
+			"name":    "TotalTimeMs",
+			"request": "FetchConsumer",
+		},
+	},
+	{
+		Match: "kafka.request.time.99p",
+		Name:  "kafka.request.fetch_follower.time.99percentile",
+		Filters: []AttributeFilter{
+			{Key: "type", Value: "fetchfollower"},
+		},
+		FixedAttributes: map[string]string{
+			"type":    "RequestMetrics",
+			"name":    "TotalTimeMs",
+			"request": "FetchFollower",
+		},
+	},
+	{
+		Match: "kafka.partition.count",
+		Name:  "kafka.replication.partition_count",
+		FixedAttributes: map[string]string{
+			"type": "ReplicaManager",
+			"name": "PartitionCount",
+		},
+	},
+	{
+		Match: "kafka.max.lag",
+		Name:  "kafka.replication.max_lag",
+		FixedAttributes: map[string]string{
+			"type":     "ReplicaFetcherManager",
+			"name":     "MaxLag",
+			"clientId": "replica",
+		},
+	},
+	{
+		Match: "kafka.controller.active.count",
+		Name:  "kafka.replication.active_controller_count",
+		FixedAttributes: map[string]string{
+			"type": "KafkaController",
+			"name": "ActiveControllerCount",
+		},
+	},
+	{
+		Match: "kafka.unclean.election.rate",
+		Name:  "kafka.replication.unclean_leader_elections.rate",
+		FixedAttributes: map[string]string{
+			"type": "ControllerStats",
+			"name": "UncleanLeaderElectionsPerSec",
+		},
+	},
+	{
+		Match: "kafka.request.queue",
+		Name:  "kafka.request.channel.queue.size",
+		FixedAttributes: map[string]string{
+			"type": "RequestChannel",
+			"name": "RequestQueueSize",
+		},
+	},
+	{
+		Match: "kafka.logs.flush.time.count",
+		Name:  "kafka.log.flush_rate.rate",
+		FixedAttributes: map[string]string{
+			"type": "LogFlushStats",
+			"name": "LogFlushRateAndTimeMs",
+		},
+	},
+	{
+		Match: "kafka.consumer.bytes-consumed-rate",
+		Name:  "kafka.consumer.bytes_consumed",
+		FixedAttributes: map[string]string{
+			"type": "consumer-fetch-manager-metrics",
+		},
+		AttributesMapping: map[string]string{
+			"client-id": "client",
+		},
+	},
+	{
+		Match: "kafka.consumer.records-consumed-rate",
+		Name:  "kafka.consumer.records_consumed",
+		FixedAttributes: map[string]string{
+			"type": "consumer-fetch-manager-metrics",
+		},
+		AttributesMapping: map[string]string{
+			"client-id": "client",
+		},
+	},
+	{
+		Match: "kafka.consumer.fetch-size-avg",
+		Name:  "kafka.consumer.fetch_size_avg",
+		FixedAttributes: map[string]string{
+			"type": "consumer-fetch-manager-metrics",
+		},
+		AttributesMapping: map[string]string{
+			"client-id": "client",
+		},
+	},
+	{
+		Match: "kafka.producer.compression-rate",
+		Name:  "kafka.producer.compression_rate",
+		FixedAttributes: map[string]string{
+			"type": "producer-topic-metrics",
+		},
+		AttributesMapping: map[string]string{
+			"client-id": "client",
+		},
+	},
+	{
+		Match: "kafka.producer.record-error-rate",
+		Name:  "kafka.producer.record_error_rate",
+		FixedAttributes: map[string]string{
+			"type": "producer-topic-metrics",
+		},
+		AttributesMapping: map[string]string{
+			"client-id": "client",
+		},
+	},
+	{
+		Match: "kafka.producer.record-retry-rate",
+		Name:  "kafka.producer.record_retry_rate",
+		FixedAttributes: map[string]string{
+			"type": "producer-topic-metrics",
+		},
+		AttributesMapping: map[string]string{
+			"client-id": "client",
+		},
+	},
+	{
+		Match: "kafka.producer.record-send-rate",
+		Name:  "kafka.producer.record_send_rate",
+		FixedAttributes: map[string]string{
+			"type": "producer-topic-metrics",
+		},
+		AttributesMapping: map[string]string{
+			"client-id": "client",
+		},
+	},
 	// kafka metrics receiver
-	case "kafka.partition.current_offset":
-		copyMetricWithAttr(all, m, "kafka.broker_offset", 1,
-			attributesMapping{
-				dynamic: map[string]string{"group": "consumer_group"},
-			},
-		)
-	case "kafka.consumer_group.lag":
-		copyMetricWithAttr(all, m, "kafka.consumer_lag", 1,
-			attributesMapping{
-				dynamic: map[string]string{"group": "consumer_group"},
-			},
-		)
-	case "kafka.consumer_group.offset":
-		copyMetricWithAttr(all, m, "kafka.consumer_offset", 1,
-			attributesMapping{
-				dynamic: map[string]string{"group": "consumer_group"},
-			},
-		)
-	}
+	{
+		Match: "kafka.partition.current_offset",
+		Name:  "kafka.broker_offset",
+		AttributesMapping: map[string]string{
+			"group": "consumer_group",
+		},
+	},
+	{
+		Match: "kafka.consumer_group.lag",
+		Name:  "kafka.consumer_lag",
+		AttributesMapping: map[string]string{
+			"group": "consumer_group",
+		},
+	},
+	{
+		Match: "kafka.consumer_group.offset",
+		Name:  "kafka.consumer_offset",
+		AttributesMapping: map[string]string{
+			"group": "consumer_group",
+		},
+	},
 }
 
-func remapJvmMetrics(all pmetric.MetricSlice, m pmetric.Metric) {
-	name := m.Name()
-	if !strings.HasPrefix(name, "jvm.") {
-		// not a jvm metric
-		return
-	}
-	switch name {
-	case "jvm.gc.collections.count":
-		// Young Gen Collectors
-		copyMetricWithAttr(all, m, "jvm.gc.minor_collection_count", 1,
-			attributesMapping{
-				fixed: map[string]string{"type": "GarbageCollector"},
-			},
-			kv{"name", "Copy"},
-			kv{"name", "PS Scavenge"},
-			kv{"name", "ParNew"},
-			kv{"name", "G1 Young Generation"},
-		)
-		// Old Gen Collectors
-		copyMetricWithAttr(all, m, "jvm.gc.major_collection_count", 1,
-			attributesMapping{
-				fixed: map[string]string{"type": "GarbageCollector"},
-			},
-			kv{"name", "MarkSweepCompact"},
-			kv{"name", "PS MarkSweep"},
-			kv{"name", "ConcurrentMarkSweep"},
-			kv{"name", "G1 Mixed Generation"},
-			kv{"name", "G1 Old Generation"},
-			kv{"name", "Shenandoah Cycles"},
-			kv{"name", "ZGC"},
-		)
-
-	case "jvm.gc.collections.elapsed":
-		// Young Gen Collectors
-		copyMetricWithAttr(all, m, "jvm.gc.minor_collection_time", 1,
-			attributesMapping{
-				fixed: map[string]string{"type": "GarbageCollector"},
-			},
-			kv{"name", "Copy"},
-			kv{"name", "PS Scavenge"},
-			kv{"name", "ParNew"},
-			kv{"name", "G1 Young Generation"},
-		)
-		// Old Gen Collectors
-		copyMetricWithAttr(all, m, "jvm.gc.major_collection_time", 1,
-			attributesMapping{
-				fixed: map[string]string{"type": "GarbageCollector"},
-			},
-			kv{"name", "MarkSweepCompact"},
-			kv{"name", "PS MarkSweep"},
-			kv{"name", "ConcurrentMarkSweep"},
-			kv{"name", "G1 Mixed Generation"},
-			kv{"name", "G1 Old Generation"},
-			kv{"name", "Shenandoah Cycles"},
-			kv{"name", "ZGC"},
-		)
-	}
+// jvmRemappingRules contains the rules for computing Datadog JVM metrics from their
+// OpenTelemetry counterparts.
+var jvmRemappingRules = []RemappingRule{
+	// Young Gen Collectors
+	{
+		Match: "jvm.gc.collections.count",
+		Name:  "jvm.gc.minor_collection_count",
+		Filters: []AttributeFilter{
+			{Key: "name", Value: "Copy"},
+			{Key: "name", Value: "PS Scavenge"},
+			{Key: "name", Value: "ParNew"},
+			{Key: "name", Value: "G1 Young Generation"},
+		},
+		FixedAttributes: map[string]string{
+			"type": "GarbageCollector",
+		},
+	},
+	// Old Gen Collectors
+	{
+		Match: "jvm.gc.collections.count",
+		Name:  "jvm.gc.major_collection_count",
+		Filters: []AttributeFilter{
+			{Key: "name", Value: "MarkSweepCompact"},
+			{Key: "name", Value: "PS MarkSweep"},
+			{Key: "name", Value: "ConcurrentMarkSweep"},
+			{Key: "name", Value: "G1 Mixed Generation"},
+			{Key: "name", Value: "G1 Old Generation"},
+			{Key: "name", Value: "Shenandoah Cycles"},
+			{Key: "name", Value: "ZGC"},
+		},
+		FixedAttributes: map[string]string{
+			"type": "GarbageCollector",
+		},
+	},
+	// Young Gen Collectors
+	{
+		Match: "jvm.gc.collections.elapsed",
+		Name:  "jvm.gc.minor_collection_time",
+		Filters: []AttributeFilter{
+			{Key: "name", Value: "Copy"},
+			{Key: "name", Value: "PS Scavenge"},
+			{Key: "name", Value: "ParNew"},
+			{Key: "name", Value: "G1 Young Generation"},
+		},
+		FixedAttributes: map[string]string{
+			"type": "GarbageCollector",
+		},
+	},
+	// Old Gen Collectors
+	{
+		Match: "jvm.gc.collections.elapsed",
+		Name:  "jvm.gc.major_collection_time",
+		Filters: []AttributeFilter{
+			{Key: "name", Value: "MarkSweepCompact"},
+			{Key: "name", Value: "PS MarkSweep"},
+			{Key: "name", Value: "ConcurrentMarkSweep"},
+			{Key: "name", Value: "G1 Mixed Generation"},
+			{Key: "name", Value: "G1 Old Generation"},
+			{Key: "name", Value: "Shenandoah Cycles"},
+			{Key: "name", Value: "ZGC"},
+		},
+		FixedAttributes: map[string]string{
+			"type": "GarbageCollector",
+		},
+	},
 }
 
 // renameKafkaMetrics renames otel kafka metrics to avoid conflicts with DD metrics.
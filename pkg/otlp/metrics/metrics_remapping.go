@@ -15,8 +15,10 @@
 package metrics
 
 import (
+	"slices"
 	"strings"
 
+	"go.opentelemetry.io/collector/pdata/pcommon"
 	"go.opentelemetry.io/collector/pdata/pmetric"
 )
 
@@ -27,16 +29,6 @@ const (
 	divPercentage = 0.01
 )
 
-var emptyAttributesMapping = attributesMapping{}
-
-// remapMetrics extracts any Datadog specific metrics from m and appends them to all.
-func remapMetrics(all pmetric.MetricSlice, m pmetric.Metric) {
-	remapSystemMetrics(all, m)
-	remapContainerMetrics(all, m)
-	remapKafkaMetrics(all, m)
-	remapJvmMetrics(all, m)
-}
-
 // renameMetrics adds the `otel.` or `otelcol_` prefix to metrics.
 func renameMetrics(m pmetric.Metric) {
 	renameHostMetrics(m)
@@ -44,94 +36,206 @@ func renameMetrics(m pmetric.Metric) {
 	renameAgentInternalOTelMetric(m)
 }
 
-// remapSystemMetrics extracts system metrics from m and appends them to all.
-func remapSystemMetrics(all pmetric.MetricSlice, m pmetric.Metric) {
-	name := m.Name()
-	if !isHostMetric(name) {
-		return
-	}
-	switch name {
-	case "system.cpu.load_average.1m":
-		copyMetricWithAttr(all, m, "system.load.1", 1, emptyAttributesMapping)
-	case "system.cpu.load_average.5m":
-		copyMetricWithAttr(all, m, "system.load.5", 1, emptyAttributesMapping)
-	case "system.cpu.load_average.15m":
-		copyMetricWithAttr(all, m, "system.load.15", 1, emptyAttributesMapping)
-	case "system.cpu.utilization":
-		copyMetricWithAttr(all, m, "system.cpu.idle", divPercentage, emptyAttributesMapping, kv{"state", "idle"})
-		copyMetricWithAttr(all, m, "system.cpu.user", divPercentage, emptyAttributesMapping, kv{"state", "user"})
-		copyMetricWithAttr(all, m, "system.cpu.system", divPercentage, emptyAttributesMapping, kv{"state", "system"})
-		copyMetricWithAttr(all, m, "system.cpu.iowait", divPercentage, emptyAttributesMapping, kv{"state", "wait"})
-		copyMetricWithAttr(all, m, "system.cpu.stolen", divPercentage, emptyAttributesMapping, kv{"state", "steal"})
-	case "system.memory.usage":
-		copyMetricWithAttr(all, m, "system.mem.total", divMebibytes, emptyAttributesMapping)
-		copyMetricWithAttr(all, m, "system.mem.usable", divMebibytes, emptyAttributesMapping,
-			kv{"state", "free"},
-			kv{"state", "cached"},
-			kv{"state", "buffered"},
-		)
-	case "system.network.io":
-		copyMetricWithAttr(all, m, "system.net.bytes_rcvd", 1, emptyAttributesMapping, kv{"direction", "receive"})
-		copyMetricWithAttr(all, m, "system.net.bytes_sent", 1, emptyAttributesMapping, kv{"direction", "transmit"})
-	case "system.paging.usage":
-		copyMetricWithAttr(all, m, "system.swap.free", divMebibytes, emptyAttributesMapping, kv{"state", "free"})
-		copyMetricWithAttr(all, m, "system.swap.used", divMebibytes, emptyAttributesMapping, kv{"state", "used"})
-	case "system.filesystem.utilization":
-		copyMetricWithAttr(all, m, "system.disk.in_use", 1, emptyAttributesMapping)
-	}
+// systemRemappingRules contains the rules for computing Datadog system metrics from their
+// OpenTelemetry counterparts.
+var systemRemappingRules = []RemappingRule{
+	{
+		Match: "system.cpu.load_average.1m",
+		Name:  "system.load.1",
+	},
+	{
+		Match: "system.cpu.load_average.5m",
+		Name:  "system.load.5",
+	},
+	{
+		Match: "system.cpu.load_average.15m",
+		Name:  "system.load.15",
+	},
+	{
+		Match:   "system.cpu.utilization",
+		Name:    "system.cpu.idle",
+		Divisor: divPercentage,
+		Filters: []AttributeFilter{
+			{Key: "state", Value: "idle"},
+		},
+	},
+	{
+		Match:   "system.cpu.utilization",
+		Name:    "system.cpu.user",
+		Divisor: divPercentage,
+		Filters: []AttributeFilter{
+			{Key: "state", Value: "user"},
+		},
+	},
+	{
+		Match:   "system.cpu.utilization",
+		Name:    "system.cpu.system",
+		Divisor: divPercentage,
+		Filters: []AttributeFilter{
+			{Key: "state", Value: "system"},
+		},
+	},
+	{
+		Match:   "system.cpu.utilization",
+		Name:    "system.cpu.iowait",
+		Divisor: divPercentage,
+		Filters: []AttributeFilter{
+			{Key: "state", Value: "wait"},
+		},
+	},
+	{
+		Match:   "system.cpu.utilization",
+		Name:    "system.cpu.stolen",
+		Divisor: divPercentage,
+		Filters: []AttributeFilter{
+			{Key: "state", Value: "steal"},
+		},
+	},
+	{
+		Match:   "system.memory.usage",
+		Name:    "system.mem.total",
+		Divisor: divMebibytes,
+	},
+	{
+		Match:   "system.memory.usage",
+		Name:    "system.mem.usable",
+		Divisor: divMebibytes,
+		Filters: []AttributeFilter{
+			{Key: "state", Value: "free"},
+			{Key: "state", Value: "cached"},
+			{Key: "state", Value: "buffered"},
+		},
+	},
+	{
+		Match: "system.network.io",
+		Name:  "system.net.bytes_rcvd",
+		Filters: []AttributeFilter{
+			{Key: "direction", Value: "receive"},
+		},
+	},
+	{
+		Match: "system.network.io",
+		Name:  "system.net.bytes_sent",
+		Filters: []AttributeFilter{
+			{Key: "direction", Value: "transmit"},
+		},
+	},
+	{
+		Match:   "system.paging.usage",
+		Name:    "system.swap.free",
+		Divisor: divMebibytes,
+		Filters: []AttributeFilter{
+			{Key: "state", Value: "free"},
+		},
+	},
+	{
+		Match:   "system.paging.usage",
+		Name:    "system.swap.used",
+		Divisor: divMebibytes,
+		Filters: []AttributeFilter{
+			{Key: "state", Value: "used"},
+		},
+	},
+	{
+		Match: "system.filesystem.utilization",
+		Name:  "system.disk.in_use",
+	},
 }
 
-// remapContainerMetrics extracts system metrics from m and appends them to all.
-func remapContainerMetrics(all pmetric.MetricSlice, m pmetric.Metric) {
-	name := m.Name()
-	if !strings.HasPrefix(name, "container.") {
-		// not a container metric
-		return
-	}
-	switch name {
-	case "container.cpu.usage.total":
-		if addm, ok := copyMetricWithAttr(all, m, "container.cpu.usage", 1, emptyAttributesMapping); ok {
-			addm.SetUnit("nanocore")
-		}
-	case "container.cpu.usage.usermode":
-		if addm, ok := copyMetricWithAttr(all, m, "container.cpu.user", 1, emptyAttributesMapping); ok {
-			addm.SetUnit("nanocore")
-		}
-	case "container.cpu.usage.system":
-		if addm, ok := copyMetricWithAttr(all, m, "container.cpu.system", 1, emptyAttributesMapping); ok {
-			addm.SetUnit("nanocore")
-		}
-	case "container.cpu.throttling_data.throttled_time":
-		copyMetricWithAttr(all, m, "container.cpu.throttled", 1, emptyAttributesMapping)
-	case "container.cpu.throttling_data.throttled_periods":
-		copyMetricWithAttr(all, m, "container.cpu.throttled.periods", 1, emptyAttributesMapping)
-	case "container.memory.usage.total":
-		copyMetricWithAttr(all, m, "container.memory.usage", 1, emptyAttributesMapping)
-	case "container.memory.active_anon":
-		copyMetricWithAttr(all, m, "container.memory.kernel", 1, emptyAttributesMapping)
-	case "container.memory.hierarchical_memory_limit":
-		copyMetricWithAttr(all, m, "container.memory.limit", 1, emptyAttributesMapping)
-	case "container.memory.usage.limit":
-		copyMetricWithAttr(all, m, "container.memory.soft_limit", 1, emptyAttributesMapping)
-	case "container.memory.total_cache":
-		copyMetricWithAttr(all, m, "container.memory.cache", 1, emptyAttributesMapping)
-	case "container.memory.total_swap":
-		copyMetricWithAttr(all, m, "container.memory.swap", 1, emptyAttributesMapping)
-	case "container.blockio.io_service_bytes_recursive":
-		copyMetricWithAttr(all, m, "container.io.write", 1, emptyAttributesMapping, kv{"operation", "write"})
-		copyMetricWithAttr(all, m, "container.io.read", 1, emptyAttributesMapping, kv{"operation", "read"})
-	case "container.blockio.io_serviced_recursive":
-		copyMetricWithAttr(all, m, "container.io.write.operations", 1, emptyAttributesMapping, kv{"operation", "write"})
-		copyMetricWithAttr(all, m, "container.io.read.operations", 1, emptyAttributesMapping, kv{"operation", "read"})
-	case "container.network.io.usage.tx_bytes":
-		copyMetricWithAttr(all, m, "container.net.sent", 1, emptyAttributesMapping)
-	case "container.network.io.usage.tx_packets":
-		copyMetricWithAttr(all, m, "container.net.sent.packets", 1, emptyAttributesMapping)
-	case "container.network.io.usage.rx_bytes":
-		copyMetricWithAttr(all, m, "container.net.rcvd", 1, emptyAttributesMapping)
-	case "container.network.io.usage.rx_packets":
-		copyMetricWithAttr(all, m, "container.net.rcvd.packets", 1, emptyAttributesMapping)
-	}
+// containerRemappingRules contains the rules for computing Datadog container metrics from their
+// OpenTelemetry counterparts.
+var containerRemappingRules = []RemappingRule{
+	{
+		Match: "container.cpu.usage.total",
+		Name:  "container.cpu.usage",
+		Unit:  "nanocore",
+	},
+	{
+		Match: "container.cpu.usage.usermode",
+		Name:  "container.cpu.user",
+		Unit:  "nanocore",
+	},
+	{
+		Match: "container.cpu.usage.system",
+		Name:  "container.cpu.system",
+		Unit:  "nanocore",
+	},
+	{
+		Match: "container.cpu.throttling_data.throttled_time",
+		Name:  "container.cpu.throttled",
+	},
+	{
+		Match: "container.cpu.throttling_data.throttled_periods",
+		Name:  "container.cpu.throttled.periods",
+	},
+	{
+		Match: "container.memory.usage.total",
+		Name:  "container.memory.usage",
+	},
+	{
+		Match: "container.memory.active_anon",
+		Name:  "container.memory.kernel",
+	},
+	{
+		Match: "container.memory.hierarchical_memory_limit",
+		Name:  "container.memory.limit",
+	},
+	{
+		Match: "container.memory.usage.limit",
+		Name:  "container.memory.soft_limit",
+	},
+	{
+		Match: "container.memory.total_cache",
+		Name:  "container.memory.cache",
+	},
+	{
+		Match: "container.memory.total_swap",
+		Name:  "container.memory.swap",
+	},
+	{
+		Match: "container.blockio.io_service_bytes_recursive",
+		Name:  "container.io.write",
+		Filters: []AttributeFilter{
+			{Key: "operation", Value: "write"},
+		},
+	},
+	{
+		Match: "container.blockio.io_service_bytes_recursive",
+		Name:  "container.io.read",
+		Filters: []AttributeFilter{
+			{Key: "operation", Value: "read"},
+		},
+	},
+	{
+		Match: "container.blockio.io_serviced_recursive",
+		Name:  "container.io.write.operations",
+		Filters: []AttributeFilter{
+			{Key: "operation", Value: "write"},
+		},
+	},
+	{
+		Match: "container.blockio.io_serviced_recursive",
+		Name:  "container.io.read.operations",
+		Filters: []AttributeFilter{
+			{Key: "operation", Value: "read"},
+		},
+	},
+	{
+		Match: "container.network.io.usage.tx_bytes",
+		Name:  "container.net.sent",
+	},
+	{
+		Match: "container.network.io.usage.tx_packets",
+		Name:  "container.net.sent.packets",
+	},
+	{
+		Match: "container.network.io.usage.rx_bytes",
+		Name:  "container.net.rcvd",
+	},
+	{
+		Match: "container.network.io.usage.rx_packets",
+		Name:  "container.net.rcvd.packets",
+	},
 }
 
 // isHostMetric determines whether a metric is a system metric.
@@ -152,74 +256,112 @@ type (
 		// to be dynamically pulled from a data point attribute. Typically when the OTel
 		// metric and DD metric have different conventions (e.g. group vs consumer_group).
 		dynamic map[string]string
+		// dropped represents attributes that need to be removed, once the other attributes are mapped.
+		dropped []string
 	}
 )
 
-// copyMetric copies metric m to dest. The new metric's name will be newname, and all of its datapoints will
-// be divided by div. If filter is provided, only the data points that have *either* of the specified string
-// attributes will be copied over. If the filtering results in no datapoints, no new metric is added to dest.
-// It will add any attributes specified in attributesMapping, by either pulling the value from the datapoint
-// for dynamic attributes, or setting the given attribute for fixed attributes.
+// copyMetricWithAttr copies metric m to dest according to rule. The new metric's name will be the rule's name,
+// and all of its number data points will be divided by the rule's divisor. If the rule has filters, only the
+// data points that have *either* of the specified string attributes, and all of its required attributes, will
+// be copied over. If the filtering results in no datapoints, no new metric is added to dest.
+// It will add any attributes specified in the rule's attributesMapping, by either pulling the value from the
+// datapoint for dynamic attributes, or setting the given attribute for fixed attributes, and remove its
+// dropped attributes.
 //
-// copyMetric returns the new metric and reports whether it was added to dest.
+// copyMetricWithAttr returns the new metric and reports whether it was added to dest.
 //
-// Please note that copyMetric is restricted to the metric types Sum and Gauge.
-func copyMetricWithAttr(dest pmetric.MetricSlice, m pmetric.Metric, newname string, div float64, attributesMapping attributesMapping, filter ...kv) (pmetric.Metric, bool) {
+// Please note that copyMetricWithAttr is restricted to the metric types Sum, Gauge, Histogram and
+// ExponentialHistogram.
+func copyMetricWithAttr(dest pmetric.MetricSlice, m pmetric.Metric, rule remappingRule) (pmetric.Metric, bool) {
 	newm := pmetric.NewMetric()
 	m.CopyTo(newm)
-	newm.SetName(newname)
-	var dps pmetric.NumberDataPointSlice
+	newm.SetName(rule.name)
+	var n int
 	switch newm.Type() {
 	case pmetric.MetricTypeGauge:
-		dps = newm.Gauge().DataPoints()
+		n = rule.remapNumberDataPoints(newm.Gauge().DataPoints())
 	case pmetric.MetricTypeSum:
-		dps = newm.Sum().DataPoints()
+		n = rule.remapNumberDataPoints(newm.Sum().DataPoints())
+	case pmetric.MetricTypeHistogram:
+		dps := newm.Histogram().DataPoints()
+		var selected bool
+		dps.RemoveIf(func(dp pmetric.HistogramDataPoint) bool {
+			if selected && rule.firstHistogramPoint {
+				return true
+			}
+			selected = rule.remapAttributes(dp.Attributes())
+			return !selected
+		})
+		n = dps.Len()
+	case pmetric.MetricTypeExponentialHistogram:
+		dps := newm.ExponentialHistogram().DataPoints()
+		dps.RemoveIf(func(dp pmetric.ExponentialHistogramDataPoint) bool {
+			return !rule.remapAttributes(dp.Attributes())
+		})
+		n = dps.Len()
 	default:
 		// invalid metric type
 		return newm, false
 	}
+	if n > 0 {
+		// if we have datapoints, copy it
+		addm := dest.AppendEmpty()
+		newm.CopyTo(addm)
+		return addm, true
+	}
+	return newm, false
+}
+
+// remapNumberDataPoints removes the data points not selected by the rule, maps the attributes and
+// divides the values of the others, and returns the number of data points left.
+func (r remappingRule) remapNumberDataPoints(dps pmetric.NumberDataPointSlice) int {
 	dps.RemoveIf(func(dp pmetric.NumberDataPoint) bool {
-		if !hasAny(dp, filter...) {
+		if !r.remapAttributes(dp.Attributes()) {
 			return true
 		}
 		switch dp.ValueType() {
 		case pmetric.NumberDataPointValueTypeInt:
-			if div >= 1 {
+			if r.div >= 1 {
 				// avoid division by zero
-				dp.SetIntValue(dp.IntValue() / int64(div))
+				dp.SetIntValue(dp.IntValue() / int64(r.div))
 			}
 		case pmetric.NumberDataPointValueTypeDouble:
-			if div != 0 {
-				dp.SetDoubleValue(dp.DoubleValue() / div)
-			}
-		}
-		// attributes mapping
-		for k, v := range attributesMapping.fixed {
-			dp.Attributes().PutStr(k, v)
-		}
-		for old, new := range attributesMapping.dynamic {
-			if v, ok := dp.Attributes().Get(old); ok {
-				v.CopyTo(dp.Attributes().PutEmpty(new))
+			if r.div != 0 {
+				dp.SetDoubleValue(dp.DoubleValue() / r.div)
 			}
 		}
 		return false
 	})
-	if dps.Len() > 0 {
-		// if we have datapoints, copy it
-		addm := dest.AppendEmpty()
-		newm.CopyTo(addm)
-		return addm, true
+	return dps.Len()
+}
+
+// remapAttributes reports whether the rule selects a data point with the given attributes and, if so,
+// maps its attributes.
+func (r remappingRule) remapAttributes(attrs pcommon.Map) bool {
+	if !hasAny(attrs, r.filter...) || !hasAll(attrs, r.required...) {
+		return false
 	}
-	return newm, false
+	for k, v := range r.mapping.fixed {
+		attrs.PutStr(k, v)
+	}
+	for old, new := range r.mapping.dynamic {
+		if v, ok := attrs.Get(old); ok {
+			v.CopyTo(attrs.PutEmpty(new))
+		}
+	}
+	for _, k := range r.mapping.dropped {
+		attrs.Remove(k)
+	}
+	return true
 }
 
-// hasAny reports whether point has any of the given string tags.
+// hasAny reports whether attr has any of the given string tags.
 // If no tags are provided it returns true.
-func hasAny(point pmetric.NumberDataPoint, tags ...kv) bool {
+func hasAny(attr pcommon.Map, tags ...kv) bool {
 	if len(tags) == 0 {
 		return true
 	}
-	attr := point.Attributes()
 	for _, tag := range tags {
 		v, ok := attr.Get(tag.K)
 		if !ok {
@@ -232,6 +374,17 @@ func hasAny(point pmetric.NumberDataPoint, tags ...kv) bool {
 	return false
 }
 
+// hasAll reports whether attr has all of the given attributes, each with any of its values.
+func hasAll(attr pcommon.Map, required ...AttributeValues) bool {
+	for _, r := range required {
+		v, ok := attr.Get(r.Key)
+		if !ok || !slices.Contains(r.Values, v.AsString()) {
+			return false
+		}
+	}
+	return true
+}
+
 // renameHostMetrics renames otel host metrics to avoid conflicts with Datadog metrics.
 func renameHostMetrics(m pmetric.Metric) {
 	if isHostMetric(m.Name()) {
@@ -22,6 +22,7 @@ import (
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/pdatatest/pmetrictest"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
 	"go.opentelemetry.io/collector/pdata/pmetric"
 )
 
@@ -59,6 +60,8 @@ func TestRemapAndRenameMetrics(t *testing.T) {
 		return m
 	}
 
+	// The default rules, including the ones only enabled by WithRemapping, set as custom rules.
+	tr := NewTestTranslator(t, WithRemappingRules(DefaultRemappingRules()...))
 	dest := pmetric.NewMetricSlice()
 	for _, tt := range []struct {
 		in  pmetric.Metric
@@ -786,10 +789,10 @@ func TestRemapAndRenameMetrics(t *testing.T) {
 			tt.in.Name() == "kafka.producer.record-error-rate" ||
 			tt.in.Name() == "kafka.producer.record-retry-rate" ||
 			tt.in.Name() == "kafka.producer.record-send-rate"
-		remapMetrics(dest, tt.in)
-		// Ensure remapMetrics does not add the otel.* prefix to the metric name
+		tr.remapper.remap(dest, tt.in)
+		// Ensure remapping does not add the otel.* prefix to the metric name
 		if checkprefix {
-			require.False(t, strings.HasPrefix(tt.in.Name(), "otel."), "remapMetrics should not add the otel.* prefix to the metric name, it should only compute Datadog metrics")
+			require.False(t, strings.HasPrefix(tt.in.Name(), "otel."), "remapping should not add the otel.* prefix to the metric name, it should only compute Datadog metrics")
 		}
 		renameMetrics(tt.in)
 		if checkprefix {
@@ -860,7 +863,7 @@ func TestCopyMetricWithAttr(t *testing.T) {
 		dp.Attributes().FromRaw(map[string]any{"human": "Ann", "age": 25})
 
 		t.Run("plain", func(t *testing.T) {
-			out, ok := copyMetricWithAttr(dest, m, "copied.test.metric", 1, attributesMapping{})
+			out, ok := copyMetricWithAttr(dest, m, remappingRule{name: "copied.test.metric", div: 1})
 			require.True(t, ok)
 			require.Equal(t, m.Name(), "test.metric")
 			require.Equal(t, out.Name(), "copied.test.metric")
@@ -869,7 +872,7 @@ func TestCopyMetricWithAttr(t *testing.T) {
 		})
 
 		t.Run("div", func(t *testing.T) {
-			out, ok := copyMetricWithAttr(dest, m, "copied.test.metric", 2, attributesMapping{})
+			out, ok := copyMetricWithAttr(dest, m, remappingRule{name: "copied.test.metric", div: 2})
 			require.True(t, ok)
 			require.Equal(t, out.Name(), "copied.test.metric")
 			require.Equal(t, out.Gauge().DataPoints().At(0).DoubleValue(), 6.)
@@ -878,7 +881,7 @@ func TestCopyMetricWithAttr(t *testing.T) {
 		})
 
 		t.Run("filter", func(t *testing.T) {
-			out, ok := copyMetricWithAttr(dest, m, "copied.test.metric", 1, attributesMapping{}, kv{"human", "Ann"})
+			out, ok := copyMetricWithAttr(dest, m, remappingRule{name: "copied.test.metric", div: 1, filter: []kv{{"human", "Ann"}}})
 			require.True(t, ok)
 			require.Equal(t, out.Name(), "copied.test.metric")
 			require.Equal(t, out.Gauge().DataPoints().Len(), 1)
@@ -887,10 +890,10 @@ func TestCopyMetricWithAttr(t *testing.T) {
 			require.Equal(t, dest.At(dest.Len()-1), out)
 		})
 		t.Run("attributesMapping", func(t *testing.T) {
-			out, ok := copyMetricWithAttr(dest, m, "copied.test.metric", 1, attributesMapping{
+			out, ok := copyMetricWithAttr(dest, m, remappingRule{name: "copied.test.metric", div: 1, mapping: attributesMapping{
 				fixed:   map[string]string{"fixed.attr": "ok"},
 				dynamic: map[string]string{"fruit": "remapped_fruit"},
-			})
+			}})
 			require.True(t, ok)
 			require.Equal(t, m.Name(), "test.metric")
 			require.Equal(t, out.Name(), "copied.test.metric")
@@ -911,9 +914,9 @@ func TestCopyMetricWithAttr(t *testing.T) {
 			require.Equal(t, dest.At(dest.Len()-1), out)
 		})
 		t.Run("dynamicattrmissing", func(t *testing.T) {
-			out, ok := copyMetricWithAttr(dest, m, "copied.test.metric", 1, attributesMapping{
+			out, ok := copyMetricWithAttr(dest, m, remappingRule{name: "copied.test.metric", div: 1, mapping: attributesMapping{
 				dynamic: map[string]string{"nonexistingattr": "remapped_nonexistingattr"},
-			})
+			}})
 			require.True(t, ok)
 			require.Equal(t, m.Name(), "test.metric")
 			require.Equal(t, out.Name(), "copied.test.metric")
@@ -921,8 +924,33 @@ func TestCopyMetricWithAttr(t *testing.T) {
 			sameExceptName(t, m, out)
 			require.Equal(t, dest.At(dest.Len()-1), out)
 		})
+		t.Run("required", func(t *testing.T) {
+			out, ok := copyMetricWithAttr(dest, m, remappingRule{name: "copied.test.metric", div: 1, required: []AttributeValues{
+				{Key: "human", Values: []string{"Paul", "Ann"}},
+				{Key: "age", Values: []string{"25"}},
+			}})
+			require.True(t, ok)
+			require.Equal(t, out.Gauge().DataPoints().Len(), 1)
+			require.Equal(t, out.Gauge().DataPoints().At(0).IntValue(), int64(24))
+
+			_, ok = copyMetricWithAttr(dest, m, remappingRule{name: "copied.test.metric", div: 1, required: []AttributeValues{
+				{Key: "human", Values: []string{"Ann"}},
+				{Key: "age", Values: []string{"26"}},
+			}})
+			require.False(t, ok)
+		})
+		t.Run("dropped", func(t *testing.T) {
+			out, ok := copyMetricWithAttr(dest, m, remappingRule{name: "copied.test.metric", div: 1, mapping: attributesMapping{
+				dynamic: map[string]string{"fruit": "remapped_fruit"},
+				dropped: []string{"fruit", "age"},
+			}})
+			require.True(t, ok)
+			require.Equal(t, out.Gauge().DataPoints().At(0).Attributes().AsRaw(), map[string]any{"remapped_fruit": "apple", "count": int64(15)})
+			require.Equal(t, out.Gauge().DataPoints().At(1).Attributes().AsRaw(), map[string]any{"human": "Ann"})
+			require.Equal(t, dest.At(dest.Len()-1), out)
+		})
 		t.Run("none", func(t *testing.T) {
-			_, ok := copyMetricWithAttr(dest, m, "copied.test.metric", 1, attributesMapping{}, kv{"human", "Paul"})
+			_, ok := copyMetricWithAttr(dest, m, remappingRule{name: "copied.test.metric", div: 1, filter: []kv{{"human", "Paul"}}})
 			require.False(t, ok)
 		})
 	})
@@ -931,7 +959,7 @@ func TestCopyMetricWithAttr(t *testing.T) {
 		dp := m.SetEmptySum().DataPoints().AppendEmpty()
 		dp.SetDoubleValue(12)
 		dp.Attributes().FromRaw(map[string]any{"fruit": "apple", "count": 15})
-		out, ok := copyMetricWithAttr(dest, m, "copied.test.metric", 1, attributesMapping{})
+		out, ok := copyMetricWithAttr(dest, m, remappingRule{name: "copied.test.metric", div: 1})
 		require.True(t, ok)
 		require.Equal(t, out.Name(), "copied.test.metric")
 		sameExceptName(t, m, out)
@@ -944,16 +972,30 @@ func TestCopyMetricWithAttr(t *testing.T) {
 		dp.SetMax(44)
 		dp.SetMin(3)
 		dp.SetSum(120)
-		_, ok := copyMetricWithAttr(dest, m, "copied.test.metric", 1, attributesMapping{})
+		dp.Attributes().FromRaw(map[string]any{"fruit": "apple"})
+		out, ok := copyMetricWithAttr(dest, m, remappingRule{name: "copied.test.metric", div: 2})
+		require.True(t, ok)
+		require.Equal(t, out.Name(), "copied.test.metric")
+		// The divisor only applies to number data points.
+		sameExceptName(t, m, out)
+		require.Equal(t, dest.At(dest.Len()-1), out)
+
+		_, ok = copyMetricWithAttr(dest, m, remappingRule{name: "copied.test.metric", filter: []kv{{"fruit", "pear"}}})
+		require.False(t, ok)
+	})
+
+	t.Run("summary", func(t *testing.T) {
+		m.SetEmptySummary().DataPoints().AppendEmpty().SetCount(12)
+		_, ok := copyMetricWithAttr(dest, m, remappingRule{name: "copied.test.metric", div: 1})
 		require.False(t, ok)
 	})
 }
 
 func TestHasAny(t *testing.T) {
-	// p returns a numberic data point having the given attributes.
-	p := func(m map[string]any) pmetric.NumberDataPoint {
-		v := pmetric.NewNumberDataPoint()
-		if err := v.Attributes().FromRaw(m); err != nil {
+	// p returns the attributes of a data point.
+	p := func(m map[string]any) pcommon.Map {
+		v := pcommon.NewMap()
+		if err := v.FromRaw(m); err != nil {
 			t.Fatalf("Error generating data point: %v", err)
 		}
 		return v
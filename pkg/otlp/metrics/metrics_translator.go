@@ -18,14 +18,16 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"math"
+	"runtime"
 	"strconv"
 	"strings"
 	"time"
 
-	"github.com/DataDog/datadog-agent/pkg/opentelemetry-mapping-go/otlp/attributes"
-	"github.com/DataDog/datadog-agent/pkg/opentelemetry-mapping-go/otlp/attributes/source"
 	"github.com/DataDog/datadog-agent/pkg/util/quantile"
+	"github.com/DataDog/opentelemetry-mapping-go/pkg/otlp/attributes"
+	"github.com/DataDog/opentelemetry-mapping-go/pkg/otlp/attributes/source"
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/pdata/pcommon"
 	"go.opentelemetry.io/collector/pdata/pmetric"
@@ -39,6 +41,7 @@ import (
 
 const (
 	metricName             string = "metric name"
+	meterName              string = "github.com/DataDog/opentelemetry-mapping-go/pkg/otlp/metrics"
 	errNoBucketsNoSumCount string = "no buckets mode and no send count sum are incompatible"
 
 	// intervalTolerance is the tolerance for interval calculation in seconds
@@ -105,6 +108,10 @@ type Translator struct {
 	prevPts              *ttlCache
 	logger               *zap.Logger
 	attributesTranslator *attributes.Translator
+	remapper             remapper
+	filter               *metricFilter
+	limiter              *cardinalityLimiter
+	telemetry            *translatorTelemetry
 	cfg                  translatorConfig
 }
 
@@ -121,6 +128,8 @@ func NewTranslator(set component.TelemetrySettings, attributesTranslator *attrib
 		SendHistogramAggregations:            false,
 		Quantiles:                            false,
 		NumberMode:                           NumberModeCumulativeToDelta,
+		NonMonotonicSumMode:                  NonMonotonicSumModeRawValue,
+		SummaryMode:                          SummaryModeCountSum,
 		InitialCumulMonoValueMode:            InitialCumulMonoValueModeAuto,
 		InstrumentationLibraryMetadataAsTags: false,
 		sweepInterval:                        1800,
@@ -141,13 +150,68 @@ func NewTranslator(set component.TelemetrySettings, attributesTranslator *attrib
 	}
 
 	cache := newTTLCache(cfg.sweepInterval, cfg.deltaTTL)
+	if cfg.cacheSnapshot != nil {
+		cache.restore(cfg.cacheSnapshot, time.Now())
+	}
+
+	telemetry, err := newTranslatorTelemetry(set, cache)
+	if err != nil {
+		return nil, err
+	}
+
+	var filter *metricFilter
+	if len(cfg.filterRules) > 0 {
+		filter, err = newMetricFilter(set, cfg.filterRules)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var limiter *cardinalityLimiter
+	if cfg.cardinalityLimit != nil {
+		limiter, err = newCardinalityLimiter(set, *cfg.cardinalityLimit)
+		if err != nil {
+			return nil, err
+		}
+	}
 
-	return &Translator{
+	defaultRules := runtimeRemappingRules
+	if cfg.withRemapping {
+		defaultRules = DefaultRemappingRules()
+	}
+	rules := mergeRemappingRules(defaultRules, cfg.remappingRules)
+
+	t := &Translator{
 		prevPts:              cache,
 		logger:               set.Logger.With(zap.String("component", "metrics translator")),
 		attributesTranslator: attributesTranslator,
+		remapper:             newRemapper(rules),
+		filter:               filter,
+		limiter:              limiter,
+		telemetry:            telemetry,
 		cfg:                  cfg,
-	}, nil
+	}
+	// The cache size callback is held by the meter provider and references the cache, but not the translator:
+	// unregister it once the translator is garbage collected without being shut down, so that the cache is too.
+	runtime.SetFinalizer(t, func(t *Translator) { _ = t.telemetry.shutdown() })
+	return t, nil
+}
+
+// SnapshotCache writes a snapshot of the state used for computing deltas from cumulative
+// timeseries to w. The snapshot can be restored on a new translator with WithCacheSnapshot.
+//
+// SnapshotCache is safe to call concurrently with MapMetrics, but points mapped while
+// the snapshot is being taken may or may not be part of it.
+func (t *Translator) SnapshotCache(w io.Writer) error {
+	return t.prevPts.snapshot(w)
+}
+
+// Shutdown releases the resources of the translator, such as the callbacks of its internal telemetry.
+// The translator must not be used after Shutdown. Translators that are not shut down release these
+// resources once they are garbage collected.
+func (t *Translator) Shutdown(_ context.Context) error {
+	runtime.SetFinalizer(t, nil)
+	return t.telemetry.shutdown()
 }
 
 // isCumulativeMonotonic checks if a metric is a cumulative monotonic metric
@@ -162,10 +226,11 @@ func isCumulativeMonotonic(md pmetric.Metric) bool {
 
 // isSkippable checks if a value can be skipped (because it is not supported by the backend).
 // It logs that the value is unsupported for debugging since this sometimes means there is a bug.
-func (t *Translator) isSkippable(name string, v float64) bool {
+func (t *Translator) isSkippable(ctx context.Context, name string, v float64) bool {
 	skippable := math.IsInf(v, 0) || math.IsNaN(v)
 	if skippable {
 		t.logger.Debug("Unsupported metric value", zap.String(metricName, name), zap.Float64("value", v))
+		t.telemetry.dropped(ctx, dropReasonUnsupportedValue, 1)
 	}
 	return skippable
 }
@@ -195,7 +260,7 @@ func (t *Translator) mapNumberMetrics(
 			val = float64(p.IntValue())
 		}
 
-		if t.isSkippable(pointDims.name, val) {
+		if t.isSkippable(ctx, pointDims.name, val) {
 			continue
 		}
 
@@ -209,6 +274,48 @@ func (t *Translator) mapNumberMetrics(
 	}
 }
 
+// mapNumberNonMonotonicMetrics maps cumulative non-monotonic datapoints into Datadog counts
+// of the change in value since the previous datapoint.
+func (t *Translator) mapNumberNonMonotonicMetrics(
+	ctx context.Context,
+	consumer TimeSeriesConsumer,
+	dims *Dimensions,
+	slice pmetric.NumberDataPointSlice,
+) {
+	for i := 0; i < slice.Len(); i++ {
+		p := slice.At(i)
+		if p.Flags().NoRecordedValue() {
+			// No recorded value, skip.
+			continue
+		}
+
+		pointDims := dims.WithAttributeMap(p.Attributes())
+
+		var val float64
+		switch p.ValueType() {
+		case pmetric.NumberDataPointValueTypeDouble:
+			val = p.DoubleValue()
+		case pmetric.NumberDataPointValueTypeInt:
+			val = float64(p.IntValue())
+		}
+
+		if t.isSkippable(ctx, pointDims.name, val) {
+			continue
+		}
+
+		if !t.limiter.admitCumulative(ctx, pointDims) {
+			continue
+		}
+
+		// The first point of a timeseries only initializes the cache.
+		if dx, ok, drop := t.prevPts.Diff(pointDims, uint64(p.StartTimestamp()), uint64(p.Timestamp()), val); ok {
+			consumer.ConsumeTimeSeries(ctx, pointDims, Count, uint64(p.Timestamp()), 0, dx)
+		} else {
+			t.telemetry.dropped(ctx, drop, 1)
+		}
+	}
+}
+
 // TODO(songy23): consider changing this to a Translator start time that must be initialized
 // if the package-level variable causes any issue.
 var startTime = uint64(time.Now().UnixNano())
@@ -266,7 +373,11 @@ func (t *Translator) mapNumberMonotonicMetrics(
 			val = float64(p.IntValue())
 		}
 
-		if t.isSkippable(pointDims.name, val) {
+		if t.isSkippable(ctx, pointDims.name, val) {
+			continue
+		}
+
+		if !t.limiter.admitCumulative(ctx, pointDims) {
 			continue
 		}
 
@@ -274,6 +385,7 @@ func (t *Translator) mapNumberMonotonicMetrics(
 			dx, isFirstPoint, shouldDropPoint := t.prevPts.MonotonicRate(pointDims, startTs, ts, val)
 			if shouldDropPoint {
 				t.logger.Debug("Dropping point: timestamp is older or equal to timestamp of previous point received", zap.String(metricName, pointDims.name))
+				t.telemetry.dropped(ctx, dropReasonOutOfOrder, 1)
 			} else if !isFirstPoint {
 				consumer.ConsumeTimeSeries(ctx, pointDims, Gauge, ts, 0, dx)
 			}
@@ -283,6 +395,7 @@ func (t *Translator) mapNumberMonotonicMetrics(
 		dx, isFirstPoint, shouldDropPoint := t.prevPts.MonotonicDiff(pointDims, startTs, ts, val)
 		if shouldDropPoint {
 			t.logger.Debug("Dropping point: timestamp is older or equal to timestamp of previous point received", zap.String(metricName, pointDims.name))
+			t.telemetry.dropped(ctx, dropReasonOutOfOrder, 1)
 			continue
 		}
 
@@ -400,7 +513,7 @@ func (t *Translator) getSketchBuckets(
 			if err != nil {
 				return err
 			}
-		} else if dx, ok := t.prevPts.Diff(bucketDims, startTs, ts, float64(count)); ok {
+		} else if dx, ok, _ := t.prevPts.Diff(bucketDims, startTs, ts, float64(count)); ok {
 			nonZeroBucket = dx > 0
 			err := as.InsertInterpolate(lowerBound, upperBound, uint(dx))
 			if err != nil {
@@ -485,7 +598,7 @@ func (t *Translator) getLegacyBuckets(
 		count := float64(p.BucketCounts().At(idx))
 		if delta {
 			consumer.ConsumeTimeSeries(ctx, bucketDims, Count, ts, 0, count)
-		} else if dx, ok := t.prevPts.Diff(bucketDims, startTs, ts, count); ok {
+		} else if dx, ok, _ := t.prevPts.Diff(bucketDims, startTs, ts, count); ok {
 			consumer.ConsumeTimeSeries(ctx, bucketDims, Count, ts, 0, dx)
 		}
 	}
@@ -521,23 +634,28 @@ func (t *Translator) mapHistogramMetrics(
 		startTs := uint64(p.StartTimestamp())
 		ts := uint64(p.Timestamp())
 		pointDims := dims.WithAttributeMap(p.Attributes())
+		if !delta && !t.limiter.admitCumulative(ctx, pointDims) {
+			continue
+		}
 
 		histInfo := histogramInfo{ok: true}
 
 		countDims := pointDims.WithSuffix("count")
 		if delta {
 			histInfo.count = p.Count()
-		} else if dx, ok := t.prevPts.Diff(countDims, startTs, ts, float64(p.Count())); ok {
+		} else if dx, ok, drop := t.prevPts.Diff(countDims, startTs, ts, float64(p.Count())); ok {
 			histInfo.count = uint64(dx)
 		} else { // not ok
 			histInfo.ok = false
+			// The buckets of the point have no delta either: the whole point is dropped.
+			t.telemetry.dropped(ctx, drop, 1)
 		}
 
 		sumDims := pointDims.WithSuffix("sum")
-		if !t.isSkippable(sumDims.name, p.Sum()) {
+		if !t.isSkippable(ctx, sumDims.name, p.Sum()) {
 			if delta {
 				histInfo.sum = p.Sum()
-			} else if dx, ok := t.prevPts.Diff(sumDims, startTs, ts, p.Sum()); ok {
+			} else if dx, ok, _ := t.prevPts.Diff(sumDims, startTs, ts, p.Sum()); ok {
 				histInfo.sum = dx
 			} else { // not ok
 				histInfo.ok = false
@@ -619,7 +737,7 @@ func getQuantileTag(quantile float64) string {
 // mapSummaryMetrics maps summary datapoints into Datadog metrics
 func (t *Translator) mapSummaryMetrics(
 	ctx context.Context,
-	consumer TimeSeriesConsumer,
+	consumer Consumer,
 	dims *Dimensions,
 	slice pmetric.SummaryDataPointSlice,
 ) {
@@ -634,29 +752,38 @@ func (t *Translator) mapSummaryMetrics(
 		startTs := uint64(p.StartTimestamp())
 		ts := uint64(p.Timestamp())
 		pointDims := dims.WithAttributeMap(p.Attributes())
+		if !t.limiter.admitCumulative(ctx, pointDims) {
+			continue
+		}
 
-		// treat count as a cumulative monotonic metric
-		// and sum as a non-monotonic metric
-		// https://prometheus.io/docs/practices/histograms/#count-and-sum-of-observations
-		{
-			countDims := pointDims.WithSuffix("count")
-			val := float64(p.Count())
-			dx, isFirstPoint, shouldDropPoint := t.prevPts.MonotonicDiff(countDims, startTs, ts, val)
-			if !shouldDropPoint && !t.isSkippable(countDims.name, dx) {
-				if !isFirstPoint {
-					consumer.ConsumeTimeSeries(ctx, countDims, Count, ts, 0, dx)
-				} else if i == 0 && t.shouldConsumeInitialValue(startTs, ts) {
-					// We only compute the first point in the timeseries if it is the first value in the datapoint slice.
-					consumer.ConsumeTimeSeries(ctx, countDims, Count, ts, 0, val)
+		if t.cfg.SummaryMode == SummaryModeDistributions {
+			t.mapSummaryDistribution(ctx, consumer, pointDims, p, i == 0)
+		} else {
+			// treat count as a cumulative monotonic metric
+			// and sum as a non-monotonic metric
+			// https://prometheus.io/docs/practices/histograms/#count-and-sum-of-observations
+			{
+				countDims := pointDims.WithSuffix("count")
+				val := float64(p.Count())
+				dx, isFirstPoint, shouldDropPoint := t.prevPts.MonotonicDiff(countDims, startTs, ts, val)
+				if shouldDropPoint {
+					t.telemetry.dropped(ctx, dropReasonOutOfOrder, 1)
+				} else if !t.isSkippable(ctx, countDims.name, dx) {
+					if !isFirstPoint {
+						consumer.ConsumeTimeSeries(ctx, countDims, Count, ts, 0, dx)
+					} else if i == 0 && t.shouldConsumeInitialValue(startTs, ts) {
+						// We only compute the first point in the timeseries if it is the first value in the datapoint slice.
+						consumer.ConsumeTimeSeries(ctx, countDims, Count, ts, 0, val)
+					}
 				}
 			}
-		}
 
-		{
-			sumDims := pointDims.WithSuffix("sum")
-			if !t.isSkippable(sumDims.name, p.Sum()) {
-				if dx, ok := t.prevPts.Diff(sumDims, startTs, ts, p.Sum()); ok {
-					consumer.ConsumeTimeSeries(ctx, sumDims, Count, ts, 0, dx)
+			{
+				sumDims := pointDims.WithSuffix("sum")
+				if !t.isSkippable(ctx, sumDims.name, p.Sum()) {
+					if dx, ok, _ := t.prevPts.Diff(sumDims, startTs, ts, p.Sum()); ok {
+						consumer.ConsumeTimeSeries(ctx, sumDims, Count, ts, 0, dx)
+					}
 				}
 			}
 		}
@@ -667,7 +794,7 @@ func (t *Translator) mapSummaryMetrics(
 			for i := 0; i < quantiles.Len(); i++ {
 				q := quantiles.At(i)
 
-				if t.isSkippable(baseQuantileDims.name, q.Value()) {
+				if t.isSkippable(ctx, baseQuantileDims.name, q.Value()) {
 					continue
 				}
 
@@ -700,102 +827,16 @@ func extractLanguageTag(name string, languageTags []string) []string {
 	return languageTags
 }
 
-// mapGaugeRuntimeMetricWithAttributes maps the specified runtime metric from metric attributes into a new Gauge metric
-func mapGaugeRuntimeMetricWithAttributes(md pmetric.Metric, metricsArray pmetric.MetricSlice, mp runtimeMetricMapping) {
-	for i := 0; i < md.Gauge().DataPoints().Len(); i++ {
-		matchesAttributes := true
-		for _, attribute := range mp.attributes {
-			attributeValue, res := md.Gauge().DataPoints().At(i).Attributes().Get(attribute.key)
-			if !res || !slices.Contains(attribute.values, attributeValue.AsString()) {
-				matchesAttributes = false
-				break
-			}
-		}
-		if matchesAttributes {
-			cp := metricsArray.AppendEmpty()
-			cp.SetEmptyGauge()
-			dataPoint := cp.Gauge().DataPoints().AppendEmpty()
-			md.Gauge().DataPoints().At(i).CopyTo(dataPoint)
-			dataPoint.Attributes().RemoveIf(func(s string, value pcommon.Value) bool {
-				for _, attribute := range mp.attributes {
-					if s == attribute.key {
-						return true
-					}
-				}
-				return false
-			})
-			cp.SetName(mp.mappedName)
-		}
-	}
-}
-
-// mapSumRuntimeMetricWithAttributes maps the specified runtime metric from metric attributes into a new Sum metric
-func mapSumRuntimeMetricWithAttributes(md pmetric.Metric, metricsArray pmetric.MetricSlice, mp runtimeMetricMapping) {
-	for i := 0; i < md.Sum().DataPoints().Len(); i++ {
-		matchesAttributes := true
-		for _, attribute := range mp.attributes {
-			attributeValue, res := md.Sum().DataPoints().At(i).Attributes().Get(attribute.key)
-			if !res || !slices.Contains(attribute.values, attributeValue.AsString()) {
-				matchesAttributes = false
-				break
-			}
-		}
-		if matchesAttributes {
-			cp := metricsArray.AppendEmpty()
-			cp.SetEmptySum()
-			cp.Sum().SetAggregationTemporality(md.Sum().AggregationTemporality())
-			cp.Sum().SetIsMonotonic(md.Sum().IsMonotonic())
-			dataPoint := cp.Sum().DataPoints().AppendEmpty()
-			md.Sum().DataPoints().At(i).CopyTo(dataPoint)
-			dataPoint.Attributes().RemoveIf(func(s string, value pcommon.Value) bool {
-				for _, attribute := range mp.attributes {
-					if s == attribute.key {
-						return true
-					}
-				}
-				return false
-			})
-			cp.SetName(mp.mappedName)
-		}
-	}
-}
-
-// mapHistogramRuntimeMetricWithAttributes maps the specified runtime metric from metric attributes into a new Histogram metric
-func mapHistogramRuntimeMetricWithAttributes(md pmetric.Metric, metricsArray pmetric.MetricSlice, mp runtimeMetricMapping) {
-	for i := 0; i < md.Histogram().DataPoints().Len(); i++ {
-		matchesAttributes := true
-		for _, attribute := range mp.attributes {
-			attributeValue, res := md.Histogram().DataPoints().At(i).Attributes().Get(attribute.key)
-			if !res || !slices.Contains(attribute.values, attributeValue.AsString()) {
-				matchesAttributes = false
-				break
-			}
-		}
-		if matchesAttributes {
-			cp := metricsArray.AppendEmpty()
-			cp.SetEmptyHistogram()
-			cp.Histogram().SetAggregationTemporality(md.Histogram().AggregationTemporality())
-			dataPoint := cp.Histogram().DataPoints().AppendEmpty()
-			md.Histogram().DataPoints().At(i).CopyTo(dataPoint)
-			dataPoint.Attributes().RemoveIf(func(s string, value pcommon.Value) bool {
-				for _, attribute := range mp.attributes {
-					if s == attribute.key {
-						return true
-					}
-				}
-				return false
-			})
-			cp.SetName(mp.mappedName)
-			break
-		}
-	}
-}
-
 // MapMetrics maps OTLP metrics into the Datadog format
 func (t *Translator) MapMetrics(ctx context.Context, md pmetric.Metrics, consumer Consumer, hostFromAttributesHandler attributes.HostFromAttributesHandler) (Metadata, error) {
+	start := time.Now()
+	defer func() { t.telemetry.mapDuration.Record(ctx, time.Since(start).Seconds()) }()
+
 	metadata := Metadata{
 		Languages: []string{},
 	}
+	// Filters and cardinality limits only apply to metrics: the original consumer is used for hosts and tags.
+	telemetryConsumer := t.telemetry.wrap(consumer)
 	rms := md.ResourceMetrics()
 	for i := 0; i < rms.Len(); i++ {
 		rm := rms.At(i)
@@ -832,6 +873,8 @@ func (t *Translator) MapMetrics(ctx context.Context, md pmetric.Metrics, consume
 			}
 
 			scopeName := ilm.Scope().Name()
+			// The cardinality limit applies to the points kept by filters, after deltas are computed.
+			metricsConsumer := t.filter.wrap(t.limiter.wrap(telemetryConsumer, additionalTags))
 
 			newMetrics := pmetric.NewMetricSlice()
 			for k := 0; k < metricsArray.Len(); k++ {
@@ -846,38 +889,21 @@ func (t *Translator) MapMetrics(ctx context.Context, md pmetric.Metrics, consume
 					}
 					continue
 				}
-				if v, ok := runtimeMetricsMappings[md.Name()]; ok {
+				if _, ok := runtimeMetricNames[md.Name()]; ok {
 					metadata.Languages = extractLanguageTag(md.Name(), metadata.Languages)
-					for _, mp := range v {
-						if mp.attributes == nil {
-							// duplicate runtime metrics as Datadog runtime metrics
-							cp := newMetrics.AppendEmpty()
-							md.CopyTo(cp)
-							cp.SetName(mp.mappedName)
-							break
-						}
-						if md.Type() == pmetric.MetricTypeSum {
-							mapSumRuntimeMetricWithAttributes(md, newMetrics, mp)
-						} else if md.Type() == pmetric.MetricTypeGauge {
-							mapGaugeRuntimeMetricWithAttributes(md, newMetrics, mp)
-						} else if md.Type() == pmetric.MetricTypeHistogram {
-							mapHistogramRuntimeMetricWithAttributes(md, newMetrics, mp)
-						}
-					}
 				} else {
 					// If we are here, we have a non-APM metric:
 					// it is not a stats metric, nor a runtime metric.
 					seenNonAPMMetrics = true
 				}
 
-				if t.cfg.withRemapping {
-					remapMetrics(newMetrics, md)
-				}
+				t.telemetry.received(ctx, md)
+				t.remapper.remap(newMetrics, md)
 				if t.cfg.withOTelPrefix {
 					renameMetrics(md)
 				}
 
-				err := t.mapToDDFormat(ctx, md, consumer, additionalTags, host, scopeName, rattrs)
+				err := t.mapToDDFormat(ctx, md, metricsConsumer, additionalTags, host, scopeName, rattrs)
 				if err != nil {
 					return metadata, err
 				}
@@ -885,7 +911,7 @@ func (t *Translator) MapMetrics(ctx context.Context, md pmetric.Metrics, consume
 
 			for k := 0; k < newMetrics.Len(); k++ {
 				md := newMetrics.At(k)
-				err := t.mapToDDFormat(ctx, md, consumer, additionalTags, host, scopeName, rattrs)
+				err := t.mapToDDFormat(ctx, md, metricsConsumer, additionalTags, host, scopeName, rattrs)
 				if err != nil {
 					return metadata, err
 				}
@@ -899,7 +925,8 @@ func (t *Translator) MapMetrics(ctx context.Context, md pmetric.Metrics, consume
 				if c, ok := consumer.(HostConsumer); ok {
 					c.ConsumeHost(host)
 				}
-			case source.AWSECSFargateKind:
+			case source.AWSECSFargateKind, source.AWSEKSFargateKind, source.AWSLambdaKind,
+				source.GCPCloudRunKind, source.AzureContainerAppsKind:
 				if c, ok := consumer.(TagsConsumer); ok {
 					c.ConsumeTag(src.Tag())
 				}
@@ -932,6 +959,8 @@ func (t *Translator) mapToDDFormat(ctx context.Context, md pmetric.Metric, consu
 				case NumberModeRawValue:
 					t.mapNumberMetrics(ctx, consumer, baseDims, Gauge, md.Sum().DataPoints())
 				}
+			} else if t.cfg.NonMonotonicSumMode == NonMonotonicSumModeCumulativeToDelta {
+				t.mapNumberNonMonotonicMetrics(ctx, consumer, baseDims, md.Sum().DataPoints())
 			} else { // delta and cumulative non-monotonic sums
 				t.mapNumberMetrics(ctx, consumer, baseDims, Gauge, md.Sum().DataPoints())
 			}
@@ -942,6 +971,7 @@ func (t *Translator) mapToDDFormat(ctx context.Context, md pmetric.Metric, consu
 				zap.String(metricName, md.Name()),
 				zap.Any("aggregation temporality", md.Sum().AggregationTemporality()),
 			)
+			t.telemetry.dropped(ctx, dropReasonUnsupportedTemporality, md.Sum().DataPoints().Len())
 		}
 	case pmetric.MetricTypeHistogram:
 		switch md.Histogram().AggregationTemporality() {
@@ -956,22 +986,25 @@ func (t *Translator) mapToDDFormat(ctx context.Context, md pmetric.Metric, consu
 				zap.String("metric name", md.Name()),
 				zap.Any("aggregation temporality", md.Histogram().AggregationTemporality()),
 			)
+			t.telemetry.dropped(ctx, dropReasonUnsupportedTemporality, md.Histogram().DataPoints().Len())
 		}
 	case pmetric.MetricTypeExponentialHistogram:
 		switch md.ExponentialHistogram().AggregationTemporality() {
-		case pmetric.AggregationTemporalityDelta:
+		case pmetric.AggregationTemporalityCumulative, pmetric.AggregationTemporalityDelta:
 			delta := md.ExponentialHistogram().AggregationTemporality() == pmetric.AggregationTemporalityDelta
 			t.mapExponentialHistogramMetrics(ctx, consumer, baseDims, md.ExponentialHistogram().DataPoints(), delta)
-		default: // pmetric.AggregationTemporalityCumulative, pmetric.AggregationTemporalityUnspecified or any other not supported type
+		default: // pmetric.AggregationTemporalityUnspecified or any other not supported type
 			t.logger.Debug("Unknown or unsupported aggregation temporality",
 				zap.String("metric name", md.Name()),
 				zap.Any("aggregation temporality", md.ExponentialHistogram().AggregationTemporality()),
 			)
+			t.telemetry.dropped(ctx, dropReasonUnsupportedTemporality, md.ExponentialHistogram().DataPoints().Len())
 		}
 	case pmetric.MetricTypeSummary:
 		t.mapSummaryMetrics(ctx, consumer, baseDims, md.Summary().DataPoints())
 	default: // pmetric.MetricDataTypeNone or any other not supported type
 		t.logger.Debug("Unknown or unsupported metric type", zap.String(metricName, md.Name()), zap.Any("data type", md.Type()))
+		t.telemetry.dropped(ctx, dropReasonUnknownMetricType, 1)
 	}
 	return nil
 }
@@ -24,7 +24,7 @@ import (
 	"go.opentelemetry.io/collector/pdata/pmetric"
 	"go.uber.org/zap"
 
-	"github.com/DataDog/datadog-agent/pkg/opentelemetry-mapping-go/otlp/attributes"
+	"github.com/DataDog/opentelemetry-mapping-go/pkg/otlp/attributes"
 )
 
 const (
@@ -164,7 +164,7 @@ func createBenchmarkDeltaSumMetrics(n int, additionalAttributes map[string]strin
 	return md
 }
 
-func createBenchmarkRuntimeMetric(metricName string, metricType pmetric.MetricType, attributes []runtimeMetricAttribute, dataPoints int) pmetric.Metrics {
+func createBenchmarkRuntimeMetric(metricName string, metricType pmetric.MetricType, attributes []AttributeValues, dataPoints int) pmetric.Metrics {
 	md := pmetric.NewMetrics()
 	met := md.ResourceMetrics().AppendEmpty().ScopeMetrics().AppendEmpty().Metrics().AppendEmpty()
 	met.SetName(metricName)
@@ -190,7 +190,7 @@ func createBenchmarkRuntimeMetric(metricName string, metricType pmetric.MetricTy
 			startTs := int(getProcessStartTime()) + 1
 			dpInt := dpsInt.AppendEmpty()
 			for _, attr := range attributes {
-				dpInt.Attributes().PutStr(attr.key, attr.values[0])
+				dpInt.Attributes().PutStr(attr.Key, attr.Values[0])
 			}
 			dpInt.SetStartTimestamp(seconds(startTs))
 			dpInt.SetTimestamp(seconds(startTs + 1 + i))
@@ -204,7 +204,7 @@ func createBenchmarkRuntimeMetric(metricName string, metricType pmetric.MetricTy
 		startTs := int(getProcessStartTime()) + 1
 		hpCount := hpsCount.AppendEmpty()
 		for _, attr := range attributes {
-			hpCount.Attributes().PutStr(attr.key, attr.values[0])
+			hpCount.Attributes().PutStr(attr.Key, attr.Values[0])
 		}
 		hpCount.SetStartTimestamp(seconds(startTs))
 		hpCount.SetTimestamp(seconds(startTs + 1 + i))
@@ -509,9 +509,9 @@ func BenchmarkMapSumRuntimeMetric(b *testing.B) {
 }
 
 func BenchmarkMapGaugeRuntimeMetricWithAttributesHasMapping(b *testing.B) {
-	attr := []runtimeMetricAttribute{{
-		key:    "generation",
-		values: []string{"gen1"},
+	attr := []AttributeValues{{
+		Key:    "generation",
+		Values: []string{"gen1"},
 	}}
 
 	for _, v := range inputTable {
@@ -525,11 +525,11 @@ func BenchmarkMapGaugeRuntimeMetricWithAttributesHasMapping(b *testing.B) {
 }
 
 func BenchmarkMapGaugeRuntimeMetricWith10AttributesHasMapping(b *testing.B) {
-	var attr []runtimeMetricAttribute
+	var attr []AttributeValues
 	for i := 1; i <= 10; i++ {
-		attr = append(attr, runtimeMetricAttribute{
-			key:    "generation",
-			values: []string{fmt.Sprintf("gen%d", i)},
+		attr = append(attr, AttributeValues{
+			Key:    "generation",
+			Values: []string{fmt.Sprintf("gen%d", i)},
 		})
 	}
 
@@ -544,11 +544,11 @@ func BenchmarkMapGaugeRuntimeMetricWith10AttributesHasMapping(b *testing.B) {
 }
 
 func BenchmarkMapGaugeRuntimeMetricWith100AttributesHasMapping(b *testing.B) {
-	var attr []runtimeMetricAttribute
+	var attr []AttributeValues
 	for i := 1; i <= 100; i++ {
-		attr = append(attr, runtimeMetricAttribute{
-			key:    "generation",
-			values: []string{fmt.Sprintf("gen%d", i)},
+		attr = append(attr, AttributeValues{
+			Key:    "generation",
+			Values: []string{fmt.Sprintf("gen%d", i)},
 		})
 	}
 
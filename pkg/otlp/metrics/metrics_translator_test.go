@@ -20,11 +20,11 @@ import (
 	"testing"
 	"time"
 
-	"github.com/DataDog/datadog-agent/pkg/opentelemetry-mapping-go/otlp/attributes"
-	"github.com/DataDog/datadog-agent/pkg/opentelemetry-mapping-go/otlp/attributes/source"
 	pb "github.com/DataDog/datadog-agent/pkg/proto/pbgo/trace"
 	"github.com/DataDog/datadog-agent/pkg/util/quantile"
 	"github.com/DataDog/datadog-agent/pkg/util/quantile/summary"
+	"github.com/DataDog/opentelemetry-mapping-go/pkg/otlp/attributes"
+	"github.com/DataDog/opentelemetry-mapping-go/pkg/otlp/attributes/source"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/collector/component/componenttest"
@@ -1126,9 +1126,9 @@ func TestMapSumRuntimeMetricWithAttributesHasMapping(t *testing.T) {
 	ctx := context.Background()
 	tr := newTranslator(t, zap.NewNop())
 	consumer := &mockFullConsumer{}
-	attributes := []runtimeMetricAttribute{{
-		key:    "generation",
-		values: []string{"gen0"},
+	attributes := []AttributeValues{{
+		Key:    "generation",
+		Values: []string{"gen0"},
 	}}
 	rmt, err := tr.MapMetrics(ctx, createTestMetricWithAttributes("process.runtime.dotnet.gc.collections.count", pmetric.MetricTypeSum, attributes, 1), consumer, nil)
 	if err != nil {
@@ -1149,9 +1149,9 @@ func TestMapSumRuntimeMetricWithAttributesHasMappingCollector(t *testing.T) {
 	ctx := context.Background()
 	tr := NewTestTranslator(t, WithRemapping())
 	consumer := &mockFullConsumer{}
-	attributes := []runtimeMetricAttribute{{
-		key:    "generation",
-		values: []string{"gen0"},
+	attributes := []AttributeValues{{
+		Key:    "generation",
+		Values: []string{"gen0"},
 	}}
 	rmt, err := tr.MapMetrics(ctx, createTestMetricWithAttributes("process.runtime.dotnet.gc.collections.count", pmetric.MetricTypeSum, attributes, 1), consumer, nil)
 	if err != nil {
@@ -1172,9 +1172,9 @@ func TestMapGaugeRuntimeMetricWithAttributesHasMapping(t *testing.T) {
 	ctx := context.Background()
 	tr := newTranslator(t, zap.NewNop())
 	consumer := &mockFullConsumer{}
-	attributes := []runtimeMetricAttribute{{
-		key:    "generation",
-		values: []string{"gen1"},
+	attributes := []AttributeValues{{
+		Key:    "generation",
+		Values: []string{"gen1"},
 	}}
 	rmt, err := tr.MapMetrics(ctx, createTestMetricWithAttributes("process.runtime.dotnet.gc.heap.size", pmetric.MetricTypeGauge, attributes, 1), consumer, nil)
 	if err != nil {
@@ -1221,9 +1221,9 @@ func TestMapHistogramRuntimeMetricWithAttributesHasMapping(t *testing.T) {
 	ctx := context.Background()
 	tr := newTranslator(t, zap.NewNop())
 	consumer := &mockFullConsumer{}
-	attributes := []runtimeMetricAttribute{{
-		key:    "generation",
-		values: []string{"gen1"},
+	attributes := []AttributeValues{{
+		Key:    "generation",
+		Values: []string{"gen1"},
 	}}
 	rmt, err := tr.MapMetrics(ctx, createTestMetricWithAttributes("process.runtime.dotnet.gc.heap.size", pmetric.MetricTypeHistogram, attributes, 1), consumer, nil)
 	if err != nil {
@@ -1250,12 +1250,12 @@ func TestMapRuntimeMetricWithTwoAttributesHasMapping(t *testing.T) {
 	ctx := context.Background()
 	tr := newTranslator(t, zap.NewNop())
 	consumer := &mockFullConsumer{}
-	attributes := []runtimeMetricAttribute{{
-		key:    "pool",
-		values: []string{"G1 Old Gen"},
+	attributes := []AttributeValues{{
+		Key:    "pool",
+		Values: []string{"G1 Old Gen"},
 	}, {
-		key:    "type",
-		values: []string{"heap"},
+		Key:    "type",
+		Values: []string{"heap"},
 	}}
 	rmt, err := tr.MapMetrics(ctx, createTestMetricWithAttributes("process.runtime.jvm.memory.usage", pmetric.MetricTypeGauge, attributes, 1), consumer, nil)
 	if err != nil {
@@ -1277,12 +1277,12 @@ func TestMapRuntimeMetricWithTwoAttributesMultipleDataPointsHasMapping(t *testin
 	ctx := context.Background()
 	tr := newTranslator(t, zap.NewNop())
 	consumer := &mockFullConsumer{}
-	attributes := []runtimeMetricAttribute{{
-		key:    "pool",
-		values: []string{"G1 Old Gen", "G1 Survivor Space", "G1 Eden Space"},
+	attributes := []AttributeValues{{
+		Key:    "pool",
+		Values: []string{"G1 Old Gen", "G1 Survivor Space", "G1 Eden Space"},
 	}, {
-		key:    "type",
-		values: []string{"heap", "heap", "heap"},
+		Key:    "type",
+		Values: []string{"heap", "heap", "heap"},
 	}}
 	rmt, err := tr.MapMetrics(ctx, createTestMetricWithAttributes("process.runtime.jvm.memory.usage", pmetric.MetricTypeGauge, attributes, 3), consumer, nil)
 	if err != nil {
@@ -1350,9 +1350,9 @@ func TestMapGaugeRuntimeMetricWithInvalidAttributes(t *testing.T) {
 	ctx := context.Background()
 	tr := newTranslator(t, zap.NewNop())
 	consumer := &mockFullConsumer{}
-	attributes := []runtimeMetricAttribute{{
-		key:    "type",
-		values: []string{"heap2"},
+	attributes := []AttributeValues{{
+		Key:    "type",
+		Values: []string{"heap2"},
 	}}
 	rmt, err := tr.MapMetrics(ctx, createTestMetricWithAttributes("process.runtime.jvm.memory.usage", pmetric.MetricTypeGauge, attributes, 1), consumer, nil)
 	if err != nil {
@@ -2207,7 +2207,7 @@ func createTestHistogramMetric(metricName string) pmetric.Metrics {
 	return md
 }
 
-func createTestMetricWithAttributes(metricName string, metricType pmetric.MetricType, attributes []runtimeMetricAttribute, dataPoints int) pmetric.Metrics {
+func createTestMetricWithAttributes(metricName string, metricType pmetric.MetricType, attributes []AttributeValues, dataPoints int) pmetric.Metrics {
 	md := pmetric.NewMetrics()
 	met := md.ResourceMetrics().AppendEmpty().ScopeMetrics().AppendEmpty().Metrics().AppendEmpty()
 	met.SetName(metricName)
@@ -2233,7 +2233,7 @@ func createTestMetricWithAttributes(metricName string, metricType pmetric.Metric
 			startTs := int(getProcessStartTime()) + 1
 			dpInt := dpsInt.AppendEmpty()
 			for _, attr := range attributes {
-				dpInt.Attributes().PutStr(attr.key, attr.values[i])
+				dpInt.Attributes().PutStr(attr.Key, attr.Values[i])
 			}
 			dpInt.SetStartTimestamp(seconds(startTs))
 			dpInt.SetTimestamp(seconds(startTs + 1 + i))
@@ -2247,7 +2247,7 @@ func createTestMetricWithAttributes(metricName string, metricType pmetric.Metric
 		startTs := int(getProcessStartTime()) + 1
 		hpCount := hpsCount.AppendEmpty()
 		for _, attr := range attributes {
-			hpCount.Attributes().PutStr(attr.key, attr.values[i])
+			hpCount.Attributes().PutStr(attr.Key, attr.Values[i])
 		}
 		hpCount.SetStartTimestamp(seconds(startTs))
 		hpCount.SetTimestamp(seconds(startTs + 1 + i))
@@ -2397,3 +2397,100 @@ func TestInferInterval(t *testing.T) {
 		})
 	}
 }
+
+type mockSourceConsumer struct {
+	mockFullConsumer
+	hosts []string
+	tags  []string
+}
+
+func (c *mockSourceConsumer) ConsumeHost(host string) {
+	c.hosts = append(c.hosts, host)
+}
+
+func (c *mockSourceConsumer) ConsumeTag(tag string) {
+	c.tags = append(c.tags, tag)
+}
+
+func TestMapMetricsSourceKinds(t *testing.T) {
+	tests := []struct {
+		name     string
+		resAttrs map[string]any
+		host     string
+		tag      string
+	}{
+		{
+			name:     "host",
+			resAttrs: map[string]any{"host.name": "example-host"},
+			host:     "example-host",
+		},
+		{
+			name: "AWS ECS Fargate",
+			resAttrs: map[string]any{
+				"aws.ecs.launchtype": "fargate",
+				"aws.ecs.task.arn":   "example-task-arn",
+			},
+			tag: "task_arn:example-task-arn",
+		},
+		{
+			name: "AWS EKS Fargate",
+			resAttrs: map[string]any{
+				"cloud.platform": "aws_eks",
+				"k8s.node.name":  "fargate-ip-10-0-1-2.ec2.internal",
+			},
+			tag: "eks_fargate_node:fargate-ip-10-0-1-2.ec2.internal",
+		},
+		{
+			name: "AWS Lambda",
+			resAttrs: map[string]any{
+				"cloud.platform":    "aws_lambda",
+				"cloud.resource_id": "arn:aws:lambda:us-east-1:123456789012:function:example",
+			},
+			tag: "function_arn:arn:aws:lambda:us-east-1:123456789012:function:example",
+		},
+		{
+			name: "GCP Cloud Run",
+			resAttrs: map[string]any{
+				"cloud.platform": "gcp_cloud_run",
+				"faas.name":      "example-service",
+			},
+			tag: "cloud_run:example-service",
+		},
+		{
+			name: "Azure Container Apps",
+			resAttrs: map[string]any{
+				"cloud.platform": "azure_container_apps",
+				"faas.name":      "example-app",
+			},
+			tag: "container_app:example-app",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			md := pmetric.NewMetrics()
+			rm := md.ResourceMetrics().AppendEmpty()
+			require.NoError(t, rm.Resource().Attributes().FromRaw(tt.resAttrs))
+			m := rm.ScopeMetrics().AppendEmpty().Metrics().AppendEmpty()
+			m.SetName("gauge")
+			m.SetEmptyGauge().DataPoints().AppendEmpty().SetDoubleValue(1)
+
+			consumer := &mockSourceConsumer{}
+			_, err := NewTestTranslator(t).MapMetrics(context.Background(), md, consumer, nil)
+			require.NoError(t, err)
+			require.Len(t, consumer.metrics, 1)
+			if tt.host != "" {
+				assert.Equal(t, []string{tt.host}, consumer.hosts)
+				assert.Equal(t, tt.host, consumer.metrics[0].host)
+			} else {
+				assert.Empty(t, consumer.hosts)
+				assert.Empty(t, consumer.metrics[0].host)
+			}
+			if tt.tag != "" {
+				assert.Equal(t, []string{tt.tag}, consumer.tags)
+			} else {
+				assert.Empty(t, consumer.tags)
+			}
+		})
+	}
+}
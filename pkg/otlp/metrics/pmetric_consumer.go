@@ -0,0 +1,251 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2023-present Datadog, Inc.
+
+package metrics
+
+import (
+	"context"
+	"maps"
+	"math"
+	"slices"
+	"strings"
+
+	"github.com/DataDog/datadog-agent/pkg/util/quantile"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+)
+
+const (
+	// exponentialHistogramScale is the scale of the exponential histograms built from sketches.
+	// Its base (2^(2^-6) ≈ 1.011) is smaller than the gamma of the sketches, so that
+	// every sketch bin maps to a different bucket.
+	exponentialHistogramScale = 6
+)
+
+// sketchGamma and sketchBias are the parameters of the quantile.Default() sketch configuration,
+// which maps a key k to the value sketchGamma^(k-sketchBias).
+var sketchGamma, sketchBias = sketchParameters(quantile.Default())
+
+// sketchParameters returns the gamma and bias of a sketch configuration from the bounds of its bins.
+func sketchParameters(config *quantile.Config) (gamma float64, bias int) {
+	bins := quantile.NewDDSketchBinGenerator(config)
+	// 1 = gamma^0 is the lower bound of the bin whose key is the bias.
+	key := bins.GetKeyForValue(1)
+	low, _ := bins.GetBound(key)
+	next, _ := bins.GetBound(key + 1)
+	return next.Low / low.Low, int(key)
+}
+
+// resourceTags maps the Datadog tags that are set as resource attributes to their attribute names.
+var resourceTags = map[string]string{
+	"host":    "host.name",
+	"env":     "deployment.environment.name",
+	"service": "service.name",
+}
+
+var _ Consumer = (*PmetricConsumer)(nil)
+
+// PmetricConsumer is a Consumer that builds OTLP metrics from the Datadog metrics it consumes.
+// It is the reverse of Translator.MapMetrics:
+//
+//   - Count timeseries become delta monotonic sums,
+//   - Gauge timeseries become gauges,
+//   - sketches become delta exponential histograms,
+//   - the host, and the `host`, `env` and `service` tags become resource attributes,
+//     and other tags become data point attributes.
+//
+// A PmetricConsumer is not safe for concurrent use.
+type PmetricConsumer struct {
+	md        pmetric.Metrics
+	resources map[string]pmetric.MetricSlice
+	metrics   map[string]pmetric.Metric
+}
+
+// NewPmetricConsumer creates a new PmetricConsumer.
+func NewPmetricConsumer() *PmetricConsumer {
+	c := &PmetricConsumer{}
+	c.reset()
+	return c
+}
+
+func (c *PmetricConsumer) reset() {
+	c.md = pmetric.NewMetrics()
+	c.resources = make(map[string]pmetric.MetricSlice)
+	c.metrics = make(map[string]pmetric.Metric)
+}
+
+// Metrics returns the OTLP metrics built from the metrics consumed since the last call.
+func (c *PmetricConsumer) Metrics() pmetric.Metrics {
+	md := c.md
+	c.reset()
+	return md
+}
+
+// splitTags splits the dimensions into resource attributes and data point attributes.
+func splitTags(dims *Dimensions) (resAttrs map[string]string, attrs map[string]string) {
+	resAttrs = make(map[string]string, len(resourceTags))
+	attrs = make(map[string]string, len(dims.tags))
+	for _, tag := range dims.tags {
+		key, value, _ := strings.Cut(tag, ":")
+		if attr, ok := resourceTags[key]; ok {
+			resAttrs[attr] = value
+		} else {
+			attrs[key] = value
+		}
+	}
+	if dims.host != "" {
+		resAttrs[resourceTags["host"]] = dims.host
+	}
+	return resAttrs, attrs
+}
+
+// metricSlice returns the metrics of the resource with the given attributes.
+func (c *PmetricConsumer) metricSlice(resAttrs map[string]string) (string, pmetric.MetricSlice) {
+	var b strings.Builder
+	for _, key := range []string{"host", "env", "service"} {
+		b.WriteString(resAttrs[resourceTags[key]])
+		b.WriteByte(0)
+	}
+	resKey := b.String()
+
+	ms, ok := c.resources[resKey]
+	if !ok {
+		rm := c.md.ResourceMetrics().AppendEmpty()
+		putAttributes(rm.Resource().Attributes(), resAttrs)
+		ms = rm.ScopeMetrics().AppendEmpty().Metrics()
+		c.resources[resKey] = ms
+	}
+	return resKey, ms
+}
+
+// metric returns the metric with the given name and type for the given dimensions,
+// and the attributes of its data points.
+func (c *PmetricConsumer) metric(dims *Dimensions, typ string, init func(pmetric.Metric)) (pmetric.Metric, map[string]string) {
+	resAttrs, attrs := splitTags(dims)
+	resKey, ms := c.metricSlice(resAttrs)
+
+	key := resKey + typ + "\x00" + dims.name
+	m, ok := c.metrics[key]
+	if !ok {
+		m = ms.AppendEmpty()
+		m.SetName(dims.name)
+		init(m)
+		c.metrics[key] = m
+	}
+	return m, attrs
+}
+
+func setTimestamps(startTs func(pcommon.Timestamp), ts func(pcommon.Timestamp), timestamp uint64, interval int64) {
+	ts(pcommon.Timestamp(timestamp))
+	if interval > 0 {
+		startTs(pcommon.Timestamp(timestamp - uint64(interval)*1e9))
+	}
+}
+
+// putAttributes sets the given attributes in dest, in key order.
+func putAttributes(dest pcommon.Map, attrs map[string]string) {
+	dest.EnsureCapacity(len(attrs))
+	for _, key := range slices.Sorted(maps.Keys(attrs)) {
+		dest.PutStr(key, attrs[key])
+	}
+}
+
+// ConsumeTimeSeries implements the TimeSeriesConsumer interface.
+func (c *PmetricConsumer) ConsumeTimeSeries(_ context.Context, dimensions *Dimensions, typ DataType, timestamp uint64, interval int64, value float64) {
+	var dps pmetric.NumberDataPointSlice
+	var attrs map[string]string
+	switch typ {
+	case Gauge:
+		var m pmetric.Metric
+		m, attrs = c.metric(dimensions, "gauge", func(m pmetric.Metric) {
+			m.SetEmptyGauge()
+		})
+		dps = m.Gauge().DataPoints()
+	case Count:
+		var m pmetric.Metric
+		m, attrs = c.metric(dimensions, "count", func(m pmetric.Metric) {
+			sum := m.SetEmptySum()
+			sum.SetAggregationTemporality(pmetric.AggregationTemporalityDelta)
+			sum.SetIsMonotonic(true)
+		})
+		dps = m.Sum().DataPoints()
+	default:
+		return
+	}
+
+	dp := dps.AppendEmpty()
+	setTimestamps(dp.SetStartTimestamp, dp.SetTimestamp, timestamp, interval)
+	dp.SetDoubleValue(value)
+	putAttributes(dp.Attributes(), attrs)
+}
+
+// sketchKeyToBucketIndex returns the index of the exponential histogram bucket holding
+// the values of the sketch bin with the given (non-zero) key.
+func sketchKeyToBucketIndex(k int32) int32 {
+	if k < 0 {
+		k = -k
+	}
+	// The sketch bin of key k holds values around sketchGamma^(k-sketchBias).
+	// The exponential histogram bucket of index i holds values in (base^i, base^(i+1)].
+	log2 := float64(int(k)-sketchBias) * math.Log2(sketchGamma)
+	return int32(math.Ceil(math.Ldexp(log2, exponentialHistogramScale))) - 1
+}
+
+// fillBuckets sets the counts of the given bins in the exponential histogram buckets.
+func fillBuckets(buckets pmetric.ExponentialHistogramDataPointBuckets, keys []int32, counts []uint32) {
+	if len(keys) == 0 {
+		return
+	}
+	indexes := make([]int32, len(keys))
+	minIndex, maxIndex := int32(math.MaxInt32), int32(math.MinInt32)
+	for i, k := range keys {
+		indexes[i] = sketchKeyToBucketIndex(k)
+		minIndex = min(minIndex, indexes[i])
+		maxIndex = max(maxIndex, indexes[i])
+	}
+
+	bucketCounts := make([]uint64, maxIndex-minIndex+1)
+	for i, index := range indexes {
+		bucketCounts[index-minIndex] += uint64(counts[i])
+	}
+	buckets.SetOffset(minIndex)
+	buckets.BucketCounts().FromRaw(bucketCounts)
+}
+
+// ConsumeSketch implements the SketchConsumer interface.
+func (c *PmetricConsumer) ConsumeSketch(_ context.Context, dimensions *Dimensions, timestamp uint64, interval int64, sketch *quantile.Sketch) {
+	m, attrs := c.metric(dimensions, "sketch", func(m pmetric.Metric) {
+		m.SetEmptyExponentialHistogram().SetAggregationTemporality(pmetric.AggregationTemporalityDelta)
+	})
+
+	dp := m.ExponentialHistogram().DataPoints().AppendEmpty()
+	setTimestamps(dp.SetStartTimestamp, dp.SetTimestamp, timestamp, interval)
+	putAttributes(dp.Attributes(), attrs)
+	dp.SetScale(exponentialHistogramScale)
+	dp.SetCount(uint64(sketch.Basic.Cnt))
+	dp.SetSum(sketch.Basic.Sum)
+	if sketch.Basic.Cnt > 0 {
+		dp.SetMin(sketch.Basic.Min)
+		dp.SetMax(sketch.Basic.Max)
+	}
+
+	var posKeys, negKeys []int32
+	var posCounts, negCounts []uint32
+	keys, counts := sketch.Cols()
+	for i, k := range keys {
+		switch {
+		case k > 0:
+			posKeys = append(posKeys, k)
+			posCounts = append(posCounts, counts[i])
+		case k < 0:
+			negKeys = append(negKeys, k)
+			negCounts = append(negCounts, counts[i])
+		default:
+			dp.SetZeroCount(dp.ZeroCount() + uint64(counts[i]))
+		}
+	}
+	fillBuckets(dp.Positive(), posKeys, posCounts)
+	fillBuckets(dp.Negative(), negKeys, negCounts)
+}
@@ -0,0 +1,167 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2023-present Datadog, Inc.
+
+package metrics
+
+import (
+	"context"
+	"math"
+	"slices"
+	"testing"
+
+	"github.com/DataDog/datadog-agent/pkg/util/quantile"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+)
+
+func TestPmetricConsumerTimeSeries(t *testing.T) {
+	ctx := context.Background()
+	c := NewPmetricConsumer()
+	dims := &Dimensions{name: "requests", host: "host1", tags: []string{"env:prod", "service:api", "code:200"}}
+	c.ConsumeTimeSeries(ctx, dims, Count, uint64(seconds(20)), 10, 3)
+	c.ConsumeTimeSeries(ctx, dims.AddTags(), Count, uint64(seconds(30)), 0, 4)
+	c.ConsumeTimeSeries(ctx, &Dimensions{name: "load", tags: []string{"host:host2"}}, Gauge, uint64(seconds(30)), 0, 0.5)
+
+	md := c.Metrics()
+	require.Equal(t, 2, md.ResourceMetrics().Len())
+
+	rm := md.ResourceMetrics().At(0)
+	assert.Equal(t, map[string]any{
+		"host.name":                   "host1",
+		"deployment.environment.name": "prod",
+		"service.name":                "api",
+	}, rm.Resource().Attributes().AsRaw())
+	require.Equal(t, 1, rm.ScopeMetrics().At(0).Metrics().Len())
+	m := rm.ScopeMetrics().At(0).Metrics().At(0)
+	assert.Equal(t, "requests", m.Name())
+	require.Equal(t, pmetric.MetricTypeSum, m.Type())
+	assert.Equal(t, pmetric.AggregationTemporalityDelta, m.Sum().AggregationTemporality())
+	assert.True(t, m.Sum().IsMonotonic())
+	require.Equal(t, 2, m.Sum().DataPoints().Len())
+	dp := m.Sum().DataPoints().At(0)
+	assert.Equal(t, seconds(10), dp.StartTimestamp())
+	assert.Equal(t, seconds(20), dp.Timestamp())
+	assert.Equal(t, 3.0, dp.DoubleValue())
+	assert.Equal(t, map[string]any{"code": "200"}, dp.Attributes().AsRaw())
+	assert.Equal(t, pcommon.Timestamp(0), m.Sum().DataPoints().At(1).StartTimestamp())
+
+	rm = md.ResourceMetrics().At(1)
+	assert.Equal(t, map[string]any{"host.name": "host2"}, rm.Resource().Attributes().AsRaw())
+	m = rm.ScopeMetrics().At(0).Metrics().At(0)
+	assert.Equal(t, "load", m.Name())
+	require.Equal(t, pmetric.MetricTypeGauge, m.Type())
+	assert.Equal(t, 0.5, m.Gauge().DataPoints().At(0).DoubleValue())
+
+	assert.Zero(t, c.Metrics().ResourceMetrics().Len(), "metrics are reset")
+}
+
+func TestPmetricConsumerSketch(t *testing.T) {
+	values := []float64{-3, 0, 0.001, 5, 5, 1200}
+	agent := quantile.Agent{}
+	for _, v := range values {
+		agent.Insert(v, 1)
+	}
+	sketch := agent.Finish()
+
+	c := NewPmetricConsumer()
+	c.ConsumeSketch(context.Background(), &Dimensions{name: "latency"}, uint64(seconds(20)), 10, sketch)
+	md := c.Metrics()
+
+	m := md.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics().At(0)
+	require.Equal(t, pmetric.MetricTypeExponentialHistogram, m.Type())
+	assert.Equal(t, pmetric.AggregationTemporalityDelta, m.ExponentialHistogram().AggregationTemporality())
+	dp := m.ExponentialHistogram().DataPoints().At(0)
+	assert.Equal(t, seconds(10), dp.StartTimestamp())
+	assert.Equal(t, uint64(len(values)), dp.Count())
+	assert.InDelta(t, 1207.001, dp.Sum(), 1e-9)
+	assert.Equal(t, -3.0, dp.Min())
+	assert.Equal(t, 1200.0, dp.Max())
+	assert.Equal(t, uint64(1), dp.ZeroCount())
+
+	// Every value is in the bucket its sketch bin was mapped to, up to the sketch relative accuracy.
+	base := math.Pow(2, math.Pow(2, -float64(dp.Scale())))
+	bucketContains := func(buckets pmetric.ExponentialHistogramDataPointBuckets, v float64, count uint64) {
+		for i, n := range buckets.BucketCounts().AsRaw() {
+			index := float64(int(buckets.Offset()) + i)
+			lower, upper := math.Pow(base, index), math.Pow(base, index+1)
+			if v >= lower*(1-1.0/128) && v <= upper*(1+1.0/128) && n == count {
+				return
+			}
+		}
+		assert.Fail(t, "value not found in buckets", "value %v, buckets %v", v, buckets.BucketCounts().AsRaw())
+	}
+	bucketContains(dp.Negative(), 3, 1)
+	bucketContains(dp.Positive(), 0.001, 1)
+	bucketContains(dp.Positive(), 5, 2)
+	bucketContains(dp.Positive(), 1200, 1)
+}
+
+func TestPmetricConsumerRoundTrip(t *testing.T) {
+	md := pmetric.NewMetrics()
+	rm := md.ResourceMetrics().AppendEmpty()
+	rm.Resource().Attributes().PutStr("host.name", "host1")
+	rm.Resource().Attributes().PutStr("service.name", "api")
+	rm.Resource().Attributes().PutStr("deployment.environment.name", "prod")
+	ms := rm.ScopeMetrics().AppendEmpty().Metrics()
+
+	gauge := ms.AppendEmpty()
+	gauge.SetName("gauge")
+	dp := gauge.SetEmptyGauge().DataPoints().AppendEmpty()
+	dp.SetTimestamp(seconds(10))
+	dp.SetDoubleValue(2)
+	dp.Attributes().PutStr("key", "value")
+
+	sum := ms.AppendEmpty()
+	sum.SetName("delta.sum")
+	sum.SetEmptySum().SetAggregationTemporality(pmetric.AggregationTemporalityDelta)
+	dp = sum.Sum().DataPoints().AppendEmpty()
+	dp.SetTimestamp(seconds(10))
+	dp.SetIntValue(7)
+
+	hist := ms.AppendEmpty()
+	hist.SetName("exp.hist")
+	hist.SetEmptyExponentialHistogram().SetAggregationTemporality(pmetric.AggregationTemporalityDelta)
+	hdp := hist.ExponentialHistogram().DataPoints().AppendEmpty()
+	hdp.SetTimestamp(seconds(10))
+	hdp.SetScale(4)
+	hdp.SetCount(30)
+	hdp.SetSum(1000)
+	hdp.SetMin(1)
+	hdp.SetMax(100)
+	hdp.SetZeroCount(0)
+	hdp.Positive().SetOffset(2)
+	hdp.Positive().BucketCounts().FromRaw([]uint64{10, 0, 0, 20})
+
+	translator := NewTestTranslator(t)
+	first := &mockFullConsumer{}
+	_, err := translator.MapMetrics(context.Background(), md, first, nil)
+	require.NoError(t, err)
+
+	reverse := NewPmetricConsumer()
+	_, err = translator.MapMetrics(context.Background(), md, reverse, nil)
+	require.NoError(t, err)
+
+	second := &mockFullConsumer{}
+	_, err = translator.MapMetrics(context.Background(), reverse.Metrics(), second, nil)
+	require.NoError(t, err)
+
+	// Tags may be in a different order.
+	for _, m := range append(first.metrics, second.metrics...) {
+		slices.Sort(m.tags)
+	}
+	assert.ElementsMatch(t, first.metrics, second.metrics)
+	require.Len(t, second.sketches, 1)
+	assert.Equal(t, first.sketches[0].basic, second.sketches[0].basic)
+	assert.Equal(t, first.sketches[0].host, second.sketches[0].host)
+	assert.ElementsMatch(t, first.sketches[0].tags, second.sketches[0].tags)
+}
+
+func TestSketchParameters(t *testing.T) {
+	// The default configuration has a relative accuracy of 1/128 and a minimum value of 1e-9.
+	assert.InDelta(t, 1+2.0/128, sketchGamma, 1e-12)
+	assert.Equal(t, 1-int(math.Floor(math.Log(1e-9)/math.Log1p(2.0/128))), sketchBias)
+}
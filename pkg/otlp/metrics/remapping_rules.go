@@ -0,0 +1,207 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2023-present Datadog, Inc.
+
+package metrics
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"gopkg.in/yaml.v3"
+)
+
+// RemappingRule describes how to compute a Datadog metric from an OpenTelemetry metric.
+// The OpenTelemetry metric is left untouched: a copy of it is added to the metrics being translated.
+//
+// Remapping rules apply to Gauge, Sum, Histogram and ExponentialHistogram metrics.
+type RemappingRule struct {
+	// Match is the name of the OpenTelemetry metric this rule applies to.
+	Match string `json:"match" yaml:"match"`
+	// Name is the name of the new metric.
+	Name string `json:"name" yaml:"name"`
+	// Divisor is the number all values of the new metric are divided by.
+	// Zero or one leave the values unchanged. It only applies to Gauge and Sum metrics.
+	Divisor float64 `json:"divisor,omitempty" yaml:"divisor,omitempty"`
+	// Unit is the unit of the new metric. If empty, the unit of the original metric is kept.
+	Unit string `json:"unit,omitempty" yaml:"unit,omitempty"`
+	// Filters restricts the data points copied over to the ones having *either* of the given
+	// string attributes. If empty, all data points are copied over.
+	Filters []AttributeFilter `json:"filters,omitempty" yaml:"filters,omitempty"`
+	// RequiredAttributes restricts the data points copied over to the ones having *all* of the given
+	// string attributes, each with any of its values. It applies in addition to Filters.
+	RequiredAttributes []AttributeValues `json:"required_attributes,omitempty" yaml:"required_attributes,omitempty"`
+	// FixedAttributes are attributes set on all data points of the new metric.
+	FixedAttributes map[string]string `json:"fixed_attributes,omitempty" yaml:"fixed_attributes,omitempty"`
+	// AttributesMapping maps data point attribute names to the name they should be copied to
+	// on the new metric (e.g. `group` to `consumer_group`).
+	AttributesMapping map[string]string `json:"attributes_mapping,omitempty" yaml:"attributes_mapping,omitempty"`
+	// DroppedAttributes are data point attributes removed from the new metric, after AttributesMapping
+	// is applied.
+	DroppedAttributes []string `json:"dropped_attributes,omitempty" yaml:"dropped_attributes,omitempty"`
+	// FirstHistogramPointOnly restricts the data points copied over from Histogram metrics to the first one
+	// selected by Filters and RequiredAttributes. The default Datadog runtime metric rules selecting data points
+	// by attribute set it.
+	FirstHistogramPointOnly bool `json:"first_histogram_point_only,omitempty" yaml:"first_histogram_point_only,omitempty"`
+	// Disable removes the default rules with the same Match and Name instead of adding a rule.
+	Disable bool `json:"disable,omitempty" yaml:"disable,omitempty"`
+}
+
+// AttributeFilter is a string attribute key/value pair used to select data points in a RemappingRule.
+type AttributeFilter struct {
+	Key   string `json:"key" yaml:"key"`
+	Value string `json:"value" yaml:"value"`
+}
+
+// AttributeValues is a string attribute key and its accepted values, used to select data points in a RemappingRule.
+type AttributeValues struct {
+	Key    string   `json:"key" yaml:"key"`
+	Values []string `json:"values" yaml:"values"`
+}
+
+// Validate checks that the rule is well-formed.
+func (r RemappingRule) Validate() error {
+	if r.Match == "" {
+		return errors.New("remapping rule must have a metric to match")
+	}
+	if r.Name == "" {
+		return fmt.Errorf("remapping rule for %q must have a new metric name", r.Match)
+	}
+	if r.Divisor < 0 {
+		return fmt.Errorf("remapping rule for %q must have a non-negative divisor: %v", r.Match, r.Divisor)
+	}
+	return nil
+}
+
+// clone returns a deep copy of the rule.
+func (r RemappingRule) clone() RemappingRule {
+	r.Filters = append([]AttributeFilter(nil), r.Filters...)
+	r.RequiredAttributes = append([]AttributeValues(nil), r.RequiredAttributes...)
+	for i := range r.RequiredAttributes {
+		r.RequiredAttributes[i].Values = append([]string(nil), r.RequiredAttributes[i].Values...)
+	}
+	r.DroppedAttributes = append([]string(nil), r.DroppedAttributes...)
+	r.FixedAttributes = maps.Clone(r.FixedAttributes)
+	r.AttributesMapping = maps.Clone(r.AttributesMapping)
+	return r
+}
+
+// LoadRemappingRules reads a list of remapping rules in YAML format from r.
+// Since YAML is a superset of JSON, a JSON array of rules is accepted as well.
+func LoadRemappingRules(r io.Reader) ([]RemappingRule, error) {
+	var rules []RemappingRule
+	dec := yaml.NewDecoder(r)
+	dec.KnownFields(true)
+	if err := dec.Decode(&rules); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("failed to decode remapping rules: %w", err)
+	}
+	for _, rule := range rules {
+		if err := rule.Validate(); err != nil {
+			return nil, err
+		}
+	}
+	return rules, nil
+}
+
+// DefaultRemappingRules returns the rules used for computing Datadog metrics from their
+// OpenTelemetry counterparts: Datadog runtime metrics, which are always computed, and Datadog system,
+// container, Kafka and JVM metrics, which are computed when using WithRemapping.
+//
+// A custom rule set with WithRemappingRules replaces the default rules with the same Match and Name,
+// or disables them if its Disable field is set.
+func DefaultRemappingRules() []RemappingRule {
+	return cloneRemappingRules(slices.Concat(runtimeRemappingRules, collectorRemappingRules))
+}
+
+// collectorRemappingRules are the default rules applied when using WithRemapping, in addition to
+// runtimeRemappingRules.
+var collectorRemappingRules = slices.Concat(
+	systemRemappingRules,
+	containerRemappingRules,
+	kafkaRemappingRules,
+	jvmRemappingRules,
+)
+
+func cloneRemappingRules(rules []RemappingRule) []RemappingRule {
+	cloned := make([]RemappingRule, 0, len(rules))
+	for _, rule := range rules {
+		cloned = append(cloned, rule.clone())
+	}
+	return cloned
+}
+
+// mergeRemappingRules returns the default rules, without the ones having the same Match and Name as
+// a custom rule, followed by the custom rules which are not disabled.
+func mergeRemappingRules(defaults, custom []RemappingRule) []RemappingRule {
+	type ruleKey struct{ match, name string }
+	overridden := make(map[ruleKey]struct{}, len(custom))
+	for _, rule := range custom {
+		overridden[ruleKey{rule.Match, rule.Name}] = struct{}{}
+	}
+
+	rules := make([]RemappingRule, 0, len(defaults)+len(custom))
+	for _, rule := range defaults {
+		if _, ok := overridden[ruleKey{rule.Match, rule.Name}]; !ok {
+			rules = append(rules, rule)
+		}
+	}
+	for _, rule := range custom {
+		if !rule.Disable {
+			rules = append(rules, rule)
+		}
+	}
+	return rules
+}
+
+// remappingRule is the internal representation of a RemappingRule.
+type remappingRule struct {
+	name     string
+	div      float64
+	unit     string
+	mapping  attributesMapping
+	filter   []kv
+	required []AttributeValues
+	// firstHistogramPoint restricts the data points of Histogram metrics to the first selected one.
+	firstHistogramPoint bool
+}
+
+// remapper computes new metrics from a set of remapping rules, indexed by the name of the metric they match.
+type remapper map[string][]remappingRule
+
+func newRemapper(rules []RemappingRule) remapper {
+	r := make(remapper, len(rules))
+	for _, rule := range rules {
+		filter := make([]kv, 0, len(rule.Filters))
+		for _, f := range rule.Filters {
+			filter = append(filter, kv{f.Key, f.Value})
+		}
+		r[rule.Match] = append(r[rule.Match], remappingRule{
+			name: rule.Name,
+			div:  rule.Divisor,
+			unit: rule.Unit,
+			mapping: attributesMapping{
+				fixed:   rule.FixedAttributes,
+				dynamic: rule.AttributesMapping,
+				dropped: rule.DroppedAttributes,
+			},
+			filter:              filter,
+			required:            rule.RequiredAttributes,
+			firstHistogramPoint: rule.FirstHistogramPointOnly,
+		})
+	}
+	return r
+}
+
+// remap computes the metrics of all the rules matching m and appends them to all.
+func (r remapper) remap(all pmetric.MetricSlice, m pmetric.Metric) {
+	for _, rule := range r[m.Name()] {
+		if addm, ok := copyMetricWithAttr(all, m, rule); ok && rule.unit != "" {
+			addm.SetUnit(rule.unit)
+		}
+	}
+}
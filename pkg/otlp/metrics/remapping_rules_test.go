@@ -0,0 +1,337 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2023-present Datadog, Inc.
+
+package metrics
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/pdatatest/pmetrictest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+)
+
+func TestLoadRemappingRules(t *testing.T) {
+	expected := []RemappingRule{
+		{
+			Match:   "acme.queue.usage",
+			Name:    "acme.queue.used_mb",
+			Divisor: divMebibytes,
+			Unit:    "mebibyte",
+			Filters: []AttributeFilter{
+				{Key: "state", Value: "used"},
+			},
+			FixedAttributes:   map[string]string{"type": "queue"},
+			AttributesMapping: map[string]string{"queue.name": "queue"},
+		},
+		{
+			Match: "acme.queue.depth",
+			Name:  "acme.queue.size",
+			RequiredAttributes: []AttributeValues{
+				{Key: "state", Values: []string{"ready", "delayed"}},
+			},
+			DroppedAttributes: []string{"state"},
+		},
+		{
+			Match:   "process.runtime.go.goroutines",
+			Name:    "runtime.go.num_goroutine",
+			Disable: true,
+		},
+	}
+
+	tests := []struct {
+		name     string
+		document string
+		expected []RemappingRule
+		err      string
+	}{
+		{
+			name: "yaml",
+			document: `
+- match: acme.queue.usage
+  name: acme.queue.used_mb
+  divisor: 1048576
+  unit: mebibyte
+  filters:
+    - key: state
+      value: used
+  fixed_attributes:
+    type: queue
+  attributes_mapping:
+    queue.name: queue
+- match: acme.queue.depth
+  name: acme.queue.size
+  required_attributes:
+    - key: state
+      values: [ready, delayed]
+  dropped_attributes: [state]
+- match: process.runtime.go.goroutines
+  name: runtime.go.num_goroutine
+  disable: true
+`,
+			expected: expected,
+		},
+		{
+			name: "json",
+			document: `[
+  {
+    "match": "acme.queue.usage",
+    "name": "acme.queue.used_mb",
+    "divisor": 1048576,
+    "unit": "mebibyte",
+    "filters": [{"key": "state", "value": "used"}],
+    "fixed_attributes": {"type": "queue"},
+    "attributes_mapping": {"queue.name": "queue"}
+  },
+  {
+    "match": "acme.queue.depth",
+    "name": "acme.queue.size",
+    "required_attributes": [{"key": "state", "values": ["ready", "delayed"]}],
+    "dropped_attributes": ["state"]
+  },
+  {"match": "process.runtime.go.goroutines", "name": "runtime.go.num_goroutine", "disable": true}
+]`,
+			expected: expected,
+		},
+		{
+			name:     "empty",
+			document: "",
+		},
+		{
+			name:     "unknown field",
+			document: `[{"match": "acme.queue.depth", "name": "acme.queue.size", "scale": 2}]`,
+			err:      "failed to decode remapping rules",
+		},
+		{
+			name:     "missing name",
+			document: `[{"match": "acme.queue.depth"}]`,
+			err:      `remapping rule for "acme.queue.depth" must have a new metric name`,
+		},
+		{
+			name:     "negative divisor",
+			document: `[{"match": "acme.queue.depth", "name": "acme.queue.size", "divisor": -1}]`,
+			err:      `remapping rule for "acme.queue.depth" must have a non-negative divisor: -1`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rules, err := LoadRemappingRules(strings.NewReader(tt.document))
+			if tt.err != "" {
+				assert.ErrorContains(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, rules)
+		})
+	}
+}
+
+func TestDefaultRemappingRules(t *testing.T) {
+	rules := DefaultRemappingRules()
+	require.NotEmpty(t, rules)
+	for _, rule := range rules {
+		assert.NoError(t, rule.Validate())
+	}
+
+	// Modifying the returned rules must not modify the default remapping.
+	for i := range rules {
+		if rules[i].FixedAttributes != nil {
+			rules[i].FixedAttributes["type"] = "modified"
+		}
+	}
+	for _, rule := range kafkaRemappingRules {
+		assert.NotEqual(t, "modified", rule.FixedAttributes["type"])
+	}
+
+	// Runtime rules are part of the default rules.
+	assert.Contains(t, rules, RemappingRule{Match: "process.runtime.go.goroutines", Name: "runtime.go.num_goroutine"})
+}
+
+func TestWithRemappingRules(t *testing.T) {
+	md := pmetric.NewMetrics()
+	ms := md.ResourceMetrics().AppendEmpty().ScopeMetrics().AppendEmpty().Metrics()
+	testMetric("acme.queue.usage",
+		testPoint{f: 2 * divMebibytes, attrs: map[string]any{"state": "used", "queue.name": "orders"}},
+		testPoint{f: 3 * divMebibytes, attrs: map[string]any{"state": "free", "queue.name": "orders"}},
+	).CopyTo(ms.AppendEmpty())
+	testMetric("system.cpu.load_average.1m", testPoint{f: 1}).CopyTo(ms.AppendEmpty())
+
+	rule := RemappingRule{
+		Match:             "acme.queue.usage",
+		Name:              "acme.queue.used_mb",
+		Divisor:           divMebibytes,
+		Filters:           []AttributeFilter{{Key: "state", Value: "used"}},
+		FixedAttributes:   map[string]string{"type": "queue"},
+		AttributesMapping: map[string]string{"queue.name": "queue"},
+	}
+
+	t.Run("custom rules only", func(t *testing.T) {
+		translator := NewTestTranslator(t, WithRemappingRules(rule))
+		consumer := &mockFullConsumer{}
+		_, err := translator.MapMetrics(context.Background(), md, consumer, nil)
+		require.NoError(t, err)
+
+		var names []string
+		for _, m := range consumer.metrics {
+			names = append(names, m.name)
+		}
+		assert.ElementsMatch(t, []string{"acme.queue.usage", "acme.queue.usage", "acme.queue.used_mb", "system.cpu.load_average.1m"}, names)
+		for _, m := range consumer.metrics {
+			if m.name == "acme.queue.used_mb" {
+				assert.Equal(t, 2.0, m.value)
+				assert.ElementsMatch(t, []string{"state:used", "queue.name:orders", "queue:orders", "type:queue"}, m.tags)
+			}
+		}
+	})
+
+	t.Run("custom and default rules", func(t *testing.T) {
+		translator := NewTestTranslator(t, WithRemapping(), WithRemappingRules(rule))
+		consumer := &mockFullConsumer{}
+		_, err := translator.MapMetrics(context.Background(), md, consumer, nil)
+		require.NoError(t, err)
+
+		var names []string
+		for _, m := range consumer.metrics {
+			names = append(names, m.name)
+		}
+		assert.ElementsMatch(t, []string{"acme.queue.usage", "acme.queue.usage", "acme.queue.used_mb", "otel.system.cpu.load_average.1m", "system.load.1"}, names)
+	})
+
+	t.Run("override default rule", func(t *testing.T) {
+		override := RemappingRule{
+			Match:           "system.cpu.load_average.1m",
+			Name:            "system.load.1",
+			FixedAttributes: map[string]string{"source": "otel"},
+		}
+		// The metrics mapped with WithRemapping are renamed, use new ones.
+		md := pmetric.NewMetrics()
+		testMetric("system.cpu.load_average.1m", testPoint{f: 1}).CopyTo(md.ResourceMetrics().AppendEmpty().ScopeMetrics().AppendEmpty().Metrics().AppendEmpty())
+		translator := NewTestTranslator(t, WithRemapping(), WithRemappingRules(override))
+		consumer := &mockFullConsumer{}
+		_, err := translator.MapMetrics(context.Background(), md, consumer, nil)
+		require.NoError(t, err)
+
+		var loads []metric
+		for _, m := range consumer.metrics {
+			if m.name == "system.load.1" {
+				loads = append(loads, m)
+			}
+		}
+		require.Len(t, loads, 1, "the custom rule replaces the default rule")
+		assert.Equal(t, []string{"source:otel"}, loads[0].tags)
+	})
+
+	t.Run("invalid rule", func(t *testing.T) {
+		_, err := NewTranslator(componenttest.NewNopTelemetrySettings(), nil, WithRemappingRules(RemappingRule{Name: "acme.queue.size"}))
+		assert.EqualError(t, err, "remapping rule must have a metric to match")
+	})
+}
+
+func TestRemapperUnit(t *testing.T) {
+	dest := pmetric.NewMetricSlice()
+	r := newRemapper([]RemappingRule{{Match: "container.cpu.usage.total", Name: "container.cpu.usage", Unit: "nanocore"}})
+	r.remap(dest, testMetric("container.cpu.usage.total", testPoint{i: 5}))
+	require.Equal(t, 1, dest.Len())
+
+	expected := testMetric("container.cpu.usage", testPoint{i: 5})
+	expected.SetUnit("nanocore")
+	assert.NoError(t, pmetrictest.CompareMetric(expected, dest.At(0)))
+}
+
+func TestWithRemappingRulesRuntimeMetrics(t *testing.T) {
+	md := pmetric.NewMetrics()
+	ms := md.ResourceMetrics().AppendEmpty().ScopeMetrics().AppendEmpty().Metrics()
+	testMetric("process.runtime.go.goroutines", testPoint{i: 10}).CopyTo(ms.AppendEmpty())
+	testMetric("process.runtime.dotnet.gc.heap.size",
+		testPoint{i: 1, attrs: map[string]any{"generation": "gen0"}},
+		testPoint{i: 2, attrs: map[string]any{"generation": "gen1"}},
+	).CopyTo(ms.AppendEmpty())
+
+	tests := []struct {
+		name     string
+		rules    []RemappingRule
+		expected []string
+	}{
+		{
+			name:     "default rules",
+			expected: []string{"runtime.go.num_goroutine", "runtime.dotnet.gc.size.gen0", "runtime.dotnet.gc.size.gen1"},
+		},
+		{
+			name: "disabled rule",
+			rules: []RemappingRule{
+				{Match: "process.runtime.go.goroutines", Name: "runtime.go.num_goroutine", Disable: true},
+			},
+			expected: []string{"runtime.dotnet.gc.size.gen0", "runtime.dotnet.gc.size.gen1"},
+		},
+		{
+			name: "overridden rule",
+			rules: []RemappingRule{{
+				Match:              "process.runtime.dotnet.gc.heap.size",
+				Name:               "runtime.dotnet.gc.size.gen0",
+				RequiredAttributes: []AttributeValues{{Key: "generation", Values: []string{"gen0", "gen1"}}},
+			}},
+			expected: []string{"runtime.go.num_goroutine", "runtime.dotnet.gc.size.gen0", "runtime.dotnet.gc.size.gen0", "runtime.dotnet.gc.size.gen1"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			translator := NewTestTranslator(t, WithRemappingRules(tt.rules...))
+			consumer := &mockFullConsumer{}
+			metadata, err := translator.MapMetrics(context.Background(), md, consumer, nil)
+			require.NoError(t, err)
+
+			var names []string
+			for _, m := range consumer.metrics {
+				if strings.HasPrefix(m.name, "runtime.") {
+					names = append(names, m.name)
+				}
+			}
+			assert.ElementsMatch(t, tt.expected, names)
+			// The language of runtime metrics is reported even if their rules are disabled.
+			assert.ElementsMatch(t, []string{"go", "dotnet"}, metadata.Languages)
+		})
+	}
+}
+
+func TestRemapperFirstHistogramPoint(t *testing.T) {
+	newHistogram := func() pmetric.Metric {
+		m := pmetric.NewMetric()
+		m.SetName("process.runtime.dotnet.gc.heap.size")
+		dps := m.SetEmptyHistogram().DataPoints()
+		for _, count := range []uint64{1, 2} {
+			dp := dps.AppendEmpty()
+			dp.SetCount(count)
+			dp.Attributes().PutStr("generation", "gen0")
+		}
+		return m
+	}
+	countsOf := func(dest pmetric.MetricSlice) []uint64 {
+		require.Equal(t, 1, dest.Len())
+		var counts []uint64
+		for i := 0; i < dest.At(0).Histogram().DataPoints().Len(); i++ {
+			counts = append(counts, dest.At(0).Histogram().DataPoints().At(i).Count())
+		}
+		return counts
+	}
+
+	// The default runtime rules only copy the first matching data point of histograms.
+	dest := pmetric.NewMetricSlice()
+	newRemapper(runtimeRemappingRules).remap(dest, newHistogram())
+	assert.Equal(t, []uint64{1}, countsOf(dest))
+
+	// Other rules copy all of them.
+	dest = pmetric.NewMetricSlice()
+	newRemapper([]RemappingRule{{
+		Match:              "process.runtime.dotnet.gc.heap.size",
+		Name:               "runtime.dotnet.gc.size.gen0",
+		RequiredAttributes: []AttributeValues{{Key: "generation", Values: []string{"gen0"}}},
+	}}).remap(dest, newHistogram())
+	assert.Equal(t, []uint64{1, 2}, countsOf(dest))
+}
@@ -1,5 +1,7 @@
 package metrics
 
+import "slices"
+
 // runtimeMetricPrefixLanguageMap defines the runtime metric prefixes and which languages they map to
 var runtimeMetricPrefixLanguageMap = map[string]string{
 	"process.runtime.go":     "go",
@@ -8,373 +10,425 @@ var runtimeMetricPrefixLanguageMap = map[string]string{
 	"jvm":                    "jvm",
 }
 
-// runtimeMetricMapping defines the fields needed to map OTel runtime metrics to their equivalent
-// Datadog runtime metrics
-type runtimeMetricMapping struct {
-	mappedName string                   // the Datadog runtime metric name
-	attributes []runtimeMetricAttribute // the attribute(s) this metric originates from
+// goRuntimeRemappingRules contains the rules for computing Datadog Go runtime metrics from their
+// OpenTelemetry counterparts.
+var goRuntimeRemappingRules = []RemappingRule{
+	{Match: "process.runtime.go.goroutines", Name: "runtime.go.num_goroutine"},
+	{Match: "process.runtime.go.cgo.calls", Name: "runtime.go.num_cgo_call"},
+	{Match: "process.runtime.go.lookups", Name: "runtime.go.mem_stats.lookups"},
+	{Match: "process.runtime.go.mem.heap_alloc", Name: "runtime.go.mem_stats.heap_alloc"},
+	{Match: "process.runtime.go.mem.heap_sys", Name: "runtime.go.mem_stats.heap_sys"},
+	{Match: "process.runtime.go.mem.heap_idle", Name: "runtime.go.mem_stats.heap_idle"},
+	{Match: "process.runtime.go.mem.heap_inuse", Name: "runtime.go.mem_stats.heap_inuse"},
+	{Match: "process.runtime.go.mem.heap_released", Name: "runtime.go.mem_stats.heap_released"},
+	{Match: "process.runtime.go.mem.heap_objects", Name: "runtime.go.mem_stats.heap_objects"},
+	{Match: "process.runtime.go.gc.pause_total_ns", Name: "runtime.go.mem_stats.pause_total_ns"},
+	{Match: "process.runtime.go.gc.count", Name: "runtime.go.mem_stats.num_gc"},
 }
 
-// runtimeMetricAttribute defines the structure for an attribute in regard to mapping runtime metrics.
-// The presence of a runtimeMetricAttribute means that a metric must be mapped from a data point
-// with the given attribute(s).
-type runtimeMetricAttribute struct {
-	key    string   // the attribute name
-	values []string // the attribute value, or multiple values if there is more than one value for the same mapping
+// dotnetRuntimeRemappingRules contains the rules for computing Datadog .NET runtime metrics from their
+// OpenTelemetry counterparts.
+var dotnetRuntimeRemappingRules = []RemappingRule{
+	{Match: "process.runtime.dotnet.monitor.lock_contention.count", Name: "runtime.dotnet.threads.contention_count"},
+	{Match: "process.runtime.dotnet.exceptions.count", Name: "runtime.dotnet.exceptions.count"},
+	{
+		Match: "process.runtime.dotnet.gc.heap.size",
+		Name:  "runtime.dotnet.gc.size.gen0",
+		RequiredAttributes: []AttributeValues{
+			{Key: "generation", Values: []string{"gen0"}},
+		},
+		DroppedAttributes: []string{"generation"},
+	},
+	{
+		Match: "process.runtime.dotnet.gc.heap.size",
+		Name:  "runtime.dotnet.gc.size.gen1",
+		RequiredAttributes: []AttributeValues{
+			{Key: "generation", Values: []string{"gen1"}},
+		},
+		DroppedAttributes: []string{"generation"},
+	},
+	{
+		Match: "process.runtime.dotnet.gc.heap.size",
+		Name:  "runtime.dotnet.gc.size.gen2",
+		RequiredAttributes: []AttributeValues{
+			{Key: "generation", Values: []string{"gen2"}},
+		},
+		DroppedAttributes: []string{"generation"},
+	},
+	{
+		Match: "process.runtime.dotnet.gc.heap.size",
+		Name:  "runtime.dotnet.gc.size.loh",
+		RequiredAttributes: []AttributeValues{
+			{Key: "generation", Values: []string{"loh"}},
+		},
+		DroppedAttributes: []string{"generation"},
+	},
+	{
+		Match: "process.runtime.dotnet.gc.collections.count",
+		Name:  "runtime.dotnet.gc.count.gen0",
+		RequiredAttributes: []AttributeValues{
+			{Key: "generation", Values: []string{"gen0"}},
+		},
+		DroppedAttributes: []string{"generation"},
+	},
+	{
+		Match: "process.runtime.dotnet.gc.collections.count",
+		Name:  "runtime.dotnet.gc.count.gen1",
+		RequiredAttributes: []AttributeValues{
+			{Key: "generation", Values: []string{"gen1"}},
+		},
+		DroppedAttributes: []string{"generation"},
+	},
+	{
+		Match: "process.runtime.dotnet.gc.collections.count",
+		Name:  "runtime.dotnet.gc.count.gen2",
+		RequiredAttributes: []AttributeValues{
+			{Key: "generation", Values: []string{"gen2"}},
+		},
+		DroppedAttributes: []string{"generation"},
+	},
 }
 
-// runtimeMetricMappingList defines the structure for a list of runtime metric mappings where the key
-// represents the OTel metric name and the runtimeMetricMapping contains the Datadog metric name
-type runtimeMetricMappingList map[string][]runtimeMetricMapping
-
-var goRuntimeMetricsMappings = runtimeMetricMappingList{
-	"process.runtime.go.goroutines":        {{mappedName: "runtime.go.num_goroutine"}},
-	"process.runtime.go.cgo.calls":         {{mappedName: "runtime.go.num_cgo_call"}},
-	"process.runtime.go.lookups":           {{mappedName: "runtime.go.mem_stats.lookups"}},
-	"process.runtime.go.mem.heap_alloc":    {{mappedName: "runtime.go.mem_stats.heap_alloc"}},
-	"process.runtime.go.mem.heap_sys":      {{mappedName: "runtime.go.mem_stats.heap_sys"}},
-	"process.runtime.go.mem.heap_idle":     {{mappedName: "runtime.go.mem_stats.heap_idle"}},
-	"process.runtime.go.mem.heap_inuse":    {{mappedName: "runtime.go.mem_stats.heap_inuse"}},
-	"process.runtime.go.mem.heap_released": {{mappedName: "runtime.go.mem_stats.heap_released"}},
-	"process.runtime.go.mem.heap_objects":  {{mappedName: "runtime.go.mem_stats.heap_objects"}},
-	"process.runtime.go.gc.pause_total_ns": {{mappedName: "runtime.go.mem_stats.pause_total_ns"}},
-	"process.runtime.go.gc.count":          {{mappedName: "runtime.go.mem_stats.num_gc"}},
+// javaRuntimeRemappingRules contains the rules for computing Datadog Java runtime metrics from the
+// `process.runtime.jvm.*` metrics of the experimental OpenTelemetry JVM semantic conventions.
+var javaRuntimeRemappingRules = []RemappingRule{
+	{Match: "process.runtime.jvm.threads.count", Name: "jvm.thread_count"},
+	{Match: "process.runtime.jvm.classes.current_loaded", Name: "jvm.loaded_classes"},
+	{Match: "process.runtime.jvm.system.cpu.utilization", Name: "jvm.cpu_load.system"},
+	{Match: "process.runtime.jvm.cpu.utilization", Name: "jvm.cpu_load.process"},
+	{
+		Match: "process.runtime.jvm.memory.usage",
+		Name:  "jvm.heap_memory",
+		RequiredAttributes: []AttributeValues{
+			{Key: "type", Values: []string{"heap"}},
+		},
+		DroppedAttributes: []string{"type"},
+	},
+	{
+		Match: "process.runtime.jvm.memory.usage",
+		Name:  "jvm.non_heap_memory",
+		RequiredAttributes: []AttributeValues{
+			{Key: "type", Values: []string{"non_heap"}},
+		},
+		DroppedAttributes: []string{"type"},
+	},
+	{
+		Match: "process.runtime.jvm.memory.usage",
+		Name:  "jvm.gc.old_gen_size",
+		RequiredAttributes: []AttributeValues{
+			{Key: "pool", Values: []string{"G1 Old Gen", "Tenured Gen", "PS Old Gen"}},
+			{Key: "type", Values: []string{"heap"}},
+		},
+		DroppedAttributes: []string{"pool", "type"},
+	},
+	{
+		Match: "process.runtime.jvm.memory.usage",
+		Name:  "jvm.gc.eden_size",
+		RequiredAttributes: []AttributeValues{
+			{Key: "pool", Values: []string{"G1 Eden Space", "Eden Space", "Par Eden Space", "PS Eden Space"}},
+			{Key: "type", Values: []string{"heap"}},
+		},
+		DroppedAttributes: []string{"pool", "type"},
+	},
+	{
+		Match: "process.runtime.jvm.memory.usage",
+		Name:  "jvm.gc.survivor_size",
+		RequiredAttributes: []AttributeValues{
+			{Key: "pool", Values: []string{"G1 Survivor Space", "Survivor Space", "Par Survivor Space", "PS Survivor Space"}},
+			{Key: "type", Values: []string{"heap"}},
+		},
+		DroppedAttributes: []string{"pool", "type"},
+	},
+	{
+		Match: "process.runtime.jvm.memory.usage",
+		Name:  "jvm.gc.metaspace_size",
+		RequiredAttributes: []AttributeValues{
+			{Key: "pool", Values: []string{"Metaspace"}},
+			{Key: "type", Values: []string{"non_heap"}},
+		},
+		DroppedAttributes: []string{"pool", "type"},
+	},
+	{
+		Match: "process.runtime.jvm.memory.committed",
+		Name:  "jvm.heap_memory_committed",
+		RequiredAttributes: []AttributeValues{
+			{Key: "type", Values: []string{"heap"}},
+		},
+		DroppedAttributes: []string{"type"},
+	},
+	{
+		Match: "process.runtime.jvm.memory.committed",
+		Name:  "jvm.non_heap_memory_committed",
+		RequiredAttributes: []AttributeValues{
+			{Key: "type", Values: []string{"non_heap"}},
+		},
+		DroppedAttributes: []string{"type"},
+	},
+	{
+		Match: "process.runtime.jvm.memory.init",
+		Name:  "jvm.heap_memory_init",
+		RequiredAttributes: []AttributeValues{
+			{Key: "type", Values: []string{"heap"}},
+		},
+		DroppedAttributes: []string{"type"},
+	},
+	{
+		Match: "process.runtime.jvm.memory.init",
+		Name:  "jvm.non_heap_memory_init",
+		RequiredAttributes: []AttributeValues{
+			{Key: "type", Values: []string{"non_heap"}},
+		},
+		DroppedAttributes: []string{"type"},
+	},
+	{
+		Match: "process.runtime.jvm.memory.limit",
+		Name:  "jvm.heap_memory_max",
+		RequiredAttributes: []AttributeValues{
+			{Key: "type", Values: []string{"heap"}},
+		},
+		DroppedAttributes: []string{"type"},
+	},
+	{
+		Match: "process.runtime.jvm.memory.limit",
+		Name:  "jvm.non_heap_memory_max",
+		RequiredAttributes: []AttributeValues{
+			{Key: "type", Values: []string{"non_heap"}},
+		},
+		DroppedAttributes: []string{"type"},
+	},
+	{
+		Match: "process.runtime.jvm.buffer.usage",
+		Name:  "jvm.buffer_pool.direct.used",
+		RequiredAttributes: []AttributeValues{
+			{Key: "pool", Values: []string{"direct"}},
+		},
+		DroppedAttributes: []string{"pool"},
+	},
+	{
+		Match: "process.runtime.jvm.buffer.usage",
+		Name:  "jvm.buffer_pool.mapped.used",
+		RequiredAttributes: []AttributeValues{
+			{Key: "pool", Values: []string{"mapped"}},
+		},
+		DroppedAttributes: []string{"pool"},
+	},
+	{
+		Match: "process.runtime.jvm.buffer.count",
+		Name:  "jvm.buffer_pool.direct.count",
+		RequiredAttributes: []AttributeValues{
+			{Key: "pool", Values: []string{"direct"}},
+		},
+		DroppedAttributes: []string{"pool"},
+	},
+	{
+		Match: "process.runtime.jvm.buffer.count",
+		Name:  "jvm.buffer_pool.mapped.count",
+		RequiredAttributes: []AttributeValues{
+			{Key: "pool", Values: []string{"mapped"}},
+		},
+		DroppedAttributes: []string{"pool"},
+	},
+	{
+		Match: "process.runtime.jvm.buffer.limit",
+		Name:  "jvm.buffer_pool.direct.limit",
+		RequiredAttributes: []AttributeValues{
+			{Key: "pool", Values: []string{"direct"}},
+		},
+		DroppedAttributes: []string{"pool"},
+	},
+	{
+		Match: "process.runtime.jvm.buffer.limit",
+		Name:  "jvm.buffer_pool.mapped.limit",
+		RequiredAttributes: []AttributeValues{
+			{Key: "pool", Values: []string{"mapped"}},
+		},
+		DroppedAttributes: []string{"pool"},
+	},
 }
 
-var dotnetRuntimeMetricsMappings = runtimeMetricMappingList{
-	"process.runtime.dotnet.monitor.lock_contention.count": {{mappedName: "runtime.dotnet.threads.contention_count"}},
-	"process.runtime.dotnet.exceptions.count":              {{mappedName: "runtime.dotnet.exceptions.count"}},
-	"process.runtime.dotnet.gc.heap.size": {{
-		mappedName: "runtime.dotnet.gc.size.gen0",
-		attributes: []runtimeMetricAttribute{{
-			key:    "generation",
-			values: []string{"gen0"},
-		}},
-	}, {
-		mappedName: "runtime.dotnet.gc.size.gen1",
-		attributes: []runtimeMetricAttribute{{
-			key:    "generation",
-			values: []string{"gen1"},
-		}},
-	}, {
-		mappedName: "runtime.dotnet.gc.size.gen2",
-		attributes: []runtimeMetricAttribute{{
-			key:    "generation",
-			values: []string{"gen2"},
-		}},
-	}, {
-		mappedName: "runtime.dotnet.gc.size.loh",
-		attributes: []runtimeMetricAttribute{{
-			key:    "generation",
-			values: []string{"loh"},
-		}},
-	}},
-	"process.runtime.dotnet.gc.collections.count": {{
-		mappedName: "runtime.dotnet.gc.count.gen0",
-		attributes: []runtimeMetricAttribute{{
-			key:    "generation",
-			values: []string{"gen0"},
-		}},
-	}, {
-		mappedName: "runtime.dotnet.gc.count.gen1",
-		attributes: []runtimeMetricAttribute{{
-			key:    "generation",
-			values: []string{"gen1"},
-		}},
-	}, {
-		mappedName: "runtime.dotnet.gc.count.gen2",
-		attributes: []runtimeMetricAttribute{{
-			key:    "generation",
-			values: []string{"gen2"},
-		}},
-	}},
+// stableJavaRuntimeRemappingRules contains the rules for computing Datadog Java runtime metrics from the
+// `jvm.*` metrics of the stable OpenTelemetry JVM semantic conventions.
+var stableJavaRuntimeRemappingRules = []RemappingRule{
+	{Match: "jvm.thread.count", Name: "jvm.thread_count"},
+	{Match: "jvm.class.count", Name: "jvm.loaded_classes"},
+	{Match: "jvm.system.cpu.utilization", Name: "jvm.cpu_load.system"},
+	{Match: "jvm.cpu.recent_utilization", Name: "jvm.cpu_load.process"},
+	{
+		Match: "jvm.memory.used",
+		Name:  "jvm.heap_memory",
+		RequiredAttributes: []AttributeValues{
+			{Key: "jvm.memory.type", Values: []string{"heap"}},
+		},
+		DroppedAttributes: []string{"jvm.memory.type"},
+	},
+	{
+		Match: "jvm.memory.used",
+		Name:  "jvm.non_heap_memory",
+		RequiredAttributes: []AttributeValues{
+			{Key: "jvm.memory.type", Values: []string{"non_heap"}},
+		},
+		DroppedAttributes: []string{"jvm.memory.type"},
+	},
+	{
+		Match: "jvm.memory.used",
+		Name:  "jvm.gc.old_gen_size",
+		RequiredAttributes: []AttributeValues{
+			{Key: "jvm.memory.pool.name", Values: []string{"G1 Old Gen", "Tenured Gen", "PS Old Gen"}},
+			{Key: "jvm.memory.type", Values: []string{"heap"}},
+		},
+		DroppedAttributes: []string{"jvm.memory.pool.name", "jvm.memory.type"},
+	},
+	{
+		Match: "jvm.memory.used",
+		Name:  "jvm.gc.eden_size",
+		RequiredAttributes: []AttributeValues{
+			{Key: "jvm.memory.pool.name", Values: []string{"G1 Eden Space", "Eden Space", "Par Eden Space", "PS Eden Space"}},
+			{Key: "jvm.memory.type", Values: []string{"heap"}},
+		},
+		DroppedAttributes: []string{"jvm.memory.pool.name", "jvm.memory.type"},
+	},
+	{
+		Match: "jvm.memory.used",
+		Name:  "jvm.gc.survivor_size",
+		RequiredAttributes: []AttributeValues{
+			{Key: "jvm.memory.pool.name", Values: []string{"G1 Survivor Space", "Survivor Space", "Par Survivor Space", "PS Survivor Space"}},
+			{Key: "jvm.memory.type", Values: []string{"heap"}},
+		},
+		DroppedAttributes: []string{"jvm.memory.pool.name", "jvm.memory.type"},
+	},
+	{
+		Match: "jvm.memory.used",
+		Name:  "jvm.gc.metaspace_size",
+		RequiredAttributes: []AttributeValues{
+			{Key: "jvm.memory.pool.name", Values: []string{"Metaspace"}},
+			{Key: "jvm.memory.type", Values: []string{"non_heap"}},
+		},
+		DroppedAttributes: []string{"jvm.memory.pool.name", "jvm.memory.type"},
+	},
+	{
+		Match: "jvm.memory.committed",
+		Name:  "jvm.heap_memory_committed",
+		RequiredAttributes: []AttributeValues{
+			{Key: "jvm.memory.type", Values: []string{"heap"}},
+		},
+		DroppedAttributes: []string{"jvm.memory.type"},
+	},
+	{
+		Match: "jvm.memory.committed",
+		Name:  "jvm.non_heap_memory_committed",
+		RequiredAttributes: []AttributeValues{
+			{Key: "jvm.memory.type", Values: []string{"non_heap"}},
+		},
+		DroppedAttributes: []string{"jvm.memory.type"},
+	},
+	{
+		Match: "jvm.memory.init",
+		Name:  "jvm.heap_memory_init",
+		RequiredAttributes: []AttributeValues{
+			{Key: "jvm.memory.type", Values: []string{"heap"}},
+		},
+		DroppedAttributes: []string{"jvm.memory.type"},
+	},
+	{
+		Match: "jvm.memory.init",
+		Name:  "jvm.non_heap_memory_init",
+		RequiredAttributes: []AttributeValues{
+			{Key: "jvm.memory.type", Values: []string{"non_heap"}},
+		},
+		DroppedAttributes: []string{"jvm.memory.type"},
+	},
+	{
+		Match: "jvm.memory.limit",
+		Name:  "jvm.heap_memory_max",
+		RequiredAttributes: []AttributeValues{
+			{Key: "jvm.memory.type", Values: []string{"heap"}},
+		},
+		DroppedAttributes: []string{"jvm.memory.type"},
+	},
+	{
+		Match: "jvm.memory.limit",
+		Name:  "jvm.non_heap_memory_max",
+		RequiredAttributes: []AttributeValues{
+			{Key: "jvm.memory.type", Values: []string{"non_heap"}},
+		},
+		DroppedAttributes: []string{"jvm.memory.type"},
+	},
+	{
+		Match: "jvm.buffer.memory.usage",
+		Name:  "jvm.buffer_pool.direct.used",
+		RequiredAttributes: []AttributeValues{
+			{Key: "jvm.buffer.pool.name", Values: []string{"direct"}},
+		},
+		DroppedAttributes: []string{"jvm.buffer.pool.name"},
+	},
+	{
+		Match: "jvm.buffer.memory.usage",
+		Name:  "jvm.buffer_pool.mapped.used",
+		RequiredAttributes: []AttributeValues{
+			{Key: "jvm.buffer.pool.name", Values: []string{"mapped"}},
+		},
+		DroppedAttributes: []string{"jvm.buffer.pool.name"},
+	},
+	{
+		Match: "jvm.buffer.count",
+		Name:  "jvm.buffer_pool.direct.count",
+		RequiredAttributes: []AttributeValues{
+			{Key: "jvm.buffer.pool.name", Values: []string{"direct"}},
+		},
+		DroppedAttributes: []string{"jvm.buffer.pool.name"},
+	},
+	{
+		Match: "jvm.buffer.count",
+		Name:  "jvm.buffer_pool.mapped.count",
+		RequiredAttributes: []AttributeValues{
+			{Key: "jvm.buffer.pool.name", Values: []string{"mapped"}},
+		},
+		DroppedAttributes: []string{"jvm.buffer.pool.name"},
+	},
+	{
+		Match: "jvm.buffer.memory.limit",
+		Name:  "jvm.buffer_pool.direct.limit",
+		RequiredAttributes: []AttributeValues{
+			{Key: "jvm.buffer.pool.name", Values: []string{"direct"}},
+		},
+		DroppedAttributes: []string{"jvm.buffer.pool.name"},
+	},
+	{
+		Match: "jvm.buffer.memory.limit",
+		Name:  "jvm.buffer_pool.mapped.limit",
+		RequiredAttributes: []AttributeValues{
+			{Key: "jvm.buffer.pool.name", Values: []string{"mapped"}},
+		},
+		DroppedAttributes: []string{"jvm.buffer.pool.name"},
+	},
 }
 
-var stableJavaRuntimeMetricsMappings = runtimeMetricMappingList{
-	"jvm.thread.count":           {{mappedName: "jvm.thread_count"}},
-	"jvm.class.count":            {{mappedName: "jvm.loaded_classes"}},
-	"jvm.system.cpu.utilization": {{mappedName: "jvm.cpu_load.system"}},
-	"jvm.cpu.recent_utilization": {{mappedName: "jvm.cpu_load.process"}},
-	"jvm.memory.used": {{
-		mappedName: "jvm.heap_memory",
-		attributes: []runtimeMetricAttribute{{
-			key:    "jvm.memory.type",
-			values: []string{"heap"},
-		}},
-	}, {
-		mappedName: "jvm.non_heap_memory",
-		attributes: []runtimeMetricAttribute{{
-			key:    "jvm.memory.type",
-			values: []string{"non_heap"},
-		}},
-	}, {
-		mappedName: "jvm.gc.old_gen_size",
-		attributes: []runtimeMetricAttribute{{
-			key:    "jvm.memory.pool.name",
-			values: []string{"G1 Old Gen", "Tenured Gen", "PS Old Gen"},
-		}, {
-			key:    "jvm.memory.type",
-			values: []string{"heap"},
-		}},
-	}, {
-		mappedName: "jvm.gc.eden_size",
-		attributes: []runtimeMetricAttribute{{
-			key:    "jvm.memory.pool.name",
-			values: []string{"G1 Eden Space", "Eden Space", "Par Eden Space", "PS Eden Space"},
-		}, {
-			key:    "jvm.memory.type",
-			values: []string{"heap"},
-		}},
-	}, {
-		mappedName: "jvm.gc.survivor_size",
-		attributes: []runtimeMetricAttribute{{
-			key:    "jvm.memory.pool.name",
-			values: []string{"G1 Survivor Space", "Survivor Space", "Par Survivor Space", "PS Survivor Space"},
-		}, {
-			key:    "jvm.memory.type",
-			values: []string{"heap"},
-		}},
-	}, {
-		mappedName: "jvm.gc.metaspace_size",
-		attributes: []runtimeMetricAttribute{{
-			key:    "jvm.memory.pool.name",
-			values: []string{"Metaspace"},
-		}, {
-			key:    "jvm.memory.type",
-			values: []string{"non_heap"},
-		}},
-	}},
-	"jvm.memory.committed": {{
-		mappedName: "jvm.heap_memory_committed",
-		attributes: []runtimeMetricAttribute{{
-			key:    "jvm.memory.type",
-			values: []string{"heap"},
-		}},
-	}, {
-		mappedName: "jvm.non_heap_memory_committed",
-		attributes: []runtimeMetricAttribute{{
-			key:    "jvm.memory.type",
-			values: []string{"non_heap"},
-		}},
-	}},
-	"jvm.memory.init": {{
-		mappedName: "jvm.heap_memory_init",
-		attributes: []runtimeMetricAttribute{{
-			key:    "jvm.memory.type",
-			values: []string{"heap"},
-		}},
-	}, {
-		mappedName: "jvm.non_heap_memory_init",
-		attributes: []runtimeMetricAttribute{{
-			key:    "jvm.memory.type",
-			values: []string{"non_heap"},
-		}},
-	}},
-	"jvm.memory.limit": {{
-		mappedName: "jvm.heap_memory_max",
-		attributes: []runtimeMetricAttribute{{
-			key:    "jvm.memory.type",
-			values: []string{"heap"},
-		}},
-	}, {
-		mappedName: "jvm.non_heap_memory_max",
-		attributes: []runtimeMetricAttribute{{
-			key:    "jvm.memory.type",
-			values: []string{"non_heap"},
-		}},
-	}},
-	"jvm.buffer.memory.usage": {{
-		mappedName: "jvm.buffer_pool.direct.used",
-		attributes: []runtimeMetricAttribute{{
-			key:    "jvm.buffer.pool.name",
-			values: []string{"direct"},
-		}},
-	}, {
-		mappedName: "jvm.buffer_pool.mapped.used",
-		attributes: []runtimeMetricAttribute{{
-			key:    "jvm.buffer.pool.name",
-			values: []string{"mapped"},
-		}},
-	}},
-	"jvm.buffer.count": {{
-		mappedName: "jvm.buffer_pool.direct.count",
-		attributes: []runtimeMetricAttribute{{
-			key:    "jvm.buffer.pool.name",
-			values: []string{"direct"},
-		}},
-	}, {
-		mappedName: "jvm.buffer_pool.mapped.count",
-		attributes: []runtimeMetricAttribute{{
-			key:    "jvm.buffer.pool.name",
-			values: []string{"mapped"},
-		}},
-	}},
-	"jvm.buffer.memory.limit": {{
-		mappedName: "jvm.buffer_pool.direct.limit",
-		attributes: []runtimeMetricAttribute{{
-			key:    "jvm.buffer.pool.name",
-			values: []string{"direct"},
-		}},
-	}, {
-		mappedName: "jvm.buffer_pool.mapped.limit",
-		attributes: []runtimeMetricAttribute{{
-			key:    "jvm.buffer.pool.name",
-			values: []string{"mapped"},
-		}},
-	}},
-}
-
-var javaRuntimeMetricsMappings = runtimeMetricMappingList{
-	"process.runtime.jvm.threads.count":          {{mappedName: "jvm.thread_count"}},
-	"process.runtime.jvm.classes.current_loaded": {{mappedName: "jvm.loaded_classes"}},
-	"process.runtime.jvm.system.cpu.utilization": {{mappedName: "jvm.cpu_load.system"}},
-	"process.runtime.jvm.cpu.utilization":        {{mappedName: "jvm.cpu_load.process"}},
-	"process.runtime.jvm.memory.usage": {{
-		mappedName: "jvm.heap_memory",
-		attributes: []runtimeMetricAttribute{{
-			key:    "type",
-			values: []string{"heap"},
-		}},
-	}, {
-		mappedName: "jvm.non_heap_memory",
-		attributes: []runtimeMetricAttribute{{
-			key:    "type",
-			values: []string{"non_heap"},
-		}},
-	}, {
-		mappedName: "jvm.gc.old_gen_size",
-		attributes: []runtimeMetricAttribute{{
-			key:    "pool",
-			values: []string{"G1 Old Gen", "Tenured Gen", "PS Old Gen"},
-		}, {
-			key:    "type",
-			values: []string{"heap"},
-		}},
-	}, {
-		mappedName: "jvm.gc.eden_size",
-		attributes: []runtimeMetricAttribute{{
-			key:    "pool",
-			values: []string{"G1 Eden Space", "Eden Space", "Par Eden Space", "PS Eden Space"},
-		}, {
-			key:    "type",
-			values: []string{"heap"},
-		}},
-	}, {
-		mappedName: "jvm.gc.survivor_size",
-		attributes: []runtimeMetricAttribute{{
-			key:    "pool",
-			values: []string{"G1 Survivor Space", "Survivor Space", "Par Survivor Space", "PS Survivor Space"},
-		}, {
-			key:    "type",
-			values: []string{"heap"},
-		}},
-	}, {
-		mappedName: "jvm.gc.metaspace_size",
-		attributes: []runtimeMetricAttribute{{
-			key:    "pool",
-			values: []string{"Metaspace"},
-		}, {
-			key:    "type",
-			values: []string{"non_heap"},
-		}},
-	}},
-	"process.runtime.jvm.memory.committed": {{
-		mappedName: "jvm.heap_memory_committed",
-		attributes: []runtimeMetricAttribute{{
-			key:    "type",
-			values: []string{"heap"},
-		}},
-	}, {
-		mappedName: "jvm.non_heap_memory_committed",
-		attributes: []runtimeMetricAttribute{{
-			key:    "type",
-			values: []string{"non_heap"},
-		}},
-	}},
-	"process.runtime.jvm.memory.init": {{
-		mappedName: "jvm.heap_memory_init",
-		attributes: []runtimeMetricAttribute{{
-			key:    "type",
-			values: []string{"heap"},
-		}},
-	}, {
-		mappedName: "jvm.non_heap_memory_init",
-		attributes: []runtimeMetricAttribute{{
-			key:    "type",
-			values: []string{"non_heap"},
-		}},
-	}},
-	"process.runtime.jvm.memory.limit": {{
-		mappedName: "jvm.heap_memory_max",
-		attributes: []runtimeMetricAttribute{{
-			key:    "type",
-			values: []string{"heap"},
-		}},
-	}, {
-		mappedName: "jvm.non_heap_memory_max",
-		attributes: []runtimeMetricAttribute{{
-			key:    "type",
-			values: []string{"non_heap"},
-		}},
-	}},
-	"process.runtime.jvm.buffer.usage": {{
-		mappedName: "jvm.buffer_pool.direct.used",
-		attributes: []runtimeMetricAttribute{{
-			key:    "pool",
-			values: []string{"direct"},
-		}},
-	}, {
-		mappedName: "jvm.buffer_pool.mapped.used",
-		attributes: []runtimeMetricAttribute{{
-			key:    "pool",
-			values: []string{"mapped"},
-		}},
-	}},
-	"process.runtime.jvm.buffer.count": {{
-		mappedName: "jvm.buffer_pool.direct.count",
-		attributes: []runtimeMetricAttribute{{
-			key:    "pool",
-			values: []string{"direct"},
-		}},
-	}, {
-		mappedName: "jvm.buffer_pool.mapped.count",
-		attributes: []runtimeMetricAttribute{{
-			key:    "pool",
-			values: []string{"mapped"},
-		}},
-	}},
-	"process.runtime.jvm.buffer.limit": {{
-		mappedName: "jvm.buffer_pool.direct.limit",
-		attributes: []runtimeMetricAttribute{{
-			key:    "pool",
-			values: []string{"direct"},
-		}},
-	}, {
-		mappedName: "jvm.buffer_pool.mapped.limit",
-		attributes: []runtimeMetricAttribute{{
-			key:    "pool",
-			values: []string{"mapped"},
-		}},
-	}},
-}
+// runtimeRemappingRules contains the rules for computing Datadog runtime metrics from their
+// OpenTelemetry counterparts. Unlike the other default rules, they apply regardless of WithRemapping.
+var runtimeRemappingRules = withFirstHistogramPointOnly(slices.Concat(
+	goRuntimeRemappingRules,
+	dotnetRuntimeRemappingRules,
+	javaRuntimeRemappingRules,
+	stableJavaRuntimeRemappingRules,
+))
 
-func getRuntimeMetricsMappings() runtimeMetricMappingList {
-	res := runtimeMetricMappingList{}
-	for k, v := range goRuntimeMetricsMappings {
-		res[k] = v
+// withFirstHistogramPointOnly sets FirstHistogramPointOnly on the rules selecting data points by attribute:
+// Datadog runtime metrics are computed from the first matching data point of Histogram metrics.
+func withFirstHistogramPointOnly(rules []RemappingRule) []RemappingRule {
+	for i := range rules {
+		if len(rules[i].RequiredAttributes) > 0 || len(rules[i].Filters) > 0 {
+			rules[i].FirstHistogramPointOnly = true
+		}
 	}
-	for k, v := range dotnetRuntimeMetricsMappings {
-		res[k] = v
-	}
-	for k, v := range javaRuntimeMetricsMappings {
-		res[k] = v
-	}
-	for k, v := range stableJavaRuntimeMetricsMappings {
-		res[k] = v
-	}
-	return res
+	return rules
 }
 
-// runtimeMetricsMappings defines the mappings from OTel runtime metric names to their
-// equivalent Datadog runtime metric names
-var runtimeMetricsMappings = getRuntimeMetricsMappings()
+// runtimeMetricNames are the names of the OpenTelemetry runtime metrics matched by the default rules.
+// Their language is reported in Metadata.Languages, even if their rules are disabled.
+var runtimeMetricNames = func() map[string]struct{} {
+	names := make(map[string]struct{}, len(runtimeRemappingRules))
+	for _, rule := range runtimeRemappingRules {
+		names[rule.Match] = struct{}{}
+	}
+	return names
+}()
@@ -0,0 +1,137 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2023-present Datadog, Inc.
+
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/DataDog/datadog-agent/pkg/util/quantile"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.uber.org/zap"
+)
+
+type quantileValue struct {
+	quantile float64
+	value    float64
+}
+
+// summaryToSketch builds a sketch of count values distributed following the quantiles of a summary point.
+// Values are spread linearly between consecutive quantiles. Values below the lowest quantile and above the
+// highest quantile are set to the value of that quantile. It returns nil if the summary has no valid quantiles.
+func summaryToSketch(quantiles pmetric.SummaryDataPointValueAtQuantileSlice, count uint64) (*quantile.Sketch, error) {
+	qvs := make([]quantileValue, 0, quantiles.Len())
+	for i := 0; i < quantiles.Len(); i++ {
+		q := quantiles.At(i)
+		if q.Quantile() < 0 || q.Quantile() > 1 || math.IsNaN(q.Quantile()) || math.IsNaN(q.Value()) || math.IsInf(q.Value(), 0) {
+			continue
+		}
+		qvs = append(qvs, quantileValue{quantile: q.Quantile(), value: q.Value()})
+	}
+	if len(qvs) == 0 || count == 0 {
+		return nil, nil
+	}
+	sort.Slice(qvs, func(i, j int) bool { return qvs[i].quantile < qvs[j].quantile })
+
+	agent := quantile.Agent{}
+	var inserted uint64
+	// insert adds the values up to the given quantile between lower and upper.
+	insert := func(lower, upper, q float64) error {
+		// Round the cumulative count to keep the total count exact.
+		n := uint64(math.Round(q*float64(count))) - inserted
+		if n == 0 {
+			return nil
+		}
+		inserted += n
+		return agent.InsertInterpolate(lower, upper, uint(n))
+	}
+
+	prev := qvs[0]
+	if err := insert(prev.value, prev.value, prev.quantile); err != nil {
+		return nil, err
+	}
+	for _, qv := range qvs[1:] {
+		// Quantile values may be slightly out of order due to the way they are computed.
+		qv.value = max(qv.value, prev.value)
+		if err := insert(prev.value, qv.value, qv.quantile); err != nil {
+			return nil, err
+		}
+		prev = qv
+	}
+	if err := insert(prev.value, prev.value, 1); err != nil {
+		return nil, err
+	}
+
+	sketch := agent.Finish()
+	if sketch == nil {
+		return nil, nil
+	}
+	// Use the exact extrema if known.
+	if first := qvs[0]; first.quantile == 0 {
+		sketch.Basic.Min = first.value
+	}
+	if last := qvs[len(qvs)-1]; last.quantile == 1 {
+		sketch.Basic.Max = max(last.value, sketch.Basic.Min)
+	}
+	return sketch, nil
+}
+
+// mapSummaryDistribution maps a summary datapoint into a Datadog distribution.
+// As with the summary count in SummaryModeCountSum, the count and sum are treated
+// as cumulative and the distribution only holds the values since the previous point.
+func (t *Translator) mapSummaryDistribution(
+	ctx context.Context,
+	consumer SketchConsumer,
+	pointDims *Dimensions,
+	p pmetric.SummaryDataPoint,
+	firstInSlice bool,
+) {
+	startTs := uint64(p.StartTimestamp())
+	ts := uint64(p.Timestamp())
+
+	// Use the same cache entries as SummaryModeCountSum.
+	countDims := pointDims.WithSuffix("count")
+	sumDims := pointDims.WithSuffix("sum")
+
+	count := float64(p.Count())
+	dx, isFirstPoint, shouldDropPoint := t.prevPts.MonotonicDiff(countDims, startTs, ts, count)
+	if shouldDropPoint {
+		t.telemetry.dropped(ctx, dropReasonOutOfOrder, 1)
+		return
+	}
+
+	sum, hasSum := p.Sum(), false
+	if !t.isSkippable(ctx, sumDims.name, sum) {
+		sum, hasSum, _ = t.prevPts.Diff(sumDims, startTs, ts, sum)
+	}
+
+	if isFirstPoint {
+		// We only compute the first point in the timeseries if it is the first value in the datapoint slice.
+		if !firstInSlice || !t.shouldConsumeInitialValue(startTs, ts) {
+			return
+		}
+		dx, sum, hasSum = count, p.Sum(), !math.IsNaN(p.Sum()) && !math.IsInf(p.Sum(), 0)
+	}
+
+	sketch, err := summaryToSketch(p.QuantileValues(), uint64(dx))
+	if err != nil {
+		t.logger.Debug("Failed to convert summary into sketch",
+			zap.String(metricName, pointDims.name),
+			zap.Error(fmt.Errorf("failed to insert quantiles: %w", err)),
+		)
+		return
+	}
+	if sketch == nil {
+		return
+	}
+	if hasSum {
+		sketch.Basic.Sum = sum
+		sketch.Basic.Avg = sum / float64(sketch.Basic.Cnt)
+	}
+	consumer.ConsumeSketch(ctx, pointDims, ts, 0, sketch)
+}
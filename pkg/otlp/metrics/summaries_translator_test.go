@@ -0,0 +1,182 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2023-present Datadog, Inc.
+
+package metrics
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/DataDog/datadog-agent/pkg/util/quantile"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+)
+
+func newQuantiles(qvs ...quantileValue) pmetric.SummaryDataPointValueAtQuantileSlice {
+	quantiles := pmetric.NewSummaryDataPointValueAtQuantileSlice()
+	for _, qv := range qvs {
+		q := quantiles.AppendEmpty()
+		q.SetQuantile(qv.quantile)
+		q.SetValue(qv.value)
+	}
+	return quantiles
+}
+
+func TestSummaryToSketch(t *testing.T) {
+	cfg := quantile.Default()
+	tests := []struct {
+		name      string
+		quantiles []quantileValue
+		count     uint64
+		// expected quantiles of the sketch, if any
+		expected    []quantileValue
+		expectedMin float64
+		expectedMax float64
+		expectedNil bool
+	}{
+		{
+			name:        "min, max and quantiles",
+			quantiles:   []quantileValue{{1, 100}, {0, 1}, {0.5, 10}, {0.9, 50}},
+			count:       100,
+			expected:    []quantileValue{{0.5, 10}, {0.9, 50}},
+			expectedMin: 1,
+			expectedMax: 100,
+		},
+		{
+			name:        "no extrema",
+			quantiles:   []quantileValue{{0.5, 10}, {0.99, 20}},
+			count:       1000,
+			expected:    []quantileValue{{0.25, 10}, {0.5, 10}, {0.99, 20}, {1, 20}},
+			expectedMin: 10,
+			expectedMax: 20,
+		},
+		{
+			name:        "out of order values",
+			quantiles:   []quantileValue{{0.5, 10}, {0.9, 9.9}},
+			count:       10,
+			expected:    []quantileValue{{0.5, 10}, {1, 10}},
+			expectedMin: 10,
+			expectedMax: 10,
+		},
+		{
+			name:        "invalid quantiles are ignored",
+			quantiles:   []quantileValue{{0.5, math.NaN()}, {math.NaN(), 3}, {2, 3}, {0.5, 5}},
+			count:       4,
+			expected:    []quantileValue{{0.5, 5}},
+			expectedMin: 5,
+			expectedMax: 5,
+		},
+		{
+			name:        "no quantiles",
+			count:       10,
+			expectedNil: true,
+		},
+		{
+			name:        "zero count",
+			quantiles:   []quantileValue{{0.5, 10}},
+			expectedNil: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sketch, err := summaryToSketch(newQuantiles(tt.quantiles...), tt.count)
+			require.NoError(t, err)
+			if tt.expectedNil {
+				assert.Nil(t, sketch)
+				return
+			}
+			require.NotNil(t, sketch)
+			assert.Equal(t, int64(tt.count), sketch.Basic.Cnt)
+			assert.InEpsilon(t, tt.expectedMin, sketch.Basic.Min, 0.01)
+			assert.InEpsilon(t, tt.expectedMax, sketch.Basic.Max, 0.01)
+			for _, qv := range tt.expected {
+				assert.InEpsilon(t, qv.value, sketch.Quantile(cfg, qv.quantile), 0.03, "quantile %v", qv.quantile)
+			}
+		})
+	}
+}
+
+func TestSummaryModeDistributions(t *testing.T) {
+	newMetrics := func(ts int, count uint64, sum float64) pmetric.Metrics {
+		md := pmetric.NewMetrics()
+		m := md.ResourceMetrics().AppendEmpty().ScopeMetrics().AppendEmpty().Metrics().AppendEmpty()
+		m.SetName("summary")
+		dp := m.SetEmptySummary().DataPoints().AppendEmpty()
+		dp.SetStartTimestamp(seconds(1))
+		dp.SetTimestamp(seconds(ts))
+		dp.SetCount(count)
+		dp.SetSum(sum)
+		newQuantiles(quantileValue{0, 1}, quantileValue{0.5, 20}, quantileValue{1, 50}).CopyTo(dp.QuantileValues())
+		return md
+	}
+
+	translator := NewTestTranslator(t,
+		WithSummaryMode(SummaryModeDistributions),
+		WithInitialCumulMonoValueMode(InitialCumulMonoValueModeKeep),
+	)
+	consumer := &mockFullConsumer{}
+	_, err := translator.MapMetrics(context.Background(), newMetrics(2, 10, 100), consumer, nil)
+	require.NoError(t, err)
+	_, err = translator.MapMetrics(context.Background(), newMetrics(3, 30, 500), consumer, nil)
+	require.NoError(t, err)
+
+	assert.Empty(t, consumer.metrics, "count and sum are only reported through the distribution")
+	require.Len(t, consumer.sketches, 2)
+
+	first := consumer.sketches[0]
+	assert.Equal(t, "summary", first.name)
+	assert.Equal(t, uint64(seconds(2)), first.timestamp)
+	assert.Equal(t, int64(10), first.basic.Cnt)
+	assert.Equal(t, 100.0, first.basic.Sum)
+	assert.Equal(t, 10.0, first.basic.Avg)
+	assert.Equal(t, 1.0, first.basic.Min)
+	assert.Equal(t, 50.0, first.basic.Max)
+
+	second := consumer.sketches[1]
+	assert.Equal(t, int64(20), second.basic.Cnt)
+	assert.Equal(t, 400.0, second.basic.Sum)
+	assert.Equal(t, 20.0, second.basic.Avg)
+}
+
+func TestSummaryModeOption(t *testing.T) {
+	_, err := NewTranslator(componenttest.NewNopTelemetrySettings(), nil, WithSummaryMode("quantiles"))
+	assert.EqualError(t, err, `unknown summary mode: "quantiles"`)
+}
+
+func TestNonMonotonicSumModeCumulativeToDelta(t *testing.T) {
+	newMetrics := func(ts int, val float64) pmetric.Metrics {
+		md := pmetric.NewMetrics()
+		m := md.ResourceMetrics().AppendEmpty().ScopeMetrics().AppendEmpty().Metrics().AppendEmpty()
+		m.SetName("updown")
+		sum := m.SetEmptySum()
+		sum.SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
+		sum.SetIsMonotonic(false)
+		dp := sum.DataPoints().AppendEmpty()
+		dp.SetStartTimestamp(seconds(1))
+		dp.SetTimestamp(seconds(ts))
+		dp.SetDoubleValue(val)
+		return md
+	}
+
+	translator := NewTestTranslator(t, WithNonMonotonicSumMode(NonMonotonicSumModeCumulativeToDelta))
+	consumer := &mockFullConsumer{}
+	for ts, val := range []float64{10, 4, 6} {
+		_, err := translator.MapMetrics(context.Background(), newMetrics(ts+2, val), consumer, nil)
+		require.NoError(t, err)
+	}
+
+	require.Len(t, consumer.metrics, 2)
+	assert.Equal(t, Count, consumer.metrics[0].typ)
+	assert.Equal(t, -6.0, consumer.metrics[0].value)
+	assert.Equal(t, Count, consumer.metrics[1].typ)
+	assert.Equal(t, 2.0, consumer.metrics[1].value)
+
+	_, err := NewTranslator(componenttest.NewNopTelemetrySettings(), nil, WithNonMonotonicSumMode("delta"))
+	assert.EqualError(t, err, `unknown non-monotonic sum mode: "delta"`)
+}
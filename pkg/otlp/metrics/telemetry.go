@@ -0,0 +1,213 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2023-present Datadog, Inc.
+
+package metrics
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/DataDog/datadog-agent/pkg/util/quantile"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/otel/attribute"
+	otelmetric "go.opentelemetry.io/otel/metric"
+)
+
+const (
+	pointsReceivedMetricName string = "datadog.otlp_translator.metrics.points_received"
+	pointsSentMetricName     string = "datadog.otlp_translator.metrics.points_sent"
+	droppedPointsMetricName  string = "datadog.otlp_translator.metrics.dropped_points"
+	cacheSizeMetricName      string = "datadog.otlp_translator.metrics.cache_size"
+	mapDurationMetricName    string = "datadog.otlp_translator.metrics.map_duration"
+)
+
+// dropReason is the reason why the translator dropped an OTLP data point.
+type dropReason string
+
+const (
+	// dropReasonUnsupportedValue is used for NaN and infinite values.
+	dropReasonUnsupportedValue dropReason = "unsupported_value"
+	// dropReasonOutOfOrder is used for cumulative points older than the last point of their timeseries.
+	dropReasonOutOfOrder dropReason = "out_of_order"
+	// dropReasonReset is used for cumulative points after a reset of their timeseries, which have no delta
+	// with the previous point.
+	dropReasonReset dropReason = "reset"
+	// dropReasonUnsupportedTemporality is used for points with an unspecified aggregation temporality.
+	dropReasonUnsupportedTemporality dropReason = "unsupported_temporality"
+	// dropReasonUnknownMetricType is used for metrics of an unknown type.
+	dropReasonUnknownMetricType dropReason = "unknown_metric_type"
+)
+
+var dropReasons = []dropReason{
+	dropReasonUnsupportedValue,
+	dropReasonOutOfOrder,
+	dropReasonReset,
+	dropReasonUnsupportedTemporality,
+	dropReasonUnknownMetricType,
+}
+
+var metricTypes = []pmetric.MetricType{
+	pmetric.MetricTypeEmpty,
+	pmetric.MetricTypeGauge,
+	pmetric.MetricTypeSum,
+	pmetric.MetricTypeHistogram,
+	pmetric.MetricTypeExponentialHistogram,
+	pmetric.MetricTypeSummary,
+}
+
+// translatorTelemetry holds the internal telemetry of a Translator.
+type translatorTelemetry struct {
+	pointsReceived otelmetric.Int64Counter
+	pointsSent     otelmetric.Int64Counter
+	droppedPoints  otelmetric.Int64Counter
+	mapDuration    otelmetric.Float64Histogram
+	// cacheSizeRegistration is the registration of the callback observing the cache size.
+	cacheSizeRegistration otelmetric.Registration
+
+	// Attribute sets are computed once since they are used on every point.
+	receivedAttrs map[pmetric.MetricType]otelmetric.MeasurementOption
+	droppedAttrs  map[dropReason]otelmetric.MeasurementOption
+	gaugeAttrs    otelmetric.MeasurementOption
+	countAttrs    otelmetric.MeasurementOption
+	sketchAttrs   otelmetric.MeasurementOption
+}
+
+func typeAttrs(typ string) otelmetric.MeasurementOption {
+	return otelmetric.WithAttributeSet(attribute.NewSet(attribute.String("type", typ)))
+}
+
+func newTranslatorTelemetry(set component.TelemetrySettings, cache *ttlCache) (*translatorTelemetry, error) {
+	meter := set.MeterProvider.Meter(meterName)
+	tel := &translatorTelemetry{
+		receivedAttrs: make(map[pmetric.MetricType]otelmetric.MeasurementOption, len(metricTypes)),
+		droppedAttrs:  make(map[dropReason]otelmetric.MeasurementOption, len(dropReasons)),
+		gaugeAttrs:    typeAttrs("gauge"),
+		countAttrs:    typeAttrs("count"),
+		sketchAttrs:   typeAttrs("sketch"),
+	}
+	for _, typ := range metricTypes {
+		tel.receivedAttrs[typ] = typeAttrs(typ.String())
+	}
+	for _, reason := range dropReasons {
+		tel.droppedAttrs[reason] = otelmetric.WithAttributeSet(attribute.NewSet(attribute.String("reason", string(reason))))
+	}
+
+	var err error
+	tel.pointsReceived, err = meter.Int64Counter(
+		pointsReceivedMetricName,
+		otelmetric.WithDescription("OTLP data points received by the translator, by OTLP metric type"),
+		otelmetric.WithUnit("{point}"),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build points received counter: %w", err)
+	}
+	tel.pointsSent, err = meter.Int64Counter(
+		pointsSentMetricName,
+		otelmetric.WithDescription("Datadog points sent by the translator to the consumer, by Datadog metric type"),
+		otelmetric.WithUnit("{point}"),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build points sent counter: %w", err)
+	}
+	tel.droppedPoints, err = meter.Int64Counter(
+		droppedPointsMetricName,
+		otelmetric.WithDescription("OTLP data points or values dropped by the translator, by reason. Metrics of unknown type count as a single point"),
+		otelmetric.WithUnit("{point}"),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build dropped points counter: %w", err)
+	}
+	tel.mapDuration, err = meter.Float64Histogram(
+		mapDurationMetricName,
+		otelmetric.WithDescription("Duration of MapMetrics calls"),
+		otelmetric.WithUnit("s"),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build map duration histogram: %w", err)
+	}
+	cacheSize, err := meter.Int64ObservableGauge(
+		cacheSizeMetricName,
+		otelmetric.WithDescription("Number of entries in the cache used for computing deltas from cumulative timeseries"),
+		otelmetric.WithUnit("{entry}"),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build cache size gauge: %w", err)
+	}
+	// The callback references the cache of the translator: it is registered separately so that
+	// it can be unregistered on shutdown, letting the cache be garbage collected.
+	tel.cacheSizeRegistration, err = meter.RegisterCallback(func(_ context.Context, o otelmetric.Observer) error {
+		o.ObserveInt64(cacheSize, int64(cache.cache.ItemCount()))
+		return nil
+	}, cacheSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to register cache size callback: %w", err)
+	}
+	return tel, nil
+}
+
+// shutdown unregisters the callbacks of the asynchronous instruments.
+func (tel *translatorTelemetry) shutdown() error {
+	return tel.cacheSizeRegistration.Unregister()
+}
+
+// dataPointCount returns the number of data points of a metric.
+func dataPointCount(md pmetric.Metric) int {
+	switch md.Type() {
+	case pmetric.MetricTypeGauge:
+		return md.Gauge().DataPoints().Len()
+	case pmetric.MetricTypeSum:
+		return md.Sum().DataPoints().Len()
+	case pmetric.MetricTypeHistogram:
+		return md.Histogram().DataPoints().Len()
+	case pmetric.MetricTypeExponentialHistogram:
+		return md.ExponentialHistogram().DataPoints().Len()
+	case pmetric.MetricTypeSummary:
+		return md.Summary().DataPoints().Len()
+	}
+	return 0
+}
+
+// received records the data points of a metric received by the translator.
+func (tel *translatorTelemetry) received(ctx context.Context, md pmetric.Metric) {
+	if n := dataPointCount(md); n > 0 {
+		tel.pointsReceived.Add(ctx, int64(n), tel.receivedAttrs[md.Type()])
+	}
+}
+
+// dropped records n data points dropped for the given reason. Nothing is recorded without a reason.
+func (tel *translatorTelemetry) dropped(ctx context.Context, reason dropReason, n int) {
+	if n > 0 && reason != "" {
+		tel.droppedPoints.Add(ctx, int64(n), tel.droppedAttrs[reason])
+	}
+}
+
+// countingConsumer records the points passed to the wrapped Consumer.
+type countingConsumer struct {
+	Consumer
+	tel *translatorTelemetry
+}
+
+var _ Consumer = (*countingConsumer)(nil)
+
+// wrap returns a Consumer that records the points passed to consumer.
+func (tel *translatorTelemetry) wrap(consumer Consumer) Consumer {
+	return &countingConsumer{Consumer: consumer, tel: tel}
+}
+
+func (c *countingConsumer) ConsumeTimeSeries(ctx context.Context, dimensions *Dimensions, typ DataType, timestamp uint64, interval int64, value float64) {
+	switch typ {
+	case Gauge:
+		c.tel.pointsSent.Add(ctx, 1, c.tel.gaugeAttrs)
+	case Count:
+		c.tel.pointsSent.Add(ctx, 1, c.tel.countAttrs)
+	}
+	c.Consumer.ConsumeTimeSeries(ctx, dimensions, typ, timestamp, interval, value)
+}
+
+func (c *countingConsumer) ConsumeSketch(ctx context.Context, dimensions *Dimensions, timestamp uint64, interval int64, sketch *quantile.Sketch) {
+	c.tel.pointsSent.Add(ctx, 1, c.tel.sketchAttrs)
+	c.Consumer.ConsumeSketch(ctx, dimensions, timestamp, interval, sketch)
+}
@@ -0,0 +1,249 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2023-present Datadog, Inc.
+
+package metrics
+
+import (
+	"context"
+	"math"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/DataDog/opentelemetry-mapping-go/pkg/otlp/attributes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+func TestTranslatorTelemetry(t *testing.T) {
+	set := componenttest.NewNopTelemetrySettings()
+	reader := sdkmetric.NewManualReader()
+	set.MeterProvider = sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+	attributesTranslator, err := attributes.NewTranslator(componenttest.NewNopTelemetrySettings())
+	require.NoError(t, err)
+	translator, err := NewTranslator(set, attributesTranslator)
+	require.NoError(t, err)
+
+	newMetrics := func(ts int, val float64) pmetric.Metrics {
+		md := pmetric.NewMetrics()
+		ms := md.ResourceMetrics().AppendEmpty().ScopeMetrics().AppendEmpty().Metrics()
+
+		gauge := ms.AppendEmpty()
+		gauge.SetName("gauge")
+		gauge.SetEmptyGauge().DataPoints().AppendEmpty().SetDoubleValue(1)
+		gauge.Gauge().DataPoints().AppendEmpty().SetDoubleValue(math.NaN())
+
+		sum := ms.AppendEmpty()
+		sum.SetName("cumulative.sum")
+		sum.SetEmptySum().SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
+		sum.Sum().SetIsMonotonic(true)
+		dp := sum.Sum().DataPoints().AppendEmpty()
+		dp.SetStartTimestamp(seconds(1))
+		dp.SetTimestamp(seconds(ts))
+		dp.SetDoubleValue(val)
+
+		unspecified := ms.AppendEmpty()
+		unspecified.SetName("unspecified.sum")
+		unspecified.SetEmptySum().DataPoints().AppendEmpty().SetDoubleValue(1)
+
+		hist := ms.AppendEmpty()
+		hist.SetName("exp.hist")
+		hist.SetEmptyExponentialHistogram().SetAggregationTemporality(pmetric.AggregationTemporalityDelta)
+		hdp := hist.ExponentialHistogram().DataPoints().AppendEmpty()
+		hdp.SetCount(1)
+		hdp.Positive().BucketCounts().FromRaw([]uint64{1})
+
+		ms.AppendEmpty().SetName("empty")
+		return md
+	}
+
+	consumer := &mockFullConsumer{}
+	_, err = translator.MapMetrics(context.Background(), newMetrics(3, 10), consumer, nil)
+	require.NoError(t, err)
+	// The second point of the cumulative sum is older than the first one.
+	_, err = translator.MapMetrics(context.Background(), newMetrics(2, 5), consumer, nil)
+	require.NoError(t, err)
+
+	rm := &metricdata.ResourceMetrics{}
+	require.NoError(t, reader.Collect(context.Background(), rm))
+
+	assert.Equal(t, map[string]int64{
+		"Gauge":                4,
+		"Sum":                  4,
+		"ExponentialHistogram": 2,
+	}, sumValues(t, rm, pointsReceivedMetricName, "type"))
+	assert.Equal(t, map[string]int64{
+		"gauge":  2,
+		"sketch": 2,
+	}, sumValues(t, rm, pointsSentMetricName, "type"))
+	assert.Equal(t, map[string]int64{
+		"unsupported_value":       2,
+		"out_of_order":            1,
+		"unsupported_temporality": 2,
+		"unknown_metric_type":     2,
+	}, sumValues(t, rm, droppedPointsMetricName, "reason"))
+
+	var foundCacheSize, foundDuration bool
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			switch m.Name {
+			case cacheSizeMetricName:
+				foundCacheSize = true
+				dps := m.Data.(metricdata.Gauge[int64]).DataPoints
+				require.Len(t, dps, 1)
+				assert.Equal(t, int64(translator.prevPts.cache.ItemCount()), dps[0].Value)
+			case mapDurationMetricName:
+				foundDuration = true
+				dps := m.Data.(metricdata.Histogram[float64]).DataPoints
+				require.Len(t, dps, 1)
+				assert.Equal(t, uint64(2), dps[0].Count)
+			}
+		}
+	}
+	assert.True(t, foundCacheSize)
+	assert.True(t, foundDuration)
+}
+
+func TestTranslatorTelemetryShutdown(t *testing.T) {
+	set := componenttest.NewNopTelemetrySettings()
+	reader := sdkmetric.NewManualReader()
+	set.MeterProvider = sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+	attributesTranslator, err := attributes.NewTranslator(componenttest.NewNopTelemetrySettings())
+	require.NoError(t, err)
+	first, err := NewTranslator(set, attributesTranslator)
+	require.NoError(t, err)
+	second, err := NewTranslator(set, attributesTranslator)
+	require.NoError(t, err)
+
+	newSum := func(name string) pmetric.Metrics {
+		md := pmetric.NewMetrics()
+		sum := md.ResourceMetrics().AppendEmpty().ScopeMetrics().AppendEmpty().Metrics().AppendEmpty()
+		sum.SetName(name)
+		sum.SetEmptySum().SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
+		sum.Sum().SetIsMonotonic(true)
+		dp := sum.Sum().DataPoints().AppendEmpty()
+		dp.SetStartTimestamp(seconds(1))
+		dp.SetTimestamp(seconds(2))
+		dp.SetDoubleValue(1)
+		return md
+	}
+	for _, name := range []string{"a", "b"} {
+		_, err = second.MapMetrics(context.Background(), newSum(name), &mockFullConsumer{}, nil)
+		require.NoError(t, err)
+	}
+	require.Equal(t, 0, first.prevPts.cache.ItemCount())
+	require.Equal(t, 2, second.prevPts.cache.ItemCount())
+
+	cacheSizes := func() []int64 {
+		rm := &metricdata.ResourceMetrics{}
+		require.NoError(t, reader.Collect(context.Background(), rm))
+		var values []int64
+		for _, sm := range rm.ScopeMetrics {
+			for _, m := range sm.Metrics {
+				if m.Name == cacheSizeMetricName {
+					for _, dp := range m.Data.(metricdata.Gauge[int64]).DataPoints {
+						values = append(values, dp.Value)
+					}
+				}
+			}
+		}
+		return values
+	}
+
+	// Once the first translator is shut down, only the cache of the second one is observed.
+	require.NoError(t, first.Shutdown(context.Background()))
+	assert.Equal(t, []int64{2}, cacheSizes())
+
+	require.NoError(t, second.Shutdown(context.Background()))
+	assert.Empty(t, cacheSizes())
+}
+
+func TestTranslatorTelemetryGarbageCollected(t *testing.T) {
+	set := componenttest.NewNopTelemetrySettings()
+	reader := sdkmetric.NewManualReader()
+	set.MeterProvider = sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+	attributesTranslator, err := attributes.NewTranslator(componenttest.NewNopTelemetrySettings())
+	require.NoError(t, err)
+
+	func() {
+		// The translator is dropped without being shut down.
+		_, err := NewTranslator(set, attributesTranslator)
+		require.NoError(t, err)
+	}()
+
+	hasCacheSize := func() bool {
+		rm := &metricdata.ResourceMetrics{}
+		assert.NoError(t, reader.Collect(context.Background(), rm))
+		for _, sm := range rm.ScopeMetrics {
+			for _, m := range sm.Metrics {
+				if m.Name == cacheSizeMetricName && len(m.Data.(metricdata.Gauge[int64]).DataPoints) > 0 {
+					return true
+				}
+			}
+		}
+		return false
+	}
+	assert.Eventually(t, func() bool {
+		runtime.GC()
+		return !hasCacheSize()
+	}, 5*time.Second, 10*time.Millisecond, "the cache size callback is unregistered once the translator is garbage collected")
+}
+
+func TestTranslatorTelemetryCumulativeHistogramDrops(t *testing.T) {
+	set := componenttest.NewNopTelemetrySettings()
+	reader := sdkmetric.NewManualReader()
+	set.MeterProvider = sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+	attributesTranslator, err := attributes.NewTranslator(componenttest.NewNopTelemetrySettings())
+	require.NoError(t, err)
+	translator, err := NewTranslator(set, attributesTranslator)
+	require.NoError(t, err)
+
+	newMetrics := func(startTs, ts int, count uint64) pmetric.Metrics {
+		md := pmetric.NewMetrics()
+		ms := md.ResourceMetrics().AppendEmpty().ScopeMetrics().AppendEmpty().Metrics()
+
+		hist := ms.AppendEmpty()
+		hist.SetName("hist")
+		hist.SetEmptyHistogram().SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
+		dp := hist.Histogram().DataPoints().AppendEmpty()
+		dp.SetStartTimestamp(seconds(startTs))
+		dp.SetTimestamp(seconds(ts))
+		dp.SetCount(count)
+		dp.ExplicitBounds().FromRaw([]float64{1})
+		dp.BucketCounts().FromRaw([]uint64{count, 0})
+
+		expHist := ms.AppendEmpty()
+		expHist.SetName("exp.hist")
+		expHist.SetEmptyExponentialHistogram().SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
+		edp := expHist.ExponentialHistogram().DataPoints().AppendEmpty()
+		edp.SetStartTimestamp(seconds(startTs))
+		edp.SetTimestamp(seconds(ts))
+		edp.SetCount(count)
+		edp.Positive().BucketCounts().FromRaw([]uint64{count})
+		return md
+	}
+
+	consumer := &mockFullConsumer{}
+	for _, md := range []pmetric.Metrics{
+		newMetrics(1, 2, 1), // first points: not dropped
+		newMetrics(1, 3, 2),
+		newMetrics(1, 1, 1), // out of order
+		newMetrics(4, 5, 1), // reset
+	} {
+		_, err = translator.MapMetrics(context.Background(), md, consumer, nil)
+		require.NoError(t, err)
+	}
+
+	rm := &metricdata.ResourceMetrics{}
+	require.NoError(t, reader.Collect(context.Background(), rm))
+	assert.Equal(t, map[string]int64{
+		"out_of_order": 2,
+		"reset":        2,
+	}, sumValues(t, rm, droppedPointsMetricName, "reason"))
+}
@@ -18,6 +18,8 @@ import (
 	"time"
 
 	gocache "github.com/patrickmn/go-cache"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
 )
 
 type ttlCache struct {
@@ -39,7 +41,8 @@ func newTTLCache(sweepInterval int64, deltaTTL int64) *ttlCache {
 
 // Diff submits a new value for a given non-monotonic metric and returns the difference with the
 // last submitted value (ordered by timestamp). The diff value is only valid if `ok` is true.
-func (t *ttlCache) Diff(dimensions *Dimensions, startTs, ts uint64, val float64) (float64, bool) {
+// If the point is not the first one of its timeseries, it also returns why no diff could be computed.
+func (t *ttlCache) Diff(dimensions *Dimensions, startTs, ts uint64, val float64) (float64, bool, dropReason) {
 	return t.putAndGetDiff(dimensions, startTs, ts, val)
 }
 
@@ -87,8 +90,9 @@ func (t *ttlCache) putAndGetMonotonic(
 	firstPoint = true
 
 	key := dimensions.String()
-	if c, found := t.cache.Get(key); found {
-		cnt := c.(numberCounter)
+	// A cached value of another type is from a timeseries of another type with the same dimensions:
+	// it is treated as a reset.
+	if cnt, found := t.getNumberCounter(key); found {
 		if cnt.ts >= ts {
 			// We were given a point with a timestamp older or equal to the one in the cache. This point
 			// should be dropped. We keep the current point in cache.
@@ -116,21 +120,24 @@ func (t *ttlCache) putAndGetMonotonic(
 
 // putAndGetDiff submits a new value for a given metric and returns the difference with the
 // last submitted value (ordered by timestamp). The diff value is only valid if `ok` is true.
+// Otherwise, drop is the reason why the point has no diff, or empty for the first point of a timeseries.
 func (t *ttlCache) putAndGetDiff(
 	dimensions *Dimensions,
 	startTs, ts uint64,
 	val float64,
-) (dx float64, ok bool) {
+) (dx float64, ok bool, drop dropReason) {
 	key := dimensions.String()
-	if c, found := t.cache.Get(key); found {
-		cnt := c.(numberCounter)
+	if cnt, found := t.getNumberCounter(key); found {
 		if cnt.ts > ts {
 			// We were given a point older than the one in memory so we drop it
 			// We keep the existing point in memory since it is the most recent
-			return 0, false
+			return 0, false, dropReasonOutOfOrder
 		}
 		dx = val - cnt.value
 		ok = isNotFirstPoint(startTs, ts, cnt.startTs)
+		if !ok {
+			drop = dropReasonReset
+		}
 	}
 
 	t.cache.Set(
@@ -145,6 +152,16 @@ func (t *ttlCache) putAndGetDiff(
 	return
 }
 
+// getNumberCounter returns the numberCounter cached for key, if any.
+func (t *ttlCache) getNumberCounter(key string) (numberCounter, bool) {
+	c, found := t.cache.Get(key)
+	if !found {
+		return numberCounter{}, false
+	}
+	cnt, ok := c.(numberCounter)
+	return cnt, ok
+}
+
 type extrema struct {
 	ts            uint64
 	startTs       uint64
@@ -160,26 +177,28 @@ func (t *ttlCache) putAndCheckExtrema(
 	min bool,
 ) (assumeFromLastWindow bool) {
 	key := dimensions.String()
+	// A cached value of another type is from a timeseries of another type with the same dimensions:
+	// it is treated as a reset.
 	if c, found := t.cache.Get(key); found {
-		cnt := c.(extrema)
-		if cnt.ts > ts {
-			// We were given a point older than the one in memory so we drop it
-			// We keep the existing point in memory since it is the most recent
-			// Don't use the extrema, we don't have enough information.
-			return false
-		}
+		if cnt, isExtrema := c.(extrema); isExtrema {
+			if cnt.ts > ts {
+				// We were given a point older than the one in memory so we drop it
+				// We keep the existing point in memory since it is the most recent
+				// Don't use the extrema, we don't have enough information.
+				return false
+			}
 
-		isNotFirst := isNotFirstPoint(startTs, ts, cnt.startTs)
-		if min {
-			// We assume the minimum comes from the last time window if either of the following is true:
-			// - the point is NOT the first in the timeseries AND is lower than the previous one
-			// - the global minimum is bigger than the stored minimum (and therefore a reset must have happened)
-			assumeFromLastWindow = (isNotFirst && curExtrema < cnt.storedExtrema) || (curExtrema > cnt.storedExtrema)
-		} else { // not min, therefore max
-			// symmetric to the min
-			assumeFromLastWindow = (isNotFirst && curExtrema > cnt.storedExtrema) || (curExtrema < cnt.storedExtrema)
+			isNotFirst := isNotFirstPoint(startTs, ts, cnt.startTs)
+			if min {
+				// We assume the minimum comes from the last time window if either of the following is true:
+				// - the point is NOT the first in the timeseries AND is lower than the previous one
+				// - the global minimum is bigger than the stored minimum (and therefore a reset must have happened)
+				assumeFromLastWindow = (isNotFirst && curExtrema < cnt.storedExtrema) || (curExtrema > cnt.storedExtrema)
+			} else { // not min, therefore max
+				// symmetric to the min
+				assumeFromLastWindow = (isNotFirst && curExtrema > cnt.storedExtrema) || (curExtrema < cnt.storedExtrema)
+			}
 		}
-
 	}
 
 	t.cache.Set(key,
@@ -203,3 +222,167 @@ func (t *ttlCache) PutAndCheckMin(dimensions *Dimensions, startTs, ts uint64, cu
 func (t *ttlCache) PutAndCheckMax(dimensions *Dimensions, startTs, ts uint64, curMax float64) (isMaxFromLastTimeWindow bool) {
 	return t.putAndCheckExtrema(dimensions, startTs, ts, curMax, false)
 }
+
+// expBuckets keeps a copy of one of the bucket ranges of an exponential histogram point.
+type expBuckets struct {
+	offset int32
+	counts []uint64
+}
+
+func newExpBuckets(b pmetric.ExponentialHistogramDataPointBuckets) expBuckets {
+	return expBuckets{offset: b.Offset(), counts: b.BucketCounts().AsRaw()}
+}
+
+// downscale merges buckets so that they match a scale smaller by shift.
+// Each decrement of the scale merges pairs of adjacent buckets.
+func (b expBuckets) downscale(shift int32) expBuckets {
+	if shift <= 0 || len(b.counts) == 0 {
+		return b
+	}
+	// Right shifts on signed integers round towards negative infinity,
+	// which is what we want for negative bucket indices.
+	offset := b.offset >> shift
+	last := (b.offset + int32(len(b.counts)) - 1) >> shift
+	counts := make([]uint64, last-offset+1)
+	for i, count := range b.counts {
+		counts[((b.offset+int32(i))>>shift)-offset] += count
+	}
+	return expBuckets{offset: offset, counts: counts}
+}
+
+func (b expBuckets) at(index int32) uint64 {
+	i := index - b.offset
+	if i < 0 || int(i) >= len(b.counts) {
+		return 0
+	}
+	return b.counts[i]
+}
+
+// nonEmpty returns the range [lo, hi) of bucket indices between the first and the last non-empty buckets.
+func (b expBuckets) nonEmpty() (lo, hi int32) {
+	first, last := 0, len(b.counts)
+	for first < last && b.counts[first] == 0 {
+		first++
+	}
+	for last > first && b.counts[last-1] == 0 {
+		last--
+	}
+	return b.offset + int32(first), b.offset + int32(last)
+}
+
+// sub subtracts prev from b bucket by bucket. Both bucket ranges must have the same scale.
+// It returns false if any of the buckets decreased, which means the timeseries was reset.
+func (b expBuckets) sub(prev expBuckets) (expBuckets, bool) {
+	// Buckets of a cumulative histogram never decrease, so the non-empty buckets of prev must be
+	// in the range of b. This is checked before allocating, since ranges can be far apart after a reset.
+	if lo, hi := prev.nonEmpty(); lo < hi && (lo < b.offset || hi > b.offset+int32(len(b.counts))) {
+		return expBuckets{}, false
+	}
+	counts := make([]uint64, len(b.counts))
+	for i, cur := range b.counts {
+		old := prev.at(b.offset + int32(i))
+		if cur < old {
+			return expBuckets{}, false
+		}
+		counts[i] = cur - old
+	}
+	return expBuckets{offset: b.offset, counts: counts}, true
+}
+
+// expHistogramCounter keeps the buckets of a cumulative
+// exponential histogram at a given point in time
+type expHistogramCounter struct {
+	ts        uint64
+	startTs   uint64
+	scale     int32
+	zeroCount uint64
+	positive  expBuckets
+	negative  expBuckets
+}
+
+func newExpHistogramCounter(startTs, ts uint64, p pmetric.ExponentialHistogramDataPoint) expHistogramCounter {
+	return expHistogramCounter{
+		ts:        ts,
+		startTs:   startTs,
+		scale:     p.Scale(),
+		zeroCount: p.ZeroCount(),
+		positive:  newExpBuckets(p.Positive()),
+		negative:  newExpBuckets(p.Negative()),
+	}
+}
+
+// sub computes the exponential histogram of the values observed between prev and c.
+// If the scales differ, both histograms are downscaled to the smallest one before subtracting.
+// It returns false if a reset was detected.
+func (c expHistogramCounter) sub(prev expHistogramCounter) (pmetric.ExponentialHistogramDataPoint, bool) {
+	dp := pmetric.NewExponentialHistogramDataPoint()
+	if c.zeroCount < prev.zeroCount {
+		return dp, false
+	}
+
+	scale := min(c.scale, prev.scale)
+	positive, ok := c.positive.downscale(c.scale - scale).sub(prev.positive.downscale(prev.scale - scale))
+	if !ok {
+		return dp, false
+	}
+	negative, ok := c.negative.downscale(c.scale - scale).sub(prev.negative.downscale(prev.scale - scale))
+	if !ok {
+		return dp, false
+	}
+
+	count := c.zeroCount - prev.zeroCount
+	for _, n := range positive.counts {
+		count += n
+	}
+	for _, n := range negative.counts {
+		count += n
+	}
+
+	dp.SetStartTimestamp(pcommon.Timestamp(prev.ts))
+	dp.SetTimestamp(pcommon.Timestamp(c.ts))
+	dp.SetScale(scale)
+	dp.SetCount(count)
+	dp.SetZeroCount(c.zeroCount - prev.zeroCount)
+	dp.Positive().SetOffset(positive.offset)
+	dp.Positive().BucketCounts().FromRaw(positive.counts)
+	dp.Negative().SetOffset(negative.offset)
+	dp.Negative().BucketCounts().FromRaw(negative.counts)
+	return dp, true
+}
+
+// expHistogramKeySuffix is the suffix of the cache keys of exponential histograms.
+const expHistogramKeySuffix = "|exphist"
+
+// ExponentialHistogramDiff submits a new cumulative exponential histogram point for a given metric
+// and returns the delta exponential histogram with the last submitted point (ordered by timestamp).
+// Scale changes and offset shifts between points are accounted for. The delta point is only valid
+// if `ok` is true, that is, if the point is not the first one of the timeseries and no reset happened.
+// Otherwise, drop is the reason why the point has no delta, or empty for the first point of a timeseries.
+func (t *ttlCache) ExponentialHistogramDiff(
+	dimensions *Dimensions,
+	startTs, ts uint64,
+	p pmetric.ExponentialHistogramDataPoint,
+) (delta pmetric.ExponentialHistogramDataPoint, ok bool, drop dropReason) {
+	cur := newExpHistogramCounter(startTs, ts, p)
+
+	// The key has a suffix so that it cannot collide with a number timeseries with the same dimensions.
+	key := dimensions.String() + expHistogramKeySuffix
+	if c, found := t.cache.Get(key); found {
+		prev, isExpHistogram := c.(expHistogramCounter)
+		if isExpHistogram && prev.ts > ts {
+			// We were given a point older than the one in memory so we drop it
+			// We keep the existing point in memory since it is the most recent
+			return delta, false, dropReasonOutOfOrder
+		}
+		if isExpHistogram && isNotFirstPoint(startTs, ts, prev.startTs) {
+			// If any of the buckets decreased, there has been a reset. We cache the new point and ok is false.
+			delta, ok = cur.sub(prev)
+		}
+		if isExpHistogram && !ok {
+			drop = dropReasonReset
+		}
+	}
+
+	t.cache.Set(key, cur, gocache.DefaultExpiration)
+	return
+}
@@ -0,0 +1,168 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2023-present Datadog, Inc.
+
+package metrics
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"time"
+
+	gocache "github.com/patrickmn/go-cache"
+)
+
+// cacheSnapshotVersion is the version of the cache snapshot format.
+// It must be bumped on any backwards incompatible change to the format.
+const cacheSnapshotVersion = 1
+
+// cacheSnapshot is the serialized form of a ttlCache.
+type cacheSnapshot struct {
+	Version int                  `json:"version"`
+	Entries []cacheSnapshotEntry `json:"entries"`
+}
+
+// cacheSnapshotEntry is a single ttlCache entry, keyed by Dimensions.String().
+// Exactly one of Counter, Extrema or ExpHistogram is set.
+type cacheSnapshotEntry struct {
+	Key string `json:"key"`
+	// Expiration is the expiration time of the entry in nanoseconds since epoch, or zero if it never expires.
+	Expiration   int64               `json:"expiration,omitempty"`
+	Counter      *numberCounterEntry `json:"counter,omitempty"`
+	Extrema      *extremaEntry       `json:"extrema,omitempty"`
+	ExpHistogram *expHistogramEntry  `json:"exp_histogram,omitempty"`
+}
+
+type numberCounterEntry struct {
+	Ts      uint64  `json:"ts"`
+	StartTs uint64  `json:"start_ts"`
+	Value   float64 `json:"value"`
+}
+
+type extremaEntry struct {
+	Ts      uint64  `json:"ts"`
+	StartTs uint64  `json:"start_ts"`
+	Extrema float64 `json:"extrema"`
+}
+
+type expBucketsEntry struct {
+	Offset int32    `json:"offset"`
+	Counts []uint64 `json:"counts,omitempty"`
+}
+
+type expHistogramEntry struct {
+	Ts        uint64          `json:"ts"`
+	StartTs   uint64          `json:"start_ts"`
+	Scale     int32           `json:"scale"`
+	ZeroCount uint64          `json:"zero_count"`
+	Positive  expBucketsEntry `json:"positive"`
+	Negative  expBucketsEntry `json:"negative"`
+}
+
+// snapshot writes all the unexpired entries of the cache to w.
+// Counters and extrema with a non-finite value, such as a NaN minimum, cannot be encoded to JSON and are skipped:
+// the next point of their timeseries is handled as its first one.
+func (t *ttlCache) snapshot(w io.Writer) error {
+	items := t.cache.Items()
+	snapshot := cacheSnapshot{
+		Version: cacheSnapshotVersion,
+		Entries: make([]cacheSnapshotEntry, 0, len(items)),
+	}
+	for key, item := range items {
+		entry := cacheSnapshotEntry{Key: key, Expiration: item.Expiration}
+		switch v := item.Object.(type) {
+		case numberCounter:
+			if !isFinite(v.value) {
+				continue
+			}
+			entry.Counter = &numberCounterEntry{Ts: v.ts, StartTs: v.startTs, Value: v.value}
+		case extrema:
+			if !isFinite(v.storedExtrema) {
+				continue
+			}
+			entry.Extrema = &extremaEntry{Ts: v.ts, StartTs: v.startTs, Extrema: v.storedExtrema}
+		case expHistogramCounter:
+			entry.ExpHistogram = &expHistogramEntry{
+				Ts:        v.ts,
+				StartTs:   v.startTs,
+				Scale:     v.scale,
+				ZeroCount: v.zeroCount,
+				Positive:  expBucketsEntry{Offset: v.positive.offset, Counts: v.positive.counts},
+				Negative:  expBucketsEntry{Offset: v.negative.offset, Counts: v.negative.counts},
+			}
+		default:
+			return fmt.Errorf("unexpected cache entry type %T for key %q", v, key)
+		}
+		snapshot.Entries = append(snapshot.Entries, entry)
+	}
+
+	if err := json.NewEncoder(w).Encode(&snapshot); err != nil {
+		return fmt.Errorf("failed to encode cache snapshot: %w", err)
+	}
+	return nil
+}
+
+// isFinite reports whether f is neither NaN nor an infinity.
+func isFinite(f float64) bool {
+	return !math.IsNaN(f) && !math.IsInf(f, 0)
+}
+
+// decodeCacheSnapshot reads a cache snapshot written by ttlCache.snapshot from r.
+func decodeCacheSnapshot(r io.Reader) (*cacheSnapshot, error) {
+	var snapshot cacheSnapshot
+	if err := json.NewDecoder(r).Decode(&snapshot); err != nil {
+		return nil, fmt.Errorf("failed to decode cache snapshot: %w", err)
+	}
+	if snapshot.Version != cacheSnapshotVersion {
+		return nil, fmt.Errorf("unsupported cache snapshot version: %d", snapshot.Version)
+	}
+	for _, entry := range snapshot.Entries {
+		if entry.Counter == nil && entry.Extrema == nil && entry.ExpHistogram == nil {
+			return nil, fmt.Errorf("empty cache snapshot entry for key %q", entry.Key)
+		}
+	}
+	return &snapshot, nil
+}
+
+// restore adds the entries of a snapshot to the cache, keeping their original expiration.
+// Entries that have expired since the snapshot was taken are skipped.
+func (t *ttlCache) restore(snapshot *cacheSnapshot, now time.Time) {
+	for _, entry := range snapshot.Entries {
+		var d time.Duration
+		if entry.Expiration > 0 {
+			d = time.Unix(0, entry.Expiration).Sub(now)
+			if d <= 0 {
+				continue
+			}
+		} else {
+			d = gocache.NoExpiration
+		}
+
+		switch {
+		case entry.Counter != nil:
+			t.cache.Set(entry.Key, numberCounter{
+				ts:      entry.Counter.Ts,
+				startTs: entry.Counter.StartTs,
+				value:   entry.Counter.Value,
+			}, d)
+		case entry.Extrema != nil:
+			t.cache.Set(entry.Key, extrema{
+				ts:            entry.Extrema.Ts,
+				startTs:       entry.Extrema.StartTs,
+				storedExtrema: entry.Extrema.Extrema,
+			}, d)
+		case entry.ExpHistogram != nil:
+			t.cache.Set(entry.Key, expHistogramCounter{
+				ts:        entry.ExpHistogram.Ts,
+				startTs:   entry.ExpHistogram.StartTs,
+				scale:     entry.ExpHistogram.Scale,
+				zeroCount: entry.ExpHistogram.ZeroCount,
+				positive:  expBuckets{offset: entry.ExpHistogram.Positive.Offset, counts: entry.ExpHistogram.Positive.Counts},
+				negative:  expBuckets{offset: entry.ExpHistogram.Negative.Offset, counts: entry.ExpHistogram.Negative.Counts},
+			}, d)
+		}
+	}
+}
@@ -0,0 +1,151 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2023-present Datadog, Inc.
+
+package metrics
+
+import (
+	"bytes"
+	"context"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+)
+
+func TestCacheSnapshotRoundTrip(t *testing.T) {
+	cache := newTestCache()
+	counterDims := &Dimensions{name: "counter", tags: []string{"env:prod"}}
+	minDims := &Dimensions{name: "hist.min"}
+	expHistDims := &Dimensions{name: "exp.hist"}
+
+	cache.MonotonicDiff(counterDims, 1, 2, 10)
+	cache.PutAndCheckMin(minDims, 1, 2, -5)
+	cache.ExponentialHistogramDiff(expHistDims, 1, 2, newExpHistogramPoint(1, 2, 1, 1, -2, []uint64{1, 2}))
+
+	var buf bytes.Buffer
+	require.NoError(t, cache.snapshot(&buf))
+
+	snapshot, err := decodeCacheSnapshot(&buf)
+	require.NoError(t, err)
+	assert.Len(t, snapshot.Entries, 3)
+
+	restored := newTestCache()
+	restored.restore(snapshot, time.Now())
+	assert.Equal(t, cache.cache.ItemCount(), restored.cache.ItemCount())
+	for key, item := range cache.cache.Items() {
+		restoredItem, ok := restored.cache.Items()[key]
+		require.True(t, ok, "missing key %q", key)
+		assert.Equal(t, item.Object, restoredItem.Object)
+		// The expiration is recomputed from the current time on restore.
+		assert.InDelta(t, item.Expiration, restoredItem.Expiration, float64(time.Second))
+	}
+
+	// The restored cache keeps computing deltas where the original one stopped.
+	dx, firstPoint, dropPoint := restored.MonotonicDiff(counterDims, 1, 3, 15)
+	assert.Equal(t, 5.0, dx)
+	assert.False(t, firstPoint)
+	assert.False(t, dropPoint)
+
+	delta, ok, _ := restored.ExponentialHistogramDiff(expHistDims, 1, 3, newExpHistogramPoint(1, 3, 1, 1, -2, []uint64{2, 2}))
+	require.True(t, ok)
+	assert.Equal(t, []uint64{1, 0}, delta.Positive().BucketCounts().AsRaw())
+}
+
+func TestCacheSnapshotExpiredEntries(t *testing.T) {
+	cache := newTestCache()
+	cache.MonotonicDiff(dims, 1, 2, 10)
+
+	var buf bytes.Buffer
+	require.NoError(t, cache.snapshot(&buf))
+	snapshot, err := decodeCacheSnapshot(&buf)
+	require.NoError(t, err)
+
+	restored := newTestCache()
+	restored.restore(snapshot, time.Now().Add(2*time.Hour))
+	assert.Zero(t, restored.cache.ItemCount(), "entries expire after the default TTL of one hour")
+}
+
+func TestCacheSnapshotNonFiniteValues(t *testing.T) {
+	cache := newTestCache()
+	cache.PutAndCheckMin(&Dimensions{name: "hist.min"}, 1, 2, math.NaN())
+	cache.PutAndCheckMax(&Dimensions{name: "hist.max"}, 1, 2, math.Inf(1))
+	cache.MonotonicDiff(dims, 1, 2, 10)
+
+	var buf bytes.Buffer
+	require.NoError(t, cache.snapshot(&buf))
+	snapshot, err := decodeCacheSnapshot(&buf)
+	require.NoError(t, err)
+	require.Len(t, snapshot.Entries, 1, "non-finite extrema are skipped")
+	assert.NotNil(t, snapshot.Entries[0].Counter)
+}
+
+func TestDecodeCacheSnapshotErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		snapshot string
+		err      string
+	}{
+		{
+			name:     "invalid",
+			snapshot: "{",
+			err:      "failed to decode cache snapshot: unexpected EOF",
+		},
+		{
+			name:     "unsupported version",
+			snapshot: `{"version": 2, "entries": []}`,
+			err:      "unsupported cache snapshot version: 2",
+		},
+		{
+			name:     "empty entry",
+			snapshot: `{"version": 1, "entries": [{"key": "foo"}]}`,
+			err:      `empty cache snapshot entry for key "foo"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := decodeCacheSnapshot(strings.NewReader(tt.snapshot))
+			assert.EqualError(t, err, tt.err)
+		})
+	}
+}
+
+func TestTranslatorCacheSnapshot(t *testing.T) {
+	newMetrics := func(ts uint64, val float64) pmetric.Metrics {
+		md := pmetric.NewMetrics()
+		m := md.ResourceMetrics().AppendEmpty().ScopeMetrics().AppendEmpty().Metrics().AppendEmpty()
+		m.SetName("cumulative.monotonic.sum")
+		sum := m.SetEmptySum()
+		sum.SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
+		sum.SetIsMonotonic(true)
+		dp := sum.DataPoints().AppendEmpty()
+		dp.SetStartTimestamp(pcommon.Timestamp(1))
+		dp.SetTimestamp(pcommon.Timestamp(ts))
+		dp.SetDoubleValue(val)
+		return md
+	}
+
+	translator := NewTestTranslator(t, WithInitialCumulMonoValueMode(InitialCumulMonoValueModeDrop))
+	consumer := &mockFullConsumer{}
+	_, err := translator.MapMetrics(context.Background(), newMetrics(2, 10), consumer, nil)
+	require.NoError(t, err)
+	assert.Empty(t, consumer.metrics, "first point is dropped")
+
+	var buf bytes.Buffer
+	require.NoError(t, translator.SnapshotCache(&buf))
+
+	restarted := NewTestTranslator(t, WithInitialCumulMonoValueMode(InitialCumulMonoValueModeDrop), WithCacheSnapshot(&buf))
+	consumer = &mockFullConsumer{}
+	_, err = restarted.MapMetrics(context.Background(), newMetrics(3, 25), consumer, nil)
+	require.NoError(t, err)
+	require.Len(t, consumer.metrics, 1)
+	assert.Equal(t, Count, consumer.metrics[0].typ)
+	assert.Equal(t, 15.0, consumer.metrics[0].value)
+}
@@ -19,6 +19,8 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
 )
 
 func newTestCache() *ttlCache {
@@ -80,14 +82,16 @@ func TestMonotonicDiffUnknownStart(t *testing.T) {
 func TestDiffUnknownStart(t *testing.T) {
 	startTs := uint64(0) // equivalent to start being unset
 	prevPts := newTestCache()
-	_, ok := prevPts.Diff(dims, startTs, 1, 5)
+	_, ok, drop := prevPts.Diff(dims, startTs, 1, 5)
 	assert.False(t, ok, "expected no diff: first point")
-	_, ok = prevPts.Diff(dims, startTs, 0, 0)
+	assert.Empty(t, drop)
+	_, ok, drop = prevPts.Diff(dims, startTs, 0, 0)
 	assert.False(t, ok, "expected no diff: old point")
-	dx, ok := prevPts.Diff(dims, startTs, 2, 2)
+	assert.Equal(t, dropReasonOutOfOrder, drop)
+	dx, ok, _ := prevPts.Diff(dims, startTs, 2, 2)
 	assert.True(t, ok, "expected diff: no startTs, not monotonic")
 	assert.Equal(t, -3.0, dx, "expected diff -3.0 with (0,1,5) value")
-	dx, ok = prevPts.Diff(dims, startTs, 3, 4)
+	dx, ok, _ = prevPts.Diff(dims, startTs, 3, 4)
 	assert.True(t, ok, "expected diff: no startTs, old >= new")
 	assert.Equal(t, 2.0, dx, "expected diff 2.0 with (0,2,2) value")
 }
@@ -174,28 +178,32 @@ func TestMonotonicDiffKnownStart(t *testing.T) {
 func TestDiffKnownStart(t *testing.T) {
 	startTs := uint64(1)
 	prevPts := newTestCache()
-	_, ok := prevPts.Diff(dims, startTs, 1, 5)
+	_, ok, drop := prevPts.Diff(dims, startTs, 1, 5)
 	assert.False(t, ok, "expected no diff: first point")
-	_, ok = prevPts.Diff(dims, startTs, 0, 0)
+	assert.Empty(t, drop)
+	_, ok, drop = prevPts.Diff(dims, startTs, 0, 0)
 	assert.False(t, ok, "expected no diff: old point")
-	dx, ok := prevPts.Diff(dims, startTs, 2, 2)
+	assert.Equal(t, dropReasonOutOfOrder, drop)
+	dx, ok, _ := prevPts.Diff(dims, startTs, 2, 2)
 	assert.True(t, ok, "expected diff: same startTs, not monotonic")
 	assert.Equal(t, -3.0, dx, "expected diff -3.0 with (1,1,5) point")
-	dx, ok = prevPts.Diff(dims, startTs, 3, 4)
+	dx, ok, _ = prevPts.Diff(dims, startTs, 3, 4)
 	assert.True(t, ok, "expected diff: same startTs, not monotonic")
 	assert.Equal(t, 2.0, dx, "expected diff 2.0 with (0,2,2) value")
 
 	startTs = uint64(4) // simulate reset with startTs = ts
-	_, ok = prevPts.Diff(dims, startTs, startTs, 8)
+	_, ok, drop = prevPts.Diff(dims, startTs, startTs, 8)
 	assert.False(t, ok, "expected no diff: reset with unknown start")
-	dx, ok = prevPts.Diff(dims, startTs, 5, 9)
+	assert.Equal(t, dropReasonReset, drop)
+	dx, ok, _ = prevPts.Diff(dims, startTs, 5, 9)
 	assert.True(t, ok, "expected diff: same startTs, not monotonic")
 	assert.Equal(t, 1.0, dx, "expected diff 1.0 with (4,4,8) value")
 
 	startTs = uint64(6)
-	_, ok = prevPts.Diff(dims, startTs, 7, 1)
+	_, ok, drop = prevPts.Diff(dims, startTs, 7, 1)
 	assert.False(t, ok, "expected no diff: reset with known start")
-	dx, ok = prevPts.Diff(dims, startTs, 8, 10)
+	assert.Equal(t, dropReasonReset, drop)
+	dx, ok, _ = prevPts.Diff(dims, startTs, 8, 10)
 	assert.True(t, ok, "expected diff: same startTs, not monotonic")
 	assert.Equal(t, 9.0, dx, "expected diff 9.0 with (6,7,1) value")
 }
@@ -274,3 +282,111 @@ func TestPutAndGetExtrema(t *testing.T) {
 
 	}
 }
+
+func newExpHistogramPoint(startTs, ts uint64, scale int32, zeroCount uint64, offset int32, counts []uint64) pmetric.ExponentialHistogramDataPoint {
+	p := pmetric.NewExponentialHistogramDataPoint()
+	p.SetStartTimestamp(pcommon.Timestamp(startTs))
+	p.SetTimestamp(pcommon.Timestamp(ts))
+	p.SetScale(scale)
+	p.SetZeroCount(zeroCount)
+	p.Positive().SetOffset(offset)
+	p.Positive().BucketCounts().FromRaw(counts)
+	return p
+}
+
+func TestExponentialHistogramDiff(t *testing.T) {
+	prevPts := newTestCache()
+	startTs := uint64(1)
+
+	_, ok, drop := prevPts.ExponentialHistogramDiff(dims, startTs, 2, newExpHistogramPoint(startTs, 2, 1, 1, 2, []uint64{1, 2}))
+	assert.False(t, ok, "first point")
+	assert.Empty(t, drop)
+
+	// Offset shift: the delta covers the bucket range of the new point, which contains the previous one.
+	delta, ok, _ := prevPts.ExponentialHistogramDiff(dims, startTs, 3, newExpHistogramPoint(startTs, 3, 1, 2, 1, []uint64{1, 2, 3, 1}))
+	assert.True(t, ok)
+	assert.Equal(t, int32(1), delta.Scale())
+	assert.Equal(t, uint64(1), delta.ZeroCount())
+	assert.Equal(t, uint64(5), delta.Count())
+	assert.Equal(t, int32(1), delta.Positive().Offset())
+	assert.Equal(t, []uint64{1, 1, 1, 1}, delta.Positive().BucketCounts().AsRaw())
+	assert.Equal(t, pcommon.Timestamp(2), delta.StartTimestamp())
+
+	// Scale change: the previous point is downscaled before subtracting.
+	// At scale 0, the previous buckets are [1, 5, 1] with offset 0.
+	delta, ok, _ = prevPts.ExponentialHistogramDiff(dims, startTs, 4, newExpHistogramPoint(startTs, 4, 0, 2, -1, []uint64{1, 2, 7, 2}))
+	assert.True(t, ok)
+	assert.Equal(t, int32(0), delta.Scale())
+	assert.Equal(t, uint64(0), delta.ZeroCount())
+	assert.Equal(t, int32(-1), delta.Positive().Offset())
+	assert.Equal(t, []uint64{1, 1, 2, 1}, delta.Positive().BucketCounts().AsRaw())
+
+	_, ok, drop = prevPts.ExponentialHistogramDiff(dims, startTs, 3, newExpHistogramPoint(startTs, 3, 0, 3, -1, []uint64{1, 2, 8, 2}))
+	assert.False(t, ok, "older point")
+	assert.Equal(t, dropReasonOutOfOrder, drop)
+
+	_, ok, drop = prevPts.ExponentialHistogramDiff(dims, startTs, 5, newExpHistogramPoint(startTs, 5, 0, 2, 0, []uint64{1}))
+	assert.False(t, ok, "a bucket count decreased: there has been a reset")
+	assert.Equal(t, dropReasonReset, drop)
+
+	delta, ok, _ = prevPts.ExponentialHistogramDiff(dims, startTs, 6, newExpHistogramPoint(startTs, 6, 0, 2, 0, []uint64{3}))
+	assert.True(t, ok, "point after reset")
+	assert.Equal(t, []uint64{2}, delta.Positive().BucketCounts().AsRaw())
+
+	_, ok, drop = prevPts.ExponentialHistogramDiff(dims, 6, 7, newExpHistogramPoint(6, 7, 0, 2, 0, []uint64{4}))
+	assert.False(t, ok, "new start timestamp: there has been a reset")
+	assert.Equal(t, dropReasonReset, drop)
+}
+
+func TestExpBucketsDownscale(t *testing.T) {
+	b := expBuckets{offset: -3, counts: []uint64{1, 2, 3, 4, 5}}
+	// Indices -3..1 are merged into -2 (-3), -1 (-2, -1), 0 (0, 1).
+	assert.Equal(t, expBuckets{offset: -2, counts: []uint64{1, 5, 9}}, b.downscale(1))
+	// Indices -3..1 are merged into -1 (-3, -2, -1), 0 (0, 1).
+	assert.Equal(t, expBuckets{offset: -1, counts: []uint64{6, 9}}, b.downscale(2))
+	assert.Equal(t, b, b.downscale(0))
+}
+
+func TestExpBucketsSubReset(t *testing.T) {
+	cur := expBuckets{offset: 0, counts: []uint64{1, 2}}
+	// The previous buckets are far from the current ones: this is a reset, detected without
+	// allocating the buckets in between.
+	_, ok := cur.sub(expBuckets{offset: 1 << 30, counts: []uint64{1}})
+	assert.False(t, ok)
+	_, ok = cur.sub(expBuckets{offset: -1, counts: []uint64{1, 0}})
+	assert.False(t, ok)
+
+	// Empty buckets of the previous point out of the current range are ignored.
+	delta, ok := cur.sub(expBuckets{offset: -1, counts: []uint64{0, 1, 1, 0}})
+	assert.True(t, ok)
+	assert.Equal(t, expBuckets{offset: 0, counts: []uint64{0, 1}}, delta)
+}
+
+func TestCacheTypeCollision(t *testing.T) {
+	prevPts := newTestCache()
+	startTs := uint64(1)
+
+	// A sum and an exponential histogram with the same dimensions do not share a cache entry.
+	_, _, _ = prevPts.MonotonicDiff(dims, startTs, 2, 5)
+	_, ok, _ := prevPts.ExponentialHistogramDiff(dims, startTs, 2, newExpHistogramPoint(startTs, 2, 0, 0, 0, []uint64{1}))
+	assert.False(t, ok, "first point")
+	dx, firstPoint, dropPoint := prevPts.MonotonicDiff(dims, startTs, 3, 8)
+	assert.False(t, firstPoint)
+	assert.False(t, dropPoint)
+	assert.Equal(t, 3.0, dx)
+
+	// A cached value of another type is treated as a reset instead of panicking.
+	prevPts.PutAndCheckMin(dims, startTs, 4, 1)
+	assert.NotPanics(t, func() {
+		_, firstPoint, _ = prevPts.MonotonicDiff(dims, startTs, 5, 9)
+	})
+	assert.True(t, firstPoint)
+	assert.NotPanics(t, func() {
+		_, ok, _ = prevPts.Diff(dims, startTs, 6, 9)
+	})
+	assert.True(t, ok, "the previous point is the number counter cached by MonotonicDiff")
+	prevPts.cache.Set(dims.String(), numberCounter{ts: 6}, 0)
+	assert.NotPanics(t, func() {
+		prevPts.PutAndCheckMax(dims, startTs, 7, 1)
+	})
+}
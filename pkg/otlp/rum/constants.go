@@ -9,6 +9,13 @@ const (
 	InstrumentationScopeName = "datadog.rum-browser-sdk"
 	Type                     = "type"
 
+	// RUM event types (https://github.com/DataDog/rum-events-format/tree/master/schemas/rum)
+	ViewEventType     = "view"
+	ActionEventType   = "action"
+	ResourceEventType = "resource"
+	LongTaskEventType = "long_task"
+	ErrorEventType    = "error"
+
 	// _common-schema.json (https://github.com/DataDog/rum-events-format/blob/master/schemas/rum/_common-schema.json)
 	ServiceName    = "service.name"
 	ServiceVersion = "service.version"
@@ -0,0 +1,217 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025-present Datadog, Inc.
+
+package rum
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.uber.org/zap"
+)
+
+// maxErrorBodySize is the maximum number of bytes of a response body included in errors.
+const maxErrorBodySize = 1024
+
+// HTTPClient sends HTTP requests.
+type HTTPClient interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// ForwarderConfig configures a Forwarder.
+type ForwarderConfig struct {
+	// Workers is the maximum number of events sent concurrently.
+	Workers int
+	// MaxRetries is the number of times sending an event is retried after a retryable failure:
+	// a network error, or a 408, 429 or 5xx response.
+	MaxRetries int
+	// InitialBackoff is the wait before the first retry. It doubles after every retry.
+	InitialBackoff time.Duration
+	// MaxBackoff is the maximum wait between retries.
+	MaxBackoff time.Duration
+	// ValidatePayloads enables the validation of events before they are sent, see ValidatePayload.
+	// Invalid events are not sent: their error is a *PayloadError. It is disabled by default,
+	// so that events the intake would accept are not dropped.
+	ValidatePayloads bool
+}
+
+// DefaultForwarderConfig returns the default Forwarder configuration.
+func DefaultForwarderConfig() ForwarderConfig {
+	return ForwarderConfig{
+		Workers:        4,
+		MaxRetries:     3,
+		InitialBackoff: 100 * time.Millisecond,
+		MaxBackoff:     2 * time.Second,
+	}
+}
+
+// Validate the configuration.
+func (c ForwarderConfig) Validate() error {
+	if c.Workers <= 0 {
+		return fmt.Errorf("workers must be positive: %d", c.Workers)
+	}
+	if c.MaxRetries < 0 {
+		return fmt.Errorf("max retries must not be negative: %d", c.MaxRetries)
+	}
+	if c.InitialBackoff <= 0 {
+		return fmt.Errorf("initial backoff must be positive: %s", c.InitialBackoff)
+	}
+	if c.MaxBackoff < c.InitialBackoff {
+		return fmt.Errorf("max backoff (%s) must not be less than initial backoff (%s)", c.MaxBackoff, c.InitialBackoff)
+	}
+	return nil
+}
+
+// Event is a RUM event held by an OTLP log record.
+type Event struct {
+	// ResourceAttributes are the attributes of the resource of the log record.
+	ResourceAttributes pcommon.Map
+	// LogAttributes are the attributes of the log record.
+	LogAttributes pcommon.Map
+}
+
+// EventError is the error of an event that could not be forwarded.
+type EventError struct {
+	// Index is the index of the event in the forwarded events.
+	Index int
+	// Err is the error of the last attempt.
+	Err error
+}
+
+func (e EventError) Error() string {
+	return fmt.Sprintf("failed to forward RUM event %d: %v", e.Index, e.Err)
+}
+
+func (e EventError) Unwrap() error {
+	return e.Err
+}
+
+// Forwarder forwards RUM events held by OTLP log records to the Datadog RUM intake.
+type Forwarder struct {
+	client HTTPClient
+	cfg    ForwarderConfig
+	logger *zap.Logger
+}
+
+// NewForwarder returns a Forwarder sending requests with the given client.
+func NewForwarder(client HTTPClient, cfg ForwarderConfig, logger *zap.Logger) (*Forwarder, error) {
+	if client == nil {
+		return nil, errors.New("http client must not be nil")
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return &Forwarder{
+		client: client,
+		cfg:    cfg,
+		logger: logger,
+	}, nil
+}
+
+// Forward sends the events to the RUM intake at intakeURL (e.g. https://browser-intake-datadoghq.com)
+// and waits until all of them are sent. Events are sent concurrently by at most cfg.Workers workers,
+// and failed requests are retried with exponential backoff. If cfg.ValidatePayloads is true, invalid events are not sent.
+// It returns the errors of the events that could not be sent, which do not prevent sending the other events.
+func (f *Forwarder) Forward(ctx context.Context, intakeURL string, events []Event) []EventError {
+	var (
+		mu      sync.Mutex
+		errs    []EventError
+		wg      sync.WaitGroup
+		indexes = make(chan int)
+	)
+	for w := 0; w < min(f.cfg.Workers, len(events)); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				if err := f.forward(ctx, intakeURL, events[i]); err != nil {
+					mu.Lock()
+					errs = append(errs, EventError{Index: i, Err: err})
+					mu.Unlock()
+				}
+			}
+		}()
+	}
+	for i := range events {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+	return errs
+}
+
+// forward sends an event, with retries.
+func (f *Forwarder) forward(ctx context.Context, intakeURL string, event Event) error {
+	payload := ConstructRumPayloadFromOTLP(event.LogAttributes)
+	if f.cfg.ValidatePayloads {
+		if err := ValidatePayload(payload); err != nil {
+			return err
+		}
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal RUM payload: %w", err)
+	}
+	// The URL is built once so that retries have the same request ID.
+	url := intakeURL + BuildIntakeUrlPathAndParameters(event.ResourceAttributes, event.LogAttributes)
+
+	backoff := f.cfg.InitialBackoff
+	for attempt := 0; ; attempt++ {
+		retryable, err := f.send(ctx, url, body, event.LogAttributes)
+		if err == nil || !retryable || attempt == f.cfg.MaxRetries {
+			return err
+		}
+		f.logger.Debug("Retrying RUM event", zap.Int("attempt", attempt+1), zap.Duration("backoff", backoff), zap.Error(err))
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("%w (last error: %w)", ctx.Err(), err)
+		case <-time.After(backoff):
+		}
+		backoff = min(2*backoff, f.cfg.MaxBackoff)
+	}
+}
+
+// send makes a single attempt at sending an event. It reports whether a failure is retryable.
+func (f *Forwarder) send(ctx context.Context, url string, body []byte, lattrs pcommon.Map) (bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return false, fmt.Errorf("failed to create request: %w", err)
+	}
+	// add X-Forwarded-For header containing the request client IP address
+	if ip, ok := lattrs.Get("client.address"); ok {
+		req.Header.Add("X-Forwarded-For", ip.AsString())
+	}
+	req.Header.Set("Content-Type", "text/plain;charset=UTF-8")
+
+	resp, err := f.client.Do(req)
+	if err != nil {
+		// Errors caused by the context are not retried.
+		return ctx.Err() == nil, fmt.Errorf("failed to send request: %w", err)
+	}
+	var respBody []byte
+	if resp.Body != nil {
+		defer func() {
+			if cerr := resp.Body.Close(); cerr != nil {
+				f.logger.Error("failed to close response body", zap.Error(cerr))
+			}
+		}()
+		if respBody, err = io.ReadAll(io.LimitReader(resp.Body, maxErrorBodySize)); err != nil {
+			return true, fmt.Errorf("failed to read response: %w", err)
+		}
+	}
+	if resp.StatusCode == http.StatusOK || resp.StatusCode == http.StatusAccepted {
+		return false, nil
+	}
+	retryable := resp.StatusCode == http.StatusRequestTimeout || resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= http.StatusInternalServerError
+	return retryable, fmt.Errorf("received non-OK response: status: %d, body: %s", resp.StatusCode, string(respBody))
+}
@@ -0,0 +1,266 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025-present Datadog, Inc.
+
+package rum
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.uber.org/zap/zaptest"
+)
+
+func newTestEvent(t *testing.T, sessionID string) Event {
+	event := Event{
+		ResourceAttributes: pcommon.NewMap(),
+		LogAttributes:      pcommon.NewMap(),
+	}
+	require.NoError(t, event.LogAttributes.FromRaw(map[string]any{
+		"session.id":     sessionID,
+		"type":           "view",
+		"client.address": "1.2.3.4",
+	}))
+	return event
+}
+
+func newTestForwarder(t *testing.T, cfg ForwarderConfig) *Forwarder {
+	forwarder, err := NewForwarder(http.DefaultClient, cfg, zaptest.NewLogger(t))
+	require.NoError(t, err)
+	return forwarder
+}
+
+func testForwarderConfig() ForwarderConfig {
+	return ForwarderConfig{
+		Workers:        2,
+		MaxRetries:     2,
+		InitialBackoff: time.Millisecond,
+		MaxBackoff:     5 * time.Millisecond,
+	}
+}
+
+func TestForwarderConfigValidate(t *testing.T) {
+	assert.NoError(t, DefaultForwarderConfig().Validate())
+
+	cfg := testForwarderConfig()
+	cfg.Workers = 0
+	assert.EqualError(t, cfg.Validate(), "workers must be positive: 0")
+
+	cfg = testForwarderConfig()
+	cfg.MaxRetries = -1
+	assert.EqualError(t, cfg.Validate(), "max retries must not be negative: -1")
+
+	cfg = testForwarderConfig()
+	cfg.InitialBackoff = 0
+	assert.EqualError(t, cfg.Validate(), "initial backoff must be positive: 0s")
+
+	cfg = testForwarderConfig()
+	cfg.MaxBackoff = 0
+	assert.EqualError(t, cfg.Validate(), "max backoff (0s) must not be less than initial backoff (1ms)")
+
+	_, err := NewForwarder(nil, testForwarderConfig(), zaptest.NewLogger(t))
+	assert.EqualError(t, err, "http client must not be nil")
+}
+
+func TestForwarderForward(t *testing.T) {
+	var mu sync.Mutex
+	sessions := map[string]bool{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/api/v2/rum", r.URL.Path)
+		assert.Equal(t, "text/plain;charset=UTF-8", r.Header.Get("Content-Type"))
+		assert.Equal(t, "1.2.3.4", r.Header.Get("X-Forwarded-For"))
+		assert.Equal(t, "browser", r.URL.Query().Get("ddsource"))
+		assert.NotEmpty(t, r.URL.Query().Get("dd-request-id"))
+
+		var payload map[string]any
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&payload))
+		session, _ := payload["session"].(map[string]any)
+		mu.Lock()
+		sessions[session["id"].(string)] = true
+		mu.Unlock()
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	forwarder := newTestForwarder(t, testForwarderConfig())
+	errs := forwarder.Forward(context.Background(), server.URL, []Event{
+		newTestEvent(t, "a"),
+		newTestEvent(t, "b"),
+		newTestEvent(t, "c"),
+	})
+	assert.Empty(t, errs)
+	assert.Equal(t, map[string]bool{"a": true, "b": true, "c": true}, sessions)
+
+	// No events is a no-op.
+	assert.Empty(t, forwarder.Forward(context.Background(), server.URL, nil))
+}
+
+func TestForwarderRetries(t *testing.T) {
+	var attempts atomic.Int32
+	var requestIDs sync.Map
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestIDs.Store(r.URL.Query().Get("dd-request-id"), true)
+		if attempts.Add(1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	forwarder := newTestForwarder(t, testForwarderConfig())
+	errs := forwarder.Forward(context.Background(), server.URL, []Event{newTestEvent(t, "a")})
+	assert.Empty(t, errs)
+	assert.EqualValues(t, 3, attempts.Load())
+
+	// Retries have the same request ID.
+	var count int
+	requestIDs.Range(func(any, any) bool {
+		count++
+		return true
+	})
+	assert.Equal(t, 1, count)
+}
+
+func TestForwarderPerEventErrors(t *testing.T) {
+	var attempts sync.Map
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		var session string
+		switch {
+		case strings.Contains(string(body), `"id":"bad"`):
+			session = "bad"
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte("invalid event"))
+		case strings.Contains(string(body), `"id":"down"`):
+			session = "down"
+			w.WriteHeader(http.StatusInternalServerError)
+		default:
+			session = "ok"
+			w.WriteHeader(http.StatusAccepted)
+		}
+		count, _ := attempts.LoadOrStore(session, new(atomic.Int32))
+		count.(*atomic.Int32).Add(1)
+	}))
+	defer server.Close()
+
+	cfg := testForwarderConfig()
+	forwarder := newTestForwarder(t, cfg)
+	errs := forwarder.Forward(context.Background(), server.URL, []Event{
+		newTestEvent(t, "ok"),
+		newTestEvent(t, "bad"),
+		newTestEvent(t, "down"),
+		newTestEvent(t, "ok"),
+	})
+	require.Len(t, errs, 2)
+	byIndex := map[int]string{}
+	for _, err := range errs {
+		byIndex[err.Index] = err.Error()
+	}
+	assert.Equal(t, map[int]string{
+		1: "failed to forward RUM event 1: received non-OK response: status: 400, body: invalid event",
+		2: "failed to forward RUM event 2: received non-OK response: status: 500, body: ",
+	}, byIndex)
+
+	attemptsOf := func(session string) int32 {
+		count, ok := attempts.Load(session)
+		require.True(t, ok, session)
+		return count.(*atomic.Int32).Load()
+	}
+	assert.EqualValues(t, 2, attemptsOf("ok"))
+	// Client errors are not retried.
+	assert.EqualValues(t, 1, attemptsOf("bad"))
+	assert.EqualValues(t, cfg.MaxRetries+1, attemptsOf("down"))
+}
+
+func TestForwarderWorkers(t *testing.T) {
+	var inFlight, maxInFlight atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		n := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			m := maxInFlight.Load()
+			if n <= m || maxInFlight.CompareAndSwap(m, n) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	cfg := testForwarderConfig()
+	cfg.Workers = 3
+	var events []Event
+	for i := 0; i < 12; i++ {
+		events = append(events, newTestEvent(t, "a"))
+	}
+	assert.Empty(t, newTestForwarder(t, cfg).Forward(context.Background(), server.URL, events))
+	assert.LessOrEqual(t, maxInFlight.Load(), int32(cfg.Workers))
+	assert.Greater(t, maxInFlight.Load(), int32(1))
+}
+
+func TestForwarderContextCanceled(t *testing.T) {
+	var attempts atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		attempts.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	cfg := testForwarderConfig()
+	cfg.MaxRetries = 100
+	cfg.InitialBackoff = time.Hour
+	cfg.MaxBackoff = time.Hour
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	errs := newTestForwarder(t, cfg).Forward(ctx, server.URL, []Event{newTestEvent(t, "a")})
+	require.Len(t, errs, 1)
+	assert.ErrorIs(t, errs[0], context.DeadlineExceeded)
+	assert.EqualValues(t, 1, attempts.Load())
+}
+
+func TestForwarderValidatePayloads(t *testing.T) {
+	var attempts atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		attempts.Add(1)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	valid := newTestEvent(t, "valid")
+	valid.LogAttributes.PutInt("datadog.date", 1700000000000)
+	valid.LogAttributes.PutStr("datadog.application.id", "app")
+	valid.LogAttributes.PutStr("datadog.view.id", "view")
+	valid.LogAttributes.PutInt("datadog.view.time_spent", 1000)
+	valid.LogAttributes.PutInt("datadog.view.action.count", 0)
+	valid.LogAttributes.PutInt("datadog.view.error.count", 0)
+	valid.LogAttributes.PutInt("datadog.view.resource.count", 0)
+	valid.LogAttributes.PutInt("datadog._dd.document_version", 1)
+
+	cfg := testForwarderConfig()
+	cfg.ValidatePayloads = true
+	errs := newTestForwarder(t, cfg).Forward(context.Background(), server.URL, []Event{valid, newTestEvent(t, "invalid")})
+	require.Len(t, errs, 1)
+	assert.Equal(t, 1, errs[0].Index)
+	var payloadErr *PayloadError
+	require.ErrorAs(t, errs[0], &payloadErr)
+	assert.Equal(t, ViewEventType, payloadErr.EventType)
+	assert.ErrorIs(t, errs[0], ErrMissingField)
+	// Invalid events are not sent.
+	assert.EqualValues(t, 1, attempts.Load())
+}
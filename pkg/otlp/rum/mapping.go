@@ -5,6 +5,7 @@
 
 package rum
 
+// OTLPAttributeToRUMPayloadKeyMapping maps OTLP attributes to the RUM payload keys common to all event types.
 var OTLPAttributeToRUMPayloadKeyMapping = map[string]string{
 	// _common-schema.json (https://github.com/DataDog/rum-events-format/blob/master/schemas/rum/_common-schema.json)
 	ServiceName:    Service,
@@ -16,23 +17,164 @@ var OTLPAttributeToRUMPayloadKeyMapping = map[string]string{
 	UserHash:       UsrAnonymousId,
 	UserName:       AccountName,
 
+	"screen.name": "view.name",
+
+	"os.name":     "os.name",
+	"os.version":  "os.version",
+	"os.build_id": "os.build",
+
+	"device.model.identifier": "device.model",
+	"device.model.name":       "device.name",
+	"device.manufacturer":     "device.brand",
+	"browser.language":        "device.locale",
+
+	"geo.country.iso_code": "geo.country_iso_code",
+	"geo.locality.name":    "geo.city",
+	"geo.continent.code":   "geo.continent_code",
+
+	"network.carrier.name":       "connectivity.cellular.carrier_name",
+	"network.connection.subtype": "connectivity.cellular.technology",
+
 	// error-schema.json (https://github.com/DataDog/rum-events-format/blob/master/schemas/rum/error-schema.json)
 	ErrorMessage: ErrorMessage,
 	ErrorType:    ErrorType,
 }
 
-var RUMPayloadKeyToOTLPAttributeMapping = map[string]string{
-	// _common-schema.json
-	Service:        ServiceName,
-	Version:        ServiceVersion,
-	SessionId:      SessionId,
-	UsrId:          UserId,
-	UsrName:        UserFullName,
-	UsrEmail:       UserEmail,
-	UsrAnonymousId: UserHash,
-	AccountName:    UserName,
+// RUMPayloadKeyToOTLPAttributeMapping maps the RUM payload keys common to all event types to OTLP attributes.
+var RUMPayloadKeyToOTLPAttributeMapping = invertMapping(OTLPAttributeToRUMPayloadKeyMapping)
 
-	// error-schema.json
-	ErrorMessage: ErrorMessage,
-	ErrorType:    ErrorType,
+// OTLPAttributeToRUMPayloadKeyMappingByType maps OTLP attributes to RUM payload keys, by RUM event type.
+// They take precedence over OTLPAttributeToRUMPayloadKeyMapping. Fields without OpenTelemetry semantic
+// conventions, such as timings, are mapped to attributes of the same name.
+var OTLPAttributeToRUMPayloadKeyMappingByType = map[string]map[string]string{
+	// view-schema.json (https://github.com/DataDog/rum-events-format/blob/master/schemas/rum/view-schema.json)
+	ViewEventType: {
+		"url.full":                       "view.url",
+		"view.id":                        "view.id",
+		"view.referrer":                  "view.referrer",
+		"view.loading_type":              "view.loading_type",
+		"view.loading_time":              "view.loading_time",
+		"view.time_spent":                "view.time_spent",
+		"view.is_active":                 "view.is_active",
+		"view.first_byte":                "view.first_byte",
+		"view.first_contentful_paint":    "view.first_contentful_paint",
+		"view.largest_contentful_paint":  "view.largest_contentful_paint",
+		"view.first_input_delay":         "view.first_input_delay",
+		"view.interaction_to_next_paint": "view.interaction_to_next_paint",
+		"view.cumulative_layout_shift":   "view.cumulative_layout_shift",
+		"view.dom_interactive":           "view.dom_interactive",
+		"view.dom_content_loaded":        "view.dom_content_loaded",
+		"view.dom_complete":              "view.dom_complete",
+		"view.load_event":                "view.load_event",
+		"view.action.count":              "view.action.count",
+		"view.error.count":               "view.error.count",
+		"view.resource.count":            "view.resource.count",
+		"view.long_task.count":           "view.long_task.count",
+	},
+	// action-schema.json (https://github.com/DataDog/rum-events-format/blob/master/schemas/rum/action-schema.json)
+	ActionEventType: {
+		"app.widget.name":     "action.target.name",
+		"action.id":           "action.id",
+		"action.type":         "action.type",
+		"action.loading_time": "action.loading_time",
+		"action.error.count":  "action.error.count",
+	},
+	// resource-schema.json (https://github.com/DataDog/rum-events-format/blob/master/schemas/rum/resource-schema.json)
+	ResourceEventType: {
+		"http.request.method":        "resource.method",
+		"url.full":                   "resource.url",
+		"http.response.status_code":  "resource.status_code",
+		"http.response.body.size":    "resource.encoded_body_size",
+		"http.response.size":         "resource.transfer_size",
+		"resource.id":                "resource.id",
+		"resource.type":              "resource.type",
+		"resource.duration":          "resource.duration",
+		"resource.size":              "resource.size",
+		"resource.decoded_body_size": "resource.decoded_body_size",
+	},
+	// long_task-schema.json (https://github.com/DataDog/rum-events-format/blob/master/schemas/rum/long_task-schema.json)
+	LongTaskEventType: {
+		"long_task.id":              "long_task.id",
+		"long_task.entry_type":      "long_task.entry_type",
+		"long_task.duration":        "long_task.duration",
+		"long_task.is_frozen_frame": "long_task.is_frozen_frame",
+	},
+	// error-schema.json (https://github.com/DataDog/rum-events-format/blob/master/schemas/rum/error-schema.json)
+	ErrorEventType: {
+		"exception.stacktrace":      "error.stack",
+		"http.request.method":       "error.resource.method",
+		"url.full":                  "error.resource.url",
+		"http.response.status_code": "error.resource.status_code",
+		"error.id":                  "error.id",
+		"error.source":              "error.source",
+		"error.is_crash":            "error.is_crash",
+	},
+}
+
+// RUMPayloadKeyToOTLPAttributeMappingByType maps RUM payload keys to OTLP attributes, by RUM event type.
+// They take precedence over RUMPayloadKeyToOTLPAttributeMapping.
+var RUMPayloadKeyToOTLPAttributeMappingByType = func() map[string]map[string]string {
+	byType := make(map[string]map[string]string, len(OTLPAttributeToRUMPayloadKeyMappingByType))
+	for eventType, mapping := range OTLPAttributeToRUMPayloadKeyMappingByType {
+		byType[eventType] = invertMapping(mapping)
+	}
+	return byType
+}()
+
+// otlpAttributeAliases maps alternative OTLP attributes to the RUM payload keys common to all event types.
+// Unlike the other mappings, they are only used from OTLP to RUM.
+var otlpAttributeAliases = map[string]string{
+	// The platform of a browser is the name of its operating system, e.g. "macOS".
+	"browser.platform": "os.name",
+}
+
+// otlpAttributeAliasesByType maps deprecated or alternative OTLP attributes to RUM payload keys, by RUM event type.
+// Unlike the other mappings, they are only used from OTLP to RUM.
+var otlpAttributeAliasesByType = map[string]map[string]string{
+	ResourceEventType: {
+		"http.method":                  "resource.method",
+		"http.url":                     "resource.url",
+		"http.status_code":             "resource.status_code",
+		"http.response_content_length": "resource.encoded_body_size",
+	},
+	ErrorEventType: {
+		"exception.message": ErrorMessage,
+		"exception.type":    ErrorType,
+		"http.method":       "error.resource.method",
+		"http.url":          "error.resource.url",
+		"http.status_code":  "error.resource.status_code",
+	},
+}
+
+// rumPayloadKey returns the RUM payload key of an OTLP attribute of an event of the given type.
+func rumPayloadKey(eventType string, attr string) (string, bool) {
+	if key, ok := OTLPAttributeToRUMPayloadKeyMappingByType[eventType][attr]; ok {
+		return key, true
+	}
+	if key, ok := OTLPAttributeToRUMPayloadKeyMapping[attr]; ok {
+		return key, true
+	}
+	if key, ok := otlpAttributeAliasesByType[eventType][attr]; ok {
+		return key, true
+	}
+	key, ok := otlpAttributeAliases[attr]
+	return key, ok
+}
+
+// otlpAttribute returns the OTLP attribute of a RUM payload key of an event of the given type.
+func otlpAttribute(eventType string, key string) (string, bool) {
+	if attr, ok := RUMPayloadKeyToOTLPAttributeMappingByType[eventType][key]; ok {
+		return attr, true
+	}
+	attr, ok := RUMPayloadKeyToOTLPAttributeMapping[key]
+	return attr, ok
+}
+
+// invertMapping inverts a mapping, which must not map two keys to the same value.
+func invertMapping(mapping map[string]string) map[string]string {
+	inverted := make(map[string]string, len(mapping))
+	for k, v := range mapping {
+		inverted[v] = k
+	}
+	return inverted
 }
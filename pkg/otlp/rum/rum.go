@@ -10,6 +10,7 @@ import (
 	"encoding/binary"
 	"encoding/hex"
 	"fmt"
+	"math/big"
 	"net/url"
 	"strconv"
 	"strings"
@@ -64,10 +65,13 @@ func buildRumPayload(k string, v pcommon.Value, rumPayload map[string]any) {
 	}
 }
 
+// ConstructRumPayloadFromOTLP builds the RUM payload of the RUM event held by OTLP attributes.
+// Attributes are mapped according to the type of the event, in the `type` or `datadog.type` attribute.
 func ConstructRumPayloadFromOTLP(attr pcommon.Map) map[string]any {
 	rumPayload := make(map[string]any)
+	eventType := eventTypeFromOTLP(attr)
 	attr.Range(func(k string, v pcommon.Value) bool {
-		if rumAttributeName, exists := OTLPAttributeToRUMPayloadKeyMapping[k]; exists {
+		if rumAttributeName, exists := rumPayloadKey(eventType, k); exists {
 			buildRumPayload(rumAttributeName, v, rumPayload)
 			return true
 		}
@@ -79,11 +83,58 @@ func ConstructRumPayloadFromOTLP(attr pcommon.Map) map[string]any {
 	return rumPayload
 }
 
+// eventTypeFromOTLP returns the type of the RUM event held by OTLP attributes.
+func eventTypeFromOTLP(attr pcommon.Map) string {
+	for _, k := range []string{Type, "datadog." + Type} {
+		if v, ok := attr.Get(k); ok && v.Type() == pcommon.ValueTypeStr {
+			return v.Str()
+		}
+	}
+	return ""
+}
+
 type RUMPayload struct {
 	Type string
 }
 
+// isFinalViewUpdate reports whether a view event is the final update of its view, whose `view.is_active`
+// is false. Views send updates with the same view ID while they are active, each holding the view so far.
+func isFinalViewUpdate(payload map[string]any) bool {
+	isActive, ok := lookupField(payload, "view.is_active")
+	return ok && isActive == any(false)
+}
+
+// traceIDHighFields are the fields of a RUM payload that can hold the high 64 bits of the trace ID,
+// as 16 hex characters, when `_dd.trace_id` only holds the low 64 bits.
+var traceIDHighFields = []string{"_dd.trace_id_high", "_dd.p.tid"}
+
+// traceparentFields are the fields of a RUM payload that can hold the W3C traceparent header
+// (https://www.w3.org/TR/trace-context/#traceparent-header) propagated by a resource.
+var traceparentFields = []string{"_dd.traceparent", "resource.traceparent"}
+
+// hasTraceContext reports whether a RUM payload holds the IDs of a trace.
+func hasTraceContext(payload map[string]any) bool {
+	if _, ok := lookupField(payload, "_dd.trace_id"); ok {
+		return true
+	}
+	for _, field := range traceparentFields {
+		if stringField(payload, field) != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// parseIDs returns the trace and span IDs of a RUM event, from its traceparent if it has one,
+// or else from `_dd.trace_id` and `_dd.span_id`. The trace ID is a 128-bit ID if `_dd.trace_id` is
+// one, or if the high 64 bits are in `_dd.trace_id_high` or `_dd.p.tid`.
 func parseIDs(payload map[string]any) (pcommon.TraceID, pcommon.SpanID, error) {
+	for _, field := range traceparentFields {
+		if traceparent := stringField(payload, field); traceparent != "" {
+			return parseTraceparent(traceparent)
+		}
+	}
+
 	ddMetadata, ok := payload["_dd"].(map[string]any)
 	if !ok {
 		return pcommon.NewTraceIDEmpty(), pcommon.NewSpanIDEmpty(), fmt.Errorf("failed to find _dd metadata in payload")
@@ -93,21 +144,101 @@ func parseIDs(payload map[string]any) (pcommon.TraceID, pcommon.SpanID, error) {
 	if !ok {
 		return pcommon.NewTraceIDEmpty(), pcommon.NewSpanIDEmpty(), fmt.Errorf("failed to retrieve traceID from payload")
 	}
-	traceID, err := strconv.ParseUint(traceIDString, 10, 64)
+	traceID, err := parseTraceID(traceIDString)
 	if err != nil {
 		return pcommon.NewTraceIDEmpty(), pcommon.NewSpanIDEmpty(), fmt.Errorf("failed to parse traceID: %w", err)
 	}
+	if binary.BigEndian.Uint64(traceID[:8]) == 0 {
+		for _, field := range traceIDHighFields {
+			high := stringField(payload, field)
+			if high == "" {
+				continue
+			}
+			if len(high) != 16 {
+				return pcommon.NewTraceIDEmpty(), pcommon.NewSpanIDEmpty(), fmt.Errorf("failed to parse traceID high bits: %q is not 16 hex characters", high)
+			}
+			if _, err := hex.Decode(traceID[:8], []byte(high)); err != nil {
+				return pcommon.NewTraceIDEmpty(), pcommon.NewSpanIDEmpty(), fmt.Errorf("failed to parse traceID high bits: %w", err)
+			}
+			break
+		}
+	}
 
 	spanIDString, ok := ddMetadata["span_id"].(string)
 	if !ok {
 		return pcommon.NewTraceIDEmpty(), pcommon.NewSpanIDEmpty(), fmt.Errorf("failed to retrieve spanID from payload")
 	}
-	spanID, err := strconv.ParseUint(spanIDString, 10, 64)
+	spanID, err := parseSpanID(spanIDString)
 	if err != nil {
 		return pcommon.NewTraceIDEmpty(), pcommon.NewSpanIDEmpty(), fmt.Errorf("failed to parse spanID: %w", err)
 	}
 
-	return uInt64ToTraceID(0, traceID), uInt64ToSpanID(spanID), nil
+	return traceID, spanID, nil
+}
+
+// parseTraceID parses a trace ID of 32 hex characters, a decimal number of up to 128 bits,
+// or 16 hex characters, in this order. This is the rule used for trace IDs of logs: a string of 16 digits
+// is both a valid decimal and a valid hex ID, and is read as a decimal number since Datadog tracers send
+// 64-bit trace IDs in decimal.
+func parseTraceID(s string) (pcommon.TraceID, error) {
+	var traceID pcommon.TraceID
+	if len(s) == hex.EncodedLen(len(traceID)) {
+		if _, err := hex.Decode(traceID[:], []byte(s)); err == nil {
+			return traceID, nil
+		}
+	}
+	if isDecimal(s) {
+		if n, ok := new(big.Int).SetString(s, 10); ok && n.BitLen() <= 128 {
+			n.FillBytes(traceID[:])
+			return traceID, nil
+		}
+	}
+	if len(s) == 16 {
+		if _, err := hex.Decode(traceID[8:], []byte(s)); err == nil {
+			return traceID, nil
+		}
+	}
+	return pcommon.NewTraceIDEmpty(), fmt.Errorf("%q is not 32 or 16 hex characters, or a 128-bit decimal number", s)
+}
+
+// isDecimal reports whether s is a non-empty string of digits, without sign.
+func isDecimal(s string) bool {
+	return s != "" && strings.TrimLeft(s, "0123456789") == ""
+}
+
+// parseSpanID parses a span ID that is a decimal number of up to 64 bits, or 16 hex characters.
+func parseSpanID(s string) (pcommon.SpanID, error) {
+	if n, err := strconv.ParseUint(s, 10, 64); err == nil {
+		return uInt64ToSpanID(n), nil
+	}
+	var spanID pcommon.SpanID
+	if len(s) == hex.EncodedLen(len(spanID)) {
+		if _, err := hex.Decode(spanID[:], []byte(s)); err == nil {
+			return spanID, nil
+		}
+	}
+	return pcommon.NewSpanIDEmpty(), fmt.Errorf("%q is not 16 hex characters or a 64-bit decimal number", s)
+}
+
+// parseTraceparent returns the trace ID and the parent span ID of a W3C traceparent header,
+// e.g. `00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01`.
+func parseTraceparent(traceparent string) (pcommon.TraceID, pcommon.SpanID, error) {
+	var (
+		traceID pcommon.TraceID
+		spanID  pcommon.SpanID
+	)
+	parts := strings.Split(traceparent, "-")
+	if len(parts) < 4 || len(parts[0]) != 2 || parts[0] == "ff" || (parts[0] == "00" && len(parts) != 4) ||
+		len(parts[1]) != hex.EncodedLen(len(traceID)) || len(parts[2]) != hex.EncodedLen(len(spanID)) {
+		return pcommon.NewTraceIDEmpty(), pcommon.NewSpanIDEmpty(), fmt.Errorf("invalid traceparent %q", traceparent)
+	}
+	if _, err := hex.Decode(traceID[:], []byte(parts[1])); err != nil || traceID.IsEmpty() {
+		return pcommon.NewTraceIDEmpty(), pcommon.NewSpanIDEmpty(), fmt.Errorf("invalid traceparent trace ID %q", parts[1])
+	}
+	if _, err := hex.Decode(spanID[:], []byte(parts[2])); err != nil || spanID.IsEmpty() {
+		return pcommon.NewTraceIDEmpty(), pcommon.NewSpanIDEmpty(), fmt.Errorf("invalid traceparent parent ID %q", parts[2])
+	}
+	return traceID, spanID, nil
 }
 
 func parseDDForwardIntoResource(attributes pcommon.Map, ddforward string) {
@@ -187,9 +318,10 @@ func flattenJSON(payload map[string]any) map[string]any {
 	return flat
 }
 
-func setOTLPAttributes(flatPayload map[string]any, attributes pcommon.Map) {
+// setOTLPAttributes sets the OTLP attributes of a flattened RUM payload of the given event type.
+func setOTLPAttributes(flatPayload map[string]any, attributes pcommon.Map, eventType string) {
 	for key, val := range flatPayload {
-		rumKey, exists := RUMPayloadKeyToOTLPAttributeMapping[key]
+		rumKey, exists := otlpAttribute(eventType, key)
 
 		if !exists {
 			rumKey = "datadog" + "." + key
@@ -204,17 +336,17 @@ func setOTLPAttributes(flatPayload map[string]any, attributes pcommon.Map) {
 			attributes.PutDouble(rumKey, v)
 		case map[string]any:
 			objVal := attributes.PutEmptyMap(rumKey)
-			setOTLPAttributes(v, objVal)
+			setOTLPAttributes(v, objVal, eventType)
 		case []any:
 			arrVal := attributes.PutEmptySlice(rumKey)
-			appendToOTLPSlice(arrVal, v)
+			appendToOTLPSlice(arrVal, v, eventType)
 		default:
 			attributes.PutStr(rumKey, fmt.Sprintf("%v", v))
 		}
 	}
 }
 
-func appendToOTLPSlice(slice pcommon.Slice, val any) {
+func appendToOTLPSlice(slice pcommon.Slice, val any, eventType string) {
 	switch v := val.(type) {
 	case string:
 		slice.AppendEmpty().SetStr(v)
@@ -224,11 +356,11 @@ func appendToOTLPSlice(slice pcommon.Slice, val any) {
 		slice.AppendEmpty().SetDouble(v)
 	case map[string]any:
 		elemMap := slice.AppendEmpty().SetEmptyMap()
-		setOTLPAttributes(v, elemMap)
+		setOTLPAttributes(v, elemMap, eventType)
 	case []any:
 		subSlice := slice.AppendEmpty().SetEmptySlice()
 		for _, inner := range v {
-			appendToOTLPSlice(subSlice, inner)
+			appendToOTLPSlice(subSlice, inner, eventType)
 		}
 	default:
 		slice.AppendEmpty().SetStr(fmt.Sprintf("%v", val))
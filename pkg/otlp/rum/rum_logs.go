@@ -21,7 +21,8 @@ func ToLogs(payload map[string]any, req *http.Request) plog.Logs {
 
 	flatPayload := flattenJSON(payload)
 
-	setOTLPAttributes(flatPayload, newLogRecord.Attributes())
+	eventType, _ := payload[Type].(string)
+	setOTLPAttributes(flatPayload, newLogRecord.Attributes(), eventType)
 
 	return results
 }
@@ -0,0 +1,178 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025-present Datadog, Inc.
+
+package rum
+
+import (
+	"net/http"
+	"sort"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	semconv "go.opentelemetry.io/otel/semconv/v1.5.0"
+)
+
+// viewMetric is a histogram metric recording a field of view events.
+type viewMetric struct {
+	name        string
+	description string
+	unit        string
+	field       string
+	// divisor converts the value of the field to the unit of the metric.
+	divisor float64
+	// bounds are the explicit bounds of the histogram buckets.
+	bounds []float64
+}
+
+// nanosecondsPerSecond converts durations in nanoseconds, the unit of RUM durations, to seconds.
+const nanosecondsPerSecond = 1e9
+
+// countBounds are the bounds of the histograms of counts of events in a view.
+var countBounds = []float64{0, 1, 2, 5, 10, 20, 50, 100, 200, 500}
+
+// viewMetrics are the metrics recorded for view events. Bounds of web vitals include the thresholds
+// between good, needs improvement and poor (https://web.dev/articles/vitals).
+var viewMetrics = []viewMetric{
+	{
+		name:        "datadog.rum.view.largest_contentful_paint",
+		description: "Time until the largest content of the view is rendered (LCP).",
+		unit:        "s",
+		field:       "view.largest_contentful_paint",
+		divisor:     nanosecondsPerSecond,
+		bounds:      []float64{0.5, 1, 1.5, 2, 2.5, 3, 4, 6, 10, 20},
+	},
+	{
+		name:        "datadog.rum.view.first_contentful_paint",
+		description: "Time until the first content of the view is rendered (FCP).",
+		unit:        "s",
+		field:       "view.first_contentful_paint",
+		divisor:     nanosecondsPerSecond,
+		bounds:      []float64{0.5, 1, 1.5, 1.8, 2.5, 3, 4, 6, 10, 20},
+	},
+	{
+		name:        "datadog.rum.view.cumulative_layout_shift",
+		description: "Cumulative layout shift of the view (CLS).",
+		unit:        "1",
+		field:       "view.cumulative_layout_shift",
+		divisor:     1,
+		bounds:      []float64{0.01, 0.05, 0.1, 0.15, 0.25, 0.5, 1},
+	},
+	{
+		name:        "datadog.rum.view.interaction_to_next_paint",
+		description: "Longest time between an interaction with the view and the next paint (INP).",
+		unit:        "s",
+		field:       "view.interaction_to_next_paint",
+		divisor:     nanosecondsPerSecond,
+		bounds:      []float64{0.05, 0.1, 0.2, 0.3, 0.5, 1, 2, 5},
+	},
+	{
+		name:        "datadog.rum.view.loading_time",
+		description: "Time until the view is ready.",
+		unit:        "s",
+		field:       "view.loading_time",
+		divisor:     nanosecondsPerSecond,
+		bounds:      []float64{0.5, 1, 2, 3, 5, 10, 20, 60},
+	},
+	{
+		name:        "datadog.rum.view.error.count",
+		description: "Number of errors in the view.",
+		unit:        "{error}",
+		field:       "view.error.count",
+		divisor:     1,
+		bounds:      countBounds,
+	},
+	{
+		name:        "datadog.rum.view.resource.count",
+		description: "Number of resources loaded by the view.",
+		unit:        "{resource}",
+		field:       "view.resource.count",
+		divisor:     1,
+		bounds:      countBounds,
+	},
+	{
+		name:        "datadog.rum.view.action.count",
+		description: "Number of actions in the view.",
+		unit:        "{action}",
+		field:       "view.action.count",
+		divisor:     1,
+		bounds:      countBounds,
+	},
+}
+
+// viewMetricAttributes are the RUM payload keys of view events recorded as data point attributes.
+var viewMetricAttributes = []string{Service, Version, "view.name"}
+
+// ToMetrics maps a view event to delta histograms of its web vitals, its loading time and its counts of
+// errors, resources and actions, with a data point per field of the view. Only the final update of a view,
+// whose `view.is_active` is false, is recorded, so that a view is counted once however many updates it has:
+// other events, and updates of views that are active or that do not report whether they are, have no metrics.
+func ToMetrics(payload map[string]any, req *http.Request) pmetric.Metrics {
+	results := pmetric.NewMetrics()
+	if eventType, _ := payload[Type].(string); eventType != ViewEventType {
+		return results
+	}
+	if !isFinalViewUpdate(payload) {
+		return results
+	}
+
+	rm := results.ResourceMetrics().AppendEmpty()
+	rm.SetSchemaUrl(semconv.SchemaURL)
+	rm.Resource().Attributes().PutStr(string(semconv.ServiceNameKey), "browser-rum-sdk")
+	parseDDForwardIntoResource(rm.Resource().Attributes(), req.URL.Query().Get("ddforward"))
+
+	sm := rm.ScopeMetrics().AppendEmpty()
+	sm.Scope().SetName(InstrumentationScopeName)
+
+	var start, end pcommon.Timestamp
+	if date, ok := numberField(payload, "date"); ok && date >= 0 {
+		start = millisecondsToTimestamp(date)
+		end = start
+		if timeSpent, ok := numberField(payload, "view.time_spent"); ok && timeSpent > 0 {
+			end += pcommon.Timestamp(timeSpent)
+		}
+	}
+
+	attributes := pcommon.NewMap()
+	for _, key := range viewMetricAttributes {
+		if value := stringField(payload, key); value != "" {
+			attr, _ := otlpAttribute(ViewEventType, key)
+			attributes.PutStr(attr, value)
+		}
+	}
+
+	for _, m := range viewMetrics {
+		value, ok := numberField(payload, m.field)
+		if !ok {
+			continue
+		}
+		value /= m.divisor
+
+		metric := sm.Metrics().AppendEmpty()
+		metric.SetName(m.name)
+		metric.SetDescription(m.description)
+		metric.SetUnit(m.unit)
+		histogram := metric.SetEmptyHistogram()
+		histogram.SetAggregationTemporality(pmetric.AggregationTemporalityDelta)
+
+		dp := histogram.DataPoints().AppendEmpty()
+		attributes.CopyTo(dp.Attributes())
+		dp.SetStartTimestamp(start)
+		dp.SetTimestamp(end)
+		dp.SetCount(1)
+		dp.SetSum(value)
+		dp.SetMin(value)
+		dp.SetMax(value)
+		dp.ExplicitBounds().FromRaw(m.bounds)
+		// The bucket i holds values in (bounds[i-1], bounds[i]].
+		counts := make([]uint64, len(m.bounds)+1)
+		counts[sort.SearchFloat64s(m.bounds, value)] = 1
+		dp.BucketCounts().FromRaw(counts)
+	}
+
+	if sm.Metrics().Len() == 0 {
+		return pmetric.NewMetrics()
+	}
+	return results
+}
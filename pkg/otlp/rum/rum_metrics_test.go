@@ -0,0 +1,174 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025-present Datadog, Inc.
+
+package rum
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+)
+
+func TestToMetrics(t *testing.T) {
+	req := &http.Request{URL: &url.URL{RawQuery: url.Values{
+		"ddforward": []string{"/api/v2/rum?ddsource=browser&ddtags=env:prod,version:1.2.3"},
+	}.Encode()}}
+	payload := map[string]any{
+		"type":    "view",
+		"date":    1640995200000.0,
+		"service": "shop",
+		"version": "1.2.3",
+		"view": map[string]any{
+			"id":                        "view-1",
+			"name":                      "/checkout",
+			"is_active":                 false,
+			"time_spent":                30000000000.0,
+			"largest_contentful_paint":  2200000000.0,
+			"first_contentful_paint":    900000000.0,
+			"cumulative_layout_shift":   0.12,
+			"interaction_to_next_paint": 180000000.0,
+			"loading_time":              3500000000.0,
+			"error":                     map[string]any{"count": 1.0},
+			"resource":                  map[string]any{"count": 42.0},
+			"action":                    map[string]any{"count": 0.0},
+		},
+	}
+
+	metrics := ToMetrics(payload, req)
+	require.Equal(t, 1, metrics.ResourceMetrics().Len())
+	rm := metrics.ResourceMetrics().At(0)
+	assert.Equal(t, map[string]any{
+		"service.name": "browser-rum-sdk",
+		"ddsource":     "browser",
+		"ddtags":       map[string]any{"env": "prod", "version": "1.2.3"},
+	}, rm.Resource().Attributes().AsRaw())
+	require.Equal(t, 1, rm.ScopeMetrics().Len())
+	sm := rm.ScopeMetrics().At(0)
+	assert.Equal(t, InstrumentationScopeName, sm.Scope().Name())
+
+	type histogram struct {
+		unit    string
+		sum     float64
+		buckets []uint64
+	}
+	got := map[string]histogram{}
+	for i := 0; i < sm.Metrics().Len(); i++ {
+		metric := sm.Metrics().At(i)
+		require.Equal(t, pmetric.MetricTypeHistogram, metric.Type())
+		assert.Equal(t, pmetric.AggregationTemporalityDelta, metric.Histogram().AggregationTemporality())
+		require.Equal(t, 1, metric.Histogram().DataPoints().Len())
+		dp := metric.Histogram().DataPoints().At(0)
+		assert.Equal(t, map[string]any{
+			"service.name":    "shop",
+			"service.version": "1.2.3",
+			"screen.name":     "/checkout",
+		}, dp.Attributes().AsRaw())
+		assert.Equal(t, pcommon.Timestamp(1640995200000000000), dp.StartTimestamp())
+		assert.Equal(t, pcommon.Timestamp(1640995230000000000), dp.Timestamp())
+		assert.EqualValues(t, 1, dp.Count())
+		assert.Equal(t, dp.Sum(), dp.Min())
+		assert.Equal(t, dp.Sum(), dp.Max())
+		assert.Equal(t, dp.ExplicitBounds().Len()+1, dp.BucketCounts().Len())
+		got[metric.Name()] = histogram{unit: metric.Unit(), sum: dp.Sum(), buckets: dp.BucketCounts().AsRaw()}
+	}
+
+	assert.Len(t, got, 8)
+	assert.Equal(t, histogram{unit: "s", sum: 2.2, buckets: []uint64{0, 0, 0, 0, 1, 0, 0, 0, 0, 0, 0}}, got["datadog.rum.view.largest_contentful_paint"])
+	assert.Equal(t, histogram{unit: "s", sum: 0.9, buckets: []uint64{0, 1, 0, 0, 0, 0, 0, 0, 0, 0, 0}}, got["datadog.rum.view.first_contentful_paint"])
+	assert.Equal(t, histogram{unit: "1", sum: 0.12, buckets: []uint64{0, 0, 0, 1, 0, 0, 0, 0}}, got["datadog.rum.view.cumulative_layout_shift"])
+	assert.Equal(t, histogram{unit: "s", sum: 0.18, buckets: []uint64{0, 0, 1, 0, 0, 0, 0, 0, 0}}, got["datadog.rum.view.interaction_to_next_paint"])
+	assert.Equal(t, histogram{unit: "s", sum: 3.5, buckets: []uint64{0, 0, 0, 0, 1, 0, 0, 0, 0}}, got["datadog.rum.view.loading_time"])
+	assert.Equal(t, histogram{unit: "{error}", sum: 1, buckets: []uint64{0, 1, 0, 0, 0, 0, 0, 0, 0, 0, 0}}, got["datadog.rum.view.error.count"])
+	assert.Equal(t, histogram{unit: "{resource}", sum: 42, buckets: []uint64{0, 0, 0, 0, 0, 0, 1, 0, 0, 0, 0}}, got["datadog.rum.view.resource.count"])
+	assert.Equal(t, histogram{unit: "{action}", sum: 0, buckets: []uint64{1, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0}}, got["datadog.rum.view.action.count"])
+}
+
+func TestToMetricsNoMetrics(t *testing.T) {
+	req := &http.Request{URL: &url.URL{}}
+	tests := []struct {
+		name    string
+		payload map[string]any
+	}{
+		{
+			name: "not a view",
+			payload: map[string]any{
+				"type":     "resource",
+				"resource": map[string]any{"duration": 1000000.0},
+			},
+		},
+		{
+			name: "active view",
+			payload: map[string]any{
+				"type": "view",
+				"view": map[string]any{"is_active": true, "loading_time": 1000000.0},
+			},
+		},
+		{
+			name: "view without is_active",
+			payload: map[string]any{
+				"type": "view",
+				"view": map[string]any{"loading_time": 1000000.0},
+			},
+		},
+		{
+			name: "view without metrics",
+			payload: map[string]any{
+				"type": "view",
+				"view": map[string]any{"id": "view-1", "is_active": false},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, 0, ToMetrics(tt.payload, req).MetricCount())
+			assert.Equal(t, 0, ToMetrics(tt.payload, req).ResourceMetrics().Len())
+		})
+	}
+}
+
+func TestToMetricsViewUpdates(t *testing.T) {
+	req := &http.Request{URL: &url.URL{}}
+	update := func(documentVersion float64, isActive any, errorCount float64) map[string]any {
+		view := map[string]any{
+			"id":         "view-1",
+			"time_spent": documentVersion * 1000000000,
+			"error":      map[string]any{"count": errorCount},
+		}
+		if isActive != nil {
+			view["is_active"] = isActive
+		}
+		return map[string]any{
+			"type": "view",
+			"date": 1640995200000.0,
+			"view": view,
+			"_dd":  map[string]any{"document_version": documentVersion},
+		}
+	}
+
+	// The SDK sends an update of the view every time it changes: only the last one is recorded.
+	var errorCounts []float64
+	for _, payload := range []map[string]any{
+		update(1, true, 0),
+		update(2, true, 1),
+		update(3, nil, 1),
+		update(4, false, 2),
+	} {
+		rms := ToMetrics(payload, req).ResourceMetrics()
+		for i := 0; i < rms.Len(); i++ {
+			ms := rms.At(i).ScopeMetrics().At(0).Metrics()
+			for j := 0; j < ms.Len(); j++ {
+				if ms.At(j).Name() == "datadog.rum.view.error.count" {
+					errorCounts = append(errorCounts, ms.At(j).Histogram().DataPoints().At(0).Sum())
+				}
+			}
+		}
+	}
+	assert.Equal(t, []float64{2}, errorCounts)
+}
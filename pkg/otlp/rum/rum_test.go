@@ -254,6 +254,94 @@ func TestConstructRumPayloadFromOTLP(t *testing.T) {
 				"empty": "",
 			},
 		},
+		{
+			name: "common attributes",
+			attrs: map[string]pcommon.Value{
+				"os.build_id":          pcommon.NewValueStr("abc"),
+				"device.manufacturer":  pcommon.NewValueStr("Apple"),
+				"geo.country.iso_code": pcommon.NewValueStr("FR"),
+			},
+			expected: map[string]any{
+				"os":     map[string]any{"build": "abc"},
+				"device": map[string]any{"brand": "Apple"},
+				"geo":    map[string]any{"country_iso_code": "FR"},
+			},
+		},
+		{
+			name: "resource attributes",
+			attrs: map[string]pcommon.Value{
+				"type":                      pcommon.NewValueStr("resource"),
+				"http.request.method":       pcommon.NewValueStr("GET"),
+				"url.full":                  pcommon.NewValueStr("https://example.com"),
+				"http.response.status_code": pcommon.NewValueInt(200),
+			},
+			expected: map[string]any{
+				"type": "resource",
+				"resource": map[string]any{
+					"method":      "GET",
+					"url":         "https://example.com",
+					"status_code": int64(200),
+				},
+			},
+		},
+		{
+			name: "error attributes with datadog prefixed type",
+			attrs: map[string]pcommon.Value{
+				"datadog.type":         pcommon.NewValueStr("error"),
+				"exception.stacktrace": pcommon.NewValueStr("at main.js:1"),
+				"url.full":             pcommon.NewValueStr("https://example.com"),
+			},
+			expected: map[string]any{
+				"type": "error",
+				"error": map[string]any{
+					"stack":    "at main.js:1",
+					"resource": map[string]any{"url": "https://example.com"},
+				},
+			},
+		},
+		{
+			name: "view attributes",
+			attrs: map[string]pcommon.Value{
+				"type":        pcommon.NewValueStr("view"),
+				"url.full":    pcommon.NewValueStr("https://example.com/home"),
+				"screen.name": pcommon.NewValueStr("home"),
+			},
+			expected: map[string]any{
+				"type": "view",
+				"view": map[string]any{
+					"url":  "https://example.com/home",
+					"name": "home",
+				},
+			},
+		},
+		{
+			name: "deprecated attributes",
+			attrs: map[string]pcommon.Value{
+				"type":             pcommon.NewValueStr("resource"),
+				"http.method":      pcommon.NewValueStr("POST"),
+				"http.status_code": pcommon.NewValueInt(500),
+			},
+			expected: map[string]any{
+				"type": "resource",
+				"resource": map[string]any{
+					"method":      "POST",
+					"status_code": int64(500),
+				},
+			},
+		},
+		{
+			name: "attributes of another event type",
+			attrs: map[string]pcommon.Value{
+				"type":                pcommon.NewValueStr("long_task"),
+				"http.request.method": pcommon.NewValueStr("GET"),
+			},
+			expected: map[string]any{
+				"type": "long_task",
+				"http": map[string]any{
+					"request": map[string]any{"method": "GET"},
+				},
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -270,6 +358,187 @@ func TestConstructRumPayloadFromOTLP(t *testing.T) {
 	}
 }
 
+func TestMappingsByTypeRoundTrip(t *testing.T) {
+	for _, eventType := range []string{ViewEventType, ActionEventType, ResourceEventType, LongTaskEventType, ErrorEventType} {
+		t.Run(eventType, func(t *testing.T) {
+			require.Contains(t, OTLPAttributeToRUMPayloadKeyMappingByType, eventType)
+			for attr, key := range OTLPAttributeToRUMPayloadKeyMappingByType[eventType] {
+				gotKey, ok := rumPayloadKey(eventType, attr)
+				require.True(t, ok)
+				assert.Equal(t, key, gotKey)
+				gotAttr, ok := otlpAttribute(eventType, key)
+				require.True(t, ok)
+				assert.Equal(t, attr, gotAttr)
+			}
+			for alias, key := range otlpAttributeAliasesByType[eventType] {
+				gotKey, ok := rumPayloadKey(eventType, alias)
+				require.True(t, ok)
+				assert.Equal(t, key, gotKey)
+			}
+		})
+	}
+}
+
+func TestMappingsHaveNoCollisions(t *testing.T) {
+	// The inverted mappings are only correct if no two OTLP attributes map to the same RUM payload key.
+	assertInjective := func(t *testing.T, mapping map[string]string) {
+		attrs := make(map[string]string, len(mapping))
+		for attr, key := range mapping {
+			if other, ok := attrs[key]; ok {
+				t.Errorf("both %q and %q are mapped to %q", other, attr, key)
+			}
+			attrs[key] = attr
+		}
+	}
+
+	t.Run("common", func(t *testing.T) {
+		assertInjective(t, OTLPAttributeToRUMPayloadKeyMapping)
+	})
+	for eventType, mapping := range OTLPAttributeToRUMPayloadKeyMappingByType {
+		t.Run(eventType, func(t *testing.T) {
+			assertInjective(t, mapping)
+			// Mappings of the event type take precedence over the common ones.
+			for attr, key := range OTLPAttributeToRUMPayloadKeyMapping {
+				if _, ok := mapping[attr]; !ok {
+					assert.NotContains(t, RUMPayloadKeyToOTLPAttributeMappingByType[eventType], key,
+						"%q is mapped to %q by the common mapping", attr, key)
+				}
+			}
+		})
+	}
+}
+
+func TestMappingsBySchema(t *testing.T) {
+	type mapping struct {
+		attr string
+		key  string
+	}
+	tests := []struct {
+		eventType string
+		mappings  []mapping
+		// aliases are only mapped from OTLP to RUM.
+		aliases []mapping
+	}{
+		{
+			eventType: ViewEventType,
+			mappings: []mapping{
+				{attr: "url.full", key: "view.url"},
+				{attr: "screen.name", key: "view.name"},
+				{attr: "view.loading_time", key: "view.loading_time"},
+				{attr: "view.first_contentful_paint", key: "view.first_contentful_paint"},
+				{attr: "view.largest_contentful_paint", key: "view.largest_contentful_paint"},
+				{attr: "view.interaction_to_next_paint", key: "view.interaction_to_next_paint"},
+				{attr: "view.cumulative_layout_shift", key: "view.cumulative_layout_shift"},
+				{attr: "browser.language", key: "device.locale"},
+				{attr: "os.name", key: "os.name"},
+			},
+			aliases: []mapping{
+				{attr: "browser.platform", key: "os.name"},
+			},
+		},
+		{
+			eventType: ActionEventType,
+			mappings: []mapping{
+				{attr: "action.type", key: "action.type"},
+				{attr: "app.widget.name", key: "action.target.name"},
+				{attr: "action.loading_time", key: "action.loading_time"},
+				{attr: "device.model.name", key: "device.name"},
+			},
+		},
+		{
+			eventType: ResourceEventType,
+			mappings: []mapping{
+				{attr: "resource.type", key: "resource.type"},
+				{attr: "resource.duration", key: "resource.duration"},
+				{attr: "url.full", key: "resource.url"},
+				{attr: "http.request.method", key: "resource.method"},
+				{attr: "http.response.status_code", key: "resource.status_code"},
+			},
+			aliases: []mapping{
+				{attr: "http.url", key: "resource.url"},
+			},
+		},
+		{
+			eventType: LongTaskEventType,
+			mappings: []mapping{
+				{attr: "long_task.id", key: "long_task.id"},
+				{attr: "long_task.duration", key: "long_task.duration"},
+				{attr: "long_task.entry_type", key: "long_task.entry_type"},
+				{attr: "geo.locality.name", key: "geo.city"},
+			},
+		},
+		{
+			eventType: ErrorEventType,
+			mappings: []mapping{
+				{attr: "error.message", key: "error.message"},
+				{attr: "error.source", key: "error.source"},
+				{attr: "exception.stacktrace", key: "error.stack"},
+				{attr: "url.full", key: "error.resource.url"},
+			},
+			aliases: []mapping{
+				{attr: "exception.message", key: "error.message"},
+				{attr: "browser.platform", key: "os.name"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.eventType, func(t *testing.T) {
+			for _, m := range tt.mappings {
+				key, ok := rumPayloadKey(tt.eventType, m.attr)
+				assert.True(t, ok, m.attr)
+				assert.Equal(t, m.key, key)
+				attr, ok := otlpAttribute(tt.eventType, m.key)
+				assert.True(t, ok, m.key)
+				assert.Equal(t, m.attr, attr)
+			}
+			for _, m := range tt.aliases {
+				key, ok := rumPayloadKey(tt.eventType, m.attr)
+				assert.True(t, ok, m.attr)
+				assert.Equal(t, m.key, key)
+			}
+		})
+	}
+}
+
+func TestSetOTLPAttributesRoundTrip(t *testing.T) {
+	// Numbers are float64, as in JSON payloads.
+	payloads := []map[string]any{
+		{
+			"type":    "view",
+			"service": "my-service",
+			"view":    map[string]any{"url": "https://example.com/home", "name": "home", "time_spent": float64(10), "loading_time": float64(5), "largest_contentful_paint": float64(4)},
+			"os":      map[string]any{"name": "iOS", "build": "abc"},
+		},
+		{
+			"type":   "action",
+			"action": map[string]any{"type": "click", "loading_time": float64(20), "target": map[string]any{"name": "button"}},
+			"view":   map[string]any{"url": "https://example.com/home"},
+		},
+		{
+			"type":     "resource",
+			"resource": map[string]any{"type": "fetch", "duration": float64(30), "method": "GET", "url": "https://example.com/api", "status_code": float64(200), "transfer_size": float64(512)},
+			"view":     map[string]any{"url": "https://example.com/home"},
+		},
+		{
+			"type":      "long_task",
+			"long_task": map[string]any{"id": "lt-1", "duration": float64(100), "entry_type": "long-task"},
+			"device":    map[string]any{"brand": "Apple", "model": "iPhone15,2"},
+		},
+		{
+			"type":  "error",
+			"error": map[string]any{"message": "boom", "stack": "at main.js:1", "resource": map[string]any{"url": "https://example.com/api"}},
+			"geo":   map[string]any{"country_iso_code": "FR", "city": "Paris"},
+		},
+	}
+	for _, payload := range payloads {
+		t.Run(payload["type"].(string), func(t *testing.T) {
+			attrs := pcommon.NewMap()
+			setOTLPAttributes(flattenJSON(payload), attrs, payload["type"].(string))
+			assert.Equal(t, payload, ConstructRumPayloadFromOTLP(attrs))
+		})
+	}
+}
+
 func TestParseDDForwardIntoResource(t *testing.T) {
 	tests := []struct {
 		name      string
@@ -390,3 +659,120 @@ func TestBuildIntakeUrlPathAndParameters(t *testing.T) {
 		})
 	}
 }
+
+func TestParseIDs(t *testing.T) {
+	traceID128 := pcommon.TraceID{0x4b, 0xf9, 0x2f, 0x35, 0x77, 0xb3, 0x4d, 0xa6, 0xa3, 0xce, 0x92, 0x9d, 0x0e, 0x0e, 0x47, 0x36}
+	traceID64 := pcommon.TraceID{8: 0xa3, 9: 0xce, 10: 0x92, 11: 0x9d, 12: 0x0e, 13: 0x0e, 14: 0x47, 15: 0x36}
+	spanID := pcommon.SpanID{0x00, 0xf0, 0x67, 0xaa, 0x0b, 0xa9, 0x02, 0xb7}
+
+	tests := []struct {
+		name            string
+		payload         map[string]any
+		expectedTraceID pcommon.TraceID
+		expectedSpanID  pcommon.SpanID
+		expectedErr     string
+	}{
+		{
+			name:            "decimal IDs",
+			payload:         map[string]any{"_dd": map[string]any{"trace_id": "11803532876627986230", "span_id": "67667974448284343"}},
+			expectedTraceID: traceID64,
+			expectedSpanID:  spanID,
+		},
+		{
+			name: "decimal IDs with trace ID high bits",
+			payload: map[string]any{"_dd": map[string]any{
+				"trace_id":      "11803532876627986230",
+				"trace_id_high": "4bf92f3577b34da6",
+				"span_id":       "67667974448284343",
+			}},
+			expectedTraceID: traceID128,
+			expectedSpanID:  spanID,
+		},
+		{
+			name: "decimal IDs with tid",
+			payload: map[string]any{"_dd": map[string]any{
+				"trace_id": "11803532876627986230",
+				"p":        map[string]any{"tid": "4bf92f3577b34da6"},
+				"span_id":  "67667974448284343",
+			}},
+			expectedTraceID: traceID128,
+			expectedSpanID:  spanID,
+		},
+		{
+			name:            "128-bit decimal trace ID",
+			payload:         map[string]any{"_dd": map[string]any{"trace_id": "100985939111033328018442752961257817910", "span_id": "67667974448284343"}},
+			expectedTraceID: traceID128,
+			expectedSpanID:  spanID,
+		},
+		{
+			name:            "hex IDs",
+			payload:         map[string]any{"_dd": map[string]any{"trace_id": "4bf92f3577b34da6a3ce929d0e0e4736", "span_id": "00f067aa0ba902b7"}},
+			expectedTraceID: traceID128,
+			expectedSpanID:  spanID,
+		},
+		{
+			name:            "64-bit hex trace ID",
+			payload:         map[string]any{"_dd": map[string]any{"trace_id": "a3ce929d0e0e4736", "span_id": "00f067aa0ba902b7"}},
+			expectedTraceID: traceID64,
+			expectedSpanID:  spanID,
+		},
+		{
+			// 16 digits are also valid hex characters: they are read as a decimal number, as in logs.
+			name:            "64-bit decimal trace ID of 16 digits",
+			payload:         map[string]any{"_dd": map[string]any{"trace_id": "1234567890123456", "span_id": "00f067aa0ba902b7"}},
+			expectedTraceID: pcommon.TraceID{8: 0x00, 9: 0x04, 10: 0x62, 11: 0xd5, 12: 0x3c, 13: 0x8a, 14: 0xba, 15: 0xc0},
+			expectedSpanID:  spanID,
+		},
+		{
+			name: "traceparent of resource",
+			payload: map[string]any{
+				"resource": map[string]any{"traceparent": "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"},
+				"_dd":      map[string]any{"trace_id": "1", "span_id": "2"},
+			},
+			expectedTraceID: traceID128,
+			expectedSpanID:  spanID,
+		},
+		{
+			name:        "invalid traceparent",
+			payload:     map[string]any{"_dd": map[string]any{"traceparent": "00-00000000000000000000000000000000-00f067aa0ba902b7-01"}},
+			expectedErr: `invalid traceparent trace ID "00000000000000000000000000000000"`,
+		},
+		{
+			name: "invalid trace ID high bits",
+			payload: map[string]any{"_dd": map[string]any{
+				"trace_id":      "11803532876627986230",
+				"trace_id_high": "4bf92f35",
+				"span_id":       "67667974448284343",
+			}},
+			expectedErr: `failed to parse traceID high bits: "4bf92f35" is not 16 hex characters`,
+		},
+		{
+			name:        "trace ID over 128 bits",
+			payload:     map[string]any{"_dd": map[string]any{"trace_id": "340282366920938463463374607431768211456", "span_id": "1"}},
+			expectedErr: `failed to parse traceID: "340282366920938463463374607431768211456" is not 32 or 16 hex characters, or a 128-bit decimal number`,
+		},
+		{
+			name:        "signed decimal trace ID",
+			payload:     map[string]any{"_dd": map[string]any{"trace_id": "+1", "span_id": "1"}},
+			expectedErr: `failed to parse traceID: "+1" is not 32 or 16 hex characters, or a 128-bit decimal number`,
+		},
+		{
+			name:        "invalid span ID",
+			payload:     map[string]any{"_dd": map[string]any{"trace_id": "1", "span_id": "-1"}},
+			expectedErr: `failed to parse spanID: "-1" is not 16 hex characters or a 64-bit decimal number`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			traceID, spanID, err := parseIDs(tt.payload)
+			if tt.expectedErr != "" {
+				assert.EqualError(t, err, tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedTraceID, traceID)
+			assert.Equal(t, tt.expectedSpanID, spanID)
+		})
+	}
+}
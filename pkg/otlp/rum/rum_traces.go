@@ -1,7 +1,10 @@
 package rum
 
 import (
+	"crypto/rand"
 	"fmt"
+	"hash/fnv"
+	"math"
 	"net/http"
 
 	"go.opentelemetry.io/collector/pdata/pcommon"
@@ -10,7 +13,38 @@ import (
 	"go.uber.org/zap"
 )
 
+// eventIDFields are the fields holding the ID of RUM events, by event type.
+var eventIDFields = map[string]string{
+	ViewEventType:     "view.id",
+	ActionEventType:   "action.id",
+	ResourceEventType: "resource.id",
+	LongTaskEventType: "long_task.id",
+	ErrorEventType:    "error.id",
+}
+
+// eventDurationFields are the fields holding the duration in nanoseconds of RUM events, by event type.
+var eventDurationFields = map[string]string{
+	ViewEventType:     "view.time_spent",
+	ActionEventType:   "action.loading_time",
+	ResourceEventType: "resource.duration",
+	LongTaskEventType: "long_task.duration",
+}
+
+// resourcePhases are the phases of the loading of a resource, mapped to child spans of the resource span.
+var resourcePhases = []string{"dns", "connect", "ssl", "first_byte", "download"}
+
+// ToTraces maps a RUM event to a span. Spans of the same view share a trace, derived from the view ID:
+// the view span is the parent of the spans of its actions, and the parent of the spans of its other events
+// is the span of their action or else of their view. Events with a Datadog trace ID, such as traced
+// resources, keep it and are linked to their action or view span instead.
+// Resource spans have a child span for every phase of the loading of the resource (e.g. DNS lookup).
+// Since the view span ID is derived from the view ID, only the final update of a view, whose `view.is_active`
+// is false, is mapped to a span: other updates of the view have no spans, as with ToMetrics.
 func ToTraces(logger *zap.Logger, payload map[string]any, req *http.Request) (ptrace.Traces, error) {
+	if eventType, _ := payload[Type].(string); eventType == ViewEventType && !isFinalViewUpdate(payload) {
+		return ptrace.NewTraces(), nil
+	}
+
 	results := ptrace.NewTraces()
 	rs := results.ResourceSpans().AppendEmpty()
 	rs.SetSchemaUrl(semconv.SchemaURL)
@@ -20,50 +54,178 @@ func ToTraces(logger *zap.Logger, payload map[string]any, req *http.Request) (pt
 	in := rs.ScopeSpans().AppendEmpty()
 	in.Scope().SetName(InstrumentationScopeName)
 
-	traceID, spanID, err := parseIDs(payload)
+	ids, err := parseSpanIDs(payload)
 	if err != nil {
 		return ptrace.NewTraces(), err
 	}
-	logger.Info("Trace ID", zap.String("traceID", traceID.String()))
-	logger.Info("Span ID", zap.String("spanID", spanID.String()))
+	logger.Debug("Mapping RUM event to span", zap.Stringer("traceID", ids.traceID), zap.Stringer("spanID", ids.spanID))
 
+	eventType, _ := payload[Type].(string)
 	newSpan := in.Spans().AppendEmpty()
-	if eventType, ok := payload[Type].(string); ok {
+	if eventType != "" {
 		newSpan.SetName("datadog.rum." + eventType)
 	} else {
 		newSpan.SetName("datadog.rum.event")
 	}
-	newSpan.SetTraceID(traceID)
-	newSpan.SetSpanID(spanID)
+	newSpan.SetTraceID(ids.traceID)
+	newSpan.SetSpanID(ids.spanID)
+	if !ids.parentSpanID.IsEmpty() {
+		if ids.parentTraceID == ids.traceID {
+			newSpan.SetParentSpanID(ids.parentSpanID)
+		} else {
+			link := newSpan.Links().AppendEmpty()
+			link.SetTraceID(ids.parentTraceID)
+			link.SetSpanID(ids.parentSpanID)
+		}
+	}
+	if eventType == ResourceEventType {
+		newSpan.SetKind(ptrace.SpanKindClient)
+	}
 
 	flatPayload := flattenJSON(payload)
 
 	setDateForSpan(payload, newSpan)
-	setOTLPAttributes(flatPayload, newSpan.Attributes())
+	setOTLPAttributes(flatPayload, newSpan.Attributes(), eventType)
+
+	if eventType == ResourceEventType {
+		appendResourcePhaseSpans(payload, newSpan, in.Spans())
+	}
 
 	return results, nil
 }
 
-func setDateForSpan(payload map[string]any, span ptrace.Span) {
-	date, ok := payload["date"]
-	if !ok {
-		return
+// eventSpanIDs are the IDs of the span of a RUM event.
+type eventSpanIDs struct {
+	traceID pcommon.TraceID
+	spanID  pcommon.SpanID
+	// parentTraceID and parentSpanID identify the span of the action or view of the event, if any.
+	parentTraceID pcommon.TraceID
+	parentSpanID  pcommon.SpanID
+}
+
+// parseSpanIDs returns the IDs of the span of a RUM event. The span has the trace and span IDs
+// of the event if it has some (see parseIDs), and IDs derived from the view ID and the event ID otherwise.
+func parseSpanIDs(payload map[string]any) (eventSpanIDs, error) {
+	var ids eventSpanIDs
+	eventType, _ := payload[Type].(string)
+	viewID := stringField(payload, "view.id")
+
+	if hasTraceContext(payload) || viewID == "" {
+		traceID, spanID, err := parseIDs(payload)
+		if err != nil {
+			return eventSpanIDs{}, err
+		}
+		ids.traceID, ids.spanID = traceID, spanID
+	} else {
+		ids.traceID = traceIDFromViewID(viewID)
+		if eventID := stringField(payload, eventIDFields[eventType]); eventID != "" {
+			ids.spanID = spanIDFromEventID(eventID)
+		} else {
+			ids.spanID = randomSpanID()
+		}
+	}
+
+	if viewID == "" || eventType == ViewEventType {
+		return ids, nil
 	}
-	dateFloat, ok := date.(float64)
-	if !ok {
+	ids.parentTraceID = traceIDFromViewID(viewID)
+	// Resources, long tasks and errors have the ID of the action they belong to, if any.
+	if actionID := stringField(payload, "action.id"); actionID != "" && eventType != ActionEventType {
+		ids.parentSpanID = spanIDFromEventID(actionID)
+	} else {
+		ids.parentSpanID = spanIDFromEventID(viewID)
+	}
+	return ids, nil
+}
+
+// traceIDFromViewID returns the trace ID of the spans of a view.
+func traceIDFromViewID(viewID string) pcommon.TraceID {
+	h := fnv.New128a()
+	_, _ = h.Write([]byte(viewID))
+	var traceID pcommon.TraceID
+	copy(traceID[:], h.Sum(nil))
+	return traceID
+}
+
+// spanIDFromEventID returns the span ID of a RUM event, so that the spans of other events can refer to it.
+func spanIDFromEventID(eventID string) pcommon.SpanID {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(eventID))
+	return uInt64ToSpanID(h.Sum64())
+}
+
+// randomSpanID returns the span ID of a RUM event without an ID.
+func randomSpanID() pcommon.SpanID {
+	var spanID pcommon.SpanID
+	_, _ = rand.Read(spanID[:])
+	return spanID
+}
+
+// setDateForSpan sets the start of a span to the date of its event, in milliseconds since the epoch,
+// and its end from the duration of its event, in nanoseconds.
+func setDateForSpan(payload map[string]any, span ptrace.Span) {
+	date, ok := numberField(payload, "date")
+	if !ok || date < 0 {
 		return
 	}
-	dateNanoseconds := uint64(dateFloat) * 1e6
+	start := millisecondsToTimestamp(date)
 
 	// default duration to 0 if not found
-	var duration float64 = 0
-	if resource, ok := payload["resource"].(map[string]any); ok {
-		if durationVal, ok := resource["duration"].(float64); ok {
-			duration = durationVal
+	var duration float64
+	eventType, _ := payload[Type].(string)
+	if field, ok := eventDurationFields[eventType]; ok {
+		duration, _ = numberField(payload, field)
+	}
+
+	span.SetStartTimestamp(start)
+	span.SetEndTimestamp(start + pcommon.Timestamp(math.Max(duration, 0)))
+}
+
+// appendResourcePhaseSpans appends a child span of the resource span for every phase of the loading
+// of the resource. Phases start at the given offset in nanoseconds from the start of the resource.
+func appendResourcePhaseSpans(payload map[string]any, resourceSpan ptrace.Span, spans ptrace.SpanSlice) {
+	for _, phase := range resourcePhases {
+		offset, ok := numberField(payload, fmt.Sprintf("resource.%s.start", phase))
+		if !ok {
+			continue
 		}
+		duration, _ := numberField(payload, fmt.Sprintf("resource.%s.duration", phase))
+
+		span := spans.AppendEmpty()
+		span.SetName("datadog.rum.resource." + phase)
+		span.SetTraceID(resourceSpan.TraceID())
+		span.SetSpanID(spanIDFromEventID(resourceSpan.SpanID().String() + "." + phase))
+		span.SetParentSpanID(resourceSpan.SpanID())
+		start := resourceSpan.StartTimestamp() + pcommon.Timestamp(math.Max(offset, 0))
+		span.SetStartTimestamp(start)
+		span.SetEndTimestamp(start + pcommon.Timestamp(math.Max(duration, 0)))
 	}
-	fmt.Println("duration", uint64(duration))
+}
+
+// millisecondsToTimestamp converts milliseconds since the epoch to a timestamp, without losing
+// the precision of the fractional milliseconds.
+func millisecondsToTimestamp(ms float64) pcommon.Timestamp {
+	whole := math.Floor(ms)
+	return pcommon.Timestamp(uint64(whole)*1e6 + uint64(math.Round((ms-whole)*1e6)))
+}
+
+// numberField returns the value of a numeric field of a payload at a dot-separated path.
+func numberField(payload map[string]any, path string) (float64, bool) {
+	val, _ := lookupField(payload, path)
+	switch v := val.(type) {
+	case float64:
+		return v, true
+	case int64:
+		return float64(v), true
+	case int:
+		return float64(v), true
+	}
+	return 0, false
+}
 
-	span.SetStartTimestamp(pcommon.Timestamp(dateNanoseconds))
-	span.SetEndTimestamp(pcommon.Timestamp(dateNanoseconds + uint64(duration)))
+// stringField returns the value of a string field of a payload at a dot-separated path, or "".
+func stringField(payload map[string]any, path string) string {
+	val, _ := lookupField(payload, path)
+	s, _ := val.(string)
+	return s
 }
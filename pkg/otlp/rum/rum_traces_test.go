@@ -6,6 +6,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"go.opentelemetry.io/collector/pdata/pcommon"
 	"go.opentelemetry.io/collector/pdata/ptrace"
@@ -29,8 +30,8 @@ func TestToTraces(t *testing.T) {
 					"trace_id": "16976667969123787577",
 					"span_id":  "2791337267577444227",
 				},
-				"resource": map[string]any{
-					"duration": 10500000.0,
+				"action": map[string]any{
+					"loading_time": 10500000.0,
 				},
 				"service": "test-service",
 				"version": "1.0.0",
@@ -144,6 +145,7 @@ func TestSetDateForSpan(t *testing.T) {
 		{
 			name: "with date and duration",
 			payload: map[string]any{
+				"type": "resource",
 				"date": 1640995200000.0, // 2022-01-01 00:00:00 UTC
 				"resource": map[string]any{
 					"duration": 10500000.0,
@@ -152,6 +154,30 @@ func TestSetDateForSpan(t *testing.T) {
 			expectedStart: pcommon.Timestamp(1640995200000000000),
 			expectedEnd:   pcommon.Timestamp(1640995200010500000),
 		},
+		{
+			name: "with fractional date",
+			payload: map[string]any{
+				"type": "view",
+				"date": 1640995200000.25,
+				"view": map[string]any{
+					"time_spent": int64(2000000),
+				},
+			},
+			expectedStart: pcommon.Timestamp(1640995200000250000),
+			expectedEnd:   pcommon.Timestamp(1640995200002250000),
+		},
+		{
+			name: "with duration of another event type",
+			payload: map[string]any{
+				"type": "action",
+				"date": 1640995200000.0,
+				"resource": map[string]any{
+					"duration": 10500000.0,
+				},
+			},
+			expectedStart: pcommon.Timestamp(1640995200000000000),
+			expectedEnd:   pcommon.Timestamp(1640995200000000000),
+		},
 		{
 			name: "with date only",
 			payload: map[string]any{
@@ -182,3 +208,138 @@ func TestSetDateForSpan(t *testing.T) {
 		})
 	}
 }
+
+func TestToTracesHierarchy(t *testing.T) {
+	req := &http.Request{URL: &url.URL{}}
+	toSpans := func(t *testing.T, payload map[string]any) ptrace.SpanSlice {
+		traces, err := ToTraces(zap.NewNop(), payload, req)
+		require.NoError(t, err)
+		return traces.ResourceSpans().At(0).ScopeSpans().At(0).Spans()
+	}
+
+	view := toSpans(t, map[string]any{
+		"type": "view",
+		"date": 1640995200000.0,
+		"view": map[string]any{"id": "view-1", "time_spent": 5000000000.0, "is_active": false},
+	}).At(0)
+	assert.Equal(t, "datadog.rum.view", view.Name())
+	assert.True(t, view.ParentSpanID().IsEmpty())
+	assert.False(t, view.TraceID().IsEmpty())
+	assert.Equal(t, pcommon.Timestamp(1640995205000000000), view.EndTimestamp())
+
+	action := toSpans(t, map[string]any{
+		"type":   "action",
+		"date":   1640995201000.0,
+		"view":   map[string]any{"id": "view-1"},
+		"action": map[string]any{"id": "action-1", "type": "click"},
+	}).At(0)
+	assert.Equal(t, view.TraceID(), action.TraceID())
+	assert.Equal(t, view.SpanID(), action.ParentSpanID())
+
+	t.Run("resource of action", func(t *testing.T) {
+		spans := toSpans(t, map[string]any{
+			"type":   "resource",
+			"date":   1640995201000.0,
+			"view":   map[string]any{"id": "view-1"},
+			"action": map[string]any{"id": "action-1"},
+			"resource": map[string]any{
+				"id":         "resource-1",
+				"duration":   300000000.0,
+				"dns":        map[string]any{"start": 1000000.0, "duration": 2000000.0},
+				"connect":    map[string]any{"start": 3000000.0, "duration": 4000000.0},
+				"ssl":        map[string]any{"start": 5000000.0, "duration": 2000000.0},
+				"first_byte": map[string]any{"start": 7000000.0, "duration": 200000000.0},
+				"download":   map[string]any{"start": 207000000.0, "duration": 93000000.0},
+			},
+		})
+		require.Equal(t, 6, spans.Len())
+		resource := spans.At(0)
+		assert.Equal(t, ptrace.SpanKindClient, resource.Kind())
+		assert.Equal(t, view.TraceID(), resource.TraceID())
+		assert.Equal(t, action.SpanID(), resource.ParentSpanID())
+		assert.Equal(t, pcommon.Timestamp(1640995201000000000), resource.StartTimestamp())
+		assert.Equal(t, pcommon.Timestamp(1640995201300000000), resource.EndTimestamp())
+
+		phases := map[string][2]pcommon.Timestamp{}
+		for i := 1; i < spans.Len(); i++ {
+			phase := spans.At(i)
+			assert.Equal(t, resource.TraceID(), phase.TraceID())
+			assert.Equal(t, resource.SpanID(), phase.ParentSpanID())
+			assert.NotEqual(t, resource.SpanID(), phase.SpanID())
+			phases[phase.Name()] = [2]pcommon.Timestamp{phase.StartTimestamp(), phase.EndTimestamp()}
+		}
+		assert.Equal(t, map[string][2]pcommon.Timestamp{
+			"datadog.rum.resource.dns":        {1640995201001000000, 1640995201003000000},
+			"datadog.rum.resource.connect":    {1640995201003000000, 1640995201007000000},
+			"datadog.rum.resource.ssl":        {1640995201005000000, 1640995201007000000},
+			"datadog.rum.resource.first_byte": {1640995201007000000, 1640995201207000000},
+			"datadog.rum.resource.download":   {1640995201207000000, 1640995201300000000},
+		}, phases)
+	})
+
+	t.Run("traced resource of view", func(t *testing.T) {
+		spans := toSpans(t, map[string]any{
+			"type":     "resource",
+			"date":     1640995201000.0,
+			"view":     map[string]any{"id": "view-1"},
+			"resource": map[string]any{"id": "resource-2", "duration": 1000000.0},
+			"_dd": map[string]any{
+				"trace_id": "16976667969123787577",
+				"span_id":  "2791337267577444227",
+			},
+		})
+		require.Equal(t, 1, spans.Len())
+		resource := spans.At(0)
+		// The resource keeps the trace of the backend, and is linked to its view.
+		assert.Equal(t, uInt64ToTraceID(0, 16976667969123787577), resource.TraceID())
+		assert.Equal(t, uInt64ToSpanID(2791337267577444227), resource.SpanID())
+		assert.True(t, resource.ParentSpanID().IsEmpty())
+		require.Equal(t, 1, resource.Links().Len())
+		assert.Equal(t, view.TraceID(), resource.Links().At(0).TraceID())
+		assert.Equal(t, view.SpanID(), resource.Links().At(0).SpanID())
+	})
+
+	t.Run("error without action", func(t *testing.T) {
+		spans := toSpans(t, map[string]any{
+			"type":  "error",
+			"date":  1640995202000.0,
+			"view":  map[string]any{"id": "view-1"},
+			"error": map[string]any{"message": "boom"},
+		})
+		require.Equal(t, 1, spans.Len())
+		assert.Equal(t, view.TraceID(), spans.At(0).TraceID())
+		assert.Equal(t, view.SpanID(), spans.At(0).ParentSpanID())
+		assert.False(t, spans.At(0).SpanID().IsEmpty())
+	})
+}
+
+func TestToTracesViewUpdates(t *testing.T) {
+	req := &http.Request{URL: &url.URL{}}
+	update := func(documentVersion float64, isActive bool, timeSpent float64) map[string]any {
+		return map[string]any{
+			"type": "view",
+			"date": 1640995200000.0,
+			"_dd":  map[string]any{"document_version": documentVersion},
+			"view": map[string]any{"id": "view-1", "is_active": isActive, "time_spent": timeSpent},
+		}
+	}
+
+	var spans []ptrace.Span
+	for _, payload := range []map[string]any{update(1, true, 1000000000.0), update(2, false, 5000000000.0)} {
+		traces, err := ToTraces(zap.NewNop(), payload, req)
+		require.NoError(t, err)
+		for i := 0; i < traces.ResourceSpans().Len(); i++ {
+			for j := 0; j < traces.ResourceSpans().At(i).ScopeSpans().Len(); j++ {
+				ss := traces.ResourceSpans().At(i).ScopeSpans().At(j).Spans()
+				for k := 0; k < ss.Len(); k++ {
+					spans = append(spans, ss.At(k))
+				}
+			}
+		}
+	}
+
+	// Only the final update of the view is mapped to a span.
+	require.Len(t, spans, 1)
+	assert.Equal(t, spanIDFromEventID("view-1"), spans[0].SpanID())
+	assert.Equal(t, pcommon.Timestamp(1640995205000000000), spans[0].EndTimestamp())
+}
@@ -0,0 +1,223 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025-present Datadog, Inc.
+
+package rum
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+)
+
+var (
+	// ErrMissingField is the error of a required field missing from a RUM payload.
+	ErrMissingField = errors.New("missing required field")
+	// ErrInvalidFieldType is the error of a field of a RUM payload with a value of the wrong type.
+	ErrInvalidFieldType = errors.New("invalid field type")
+	// ErrUnknownEventType is the error of a RUM payload with an unknown event type.
+	ErrUnknownEventType = errors.New("unknown event type")
+)
+
+// FieldError is the error of a field of a RUM payload.
+type FieldError struct {
+	// Field is the path of the field, e.g. `session.id`.
+	Field string
+	// Err is ErrMissingField, ErrInvalidFieldType or ErrUnknownEventType, possibly wrapped.
+	Err error
+}
+
+func (e FieldError) Error() string {
+	return fmt.Sprintf("%s: %v", e.Field, e.Err)
+}
+
+func (e FieldError) Unwrap() error {
+	return e.Err
+}
+
+// PayloadError is the error of an invalid RUM payload.
+type PayloadError struct {
+	// EventType is the type of the event, empty if the payload has none.
+	EventType string
+	// Errors are the errors of the invalid fields.
+	Errors []FieldError
+}
+
+func (e *PayloadError) Error() string {
+	msgs := make([]string, 0, len(e.Errors))
+	for _, err := range e.Errors {
+		msgs = append(msgs, err.Error())
+	}
+	return fmt.Sprintf("invalid RUM %q event: %s", e.EventType, strings.Join(msgs, "; "))
+}
+
+// Unwrap returns the field errors, so that errors.Is(err, ErrMissingField) reports whether a field is missing.
+func (e *PayloadError) Unwrap() []error {
+	errs := make([]error, 0, len(e.Errors))
+	for _, err := range e.Errors {
+		errs = append(errs, err)
+	}
+	return errs
+}
+
+// fieldKind is the kind of value of a RUM payload field.
+type fieldKind string
+
+const (
+	kindString  fieldKind = "string"
+	kindInteger fieldKind = "integer"
+	kindBool    fieldKind = "boolean"
+	kindObject  fieldKind = "object"
+)
+
+// fieldSpec describes a field of a RUM payload.
+type fieldSpec struct {
+	path     string
+	kind     fieldKind
+	required bool
+}
+
+// commonFields are the fields of all RUM events
+// (https://github.com/DataDog/rum-events-format/blob/master/schemas/rum/_common-schema.json).
+var commonFields = []fieldSpec{
+	{path: Type, kind: kindString, required: true},
+	{path: "date", kind: kindInteger, required: true},
+	{path: "application.id", kind: kindString, required: true},
+	{path: SessionId, kind: kindString, required: true},
+	{path: "view.id", kind: kindString, required: true},
+	{path: Service, kind: kindString},
+	{path: Version, kind: kindString},
+	{path: "session.type", kind: kindString},
+	{path: "view.url", kind: kindString},
+	{path: "view.name", kind: kindString},
+	{path: "usr", kind: kindObject},
+	{path: UsrId, kind: kindString},
+	{path: "_dd.format_version", kind: kindInteger},
+}
+
+// eventFields are the fields of RUM events, by event type.
+var eventFields = map[string][]fieldSpec{
+	// view-schema.json
+	ViewEventType: {
+		{path: "view.time_spent", kind: kindInteger, required: true},
+		{path: "view.action.count", kind: kindInteger, required: true},
+		{path: "view.error.count", kind: kindInteger, required: true},
+		{path: "view.resource.count", kind: kindInteger, required: true},
+		{path: "_dd.document_version", kind: kindInteger, required: true},
+		{path: "view.loading_time", kind: kindInteger},
+		{path: "view.long_task.count", kind: kindInteger},
+	},
+	// action-schema.json
+	ActionEventType: {
+		{path: "action.type", kind: kindString, required: true},
+		{path: "action.id", kind: kindString},
+		{path: "action.loading_time", kind: kindInteger},
+		{path: "action.target.name", kind: kindString},
+	},
+	// resource-schema.json
+	ResourceEventType: {
+		{path: "resource.type", kind: kindString, required: true},
+		{path: "resource.url", kind: kindString, required: true},
+		{path: "resource.method", kind: kindString},
+		{path: "resource.status_code", kind: kindInteger},
+		{path: "resource.duration", kind: kindInteger},
+		{path: "resource.size", kind: kindInteger},
+	},
+	// long_task-schema.json
+	LongTaskEventType: {
+		{path: "long_task.duration", kind: kindInteger, required: true},
+		{path: "long_task.id", kind: kindString},
+	},
+	// error-schema.json
+	ErrorEventType: {
+		{path: ErrorMessage, kind: kindString, required: true},
+		{path: "error.source", kind: kindString, required: true},
+		{path: "error.stack", kind: kindString},
+		{path: ErrorType, kind: kindString},
+		{path: "error.is_crash", kind: kindBool},
+	},
+}
+
+// ValidatePayload checks that a RUM payload, such as one built by ConstructRumPayloadFromOTLP,
+// has the fields required by the RUM schema of its event type, and that its fields have values of
+// the right type. Fields not known to the validator are not checked.
+// It returns a *PayloadError listing the invalid fields, or nil if the payload is valid.
+func ValidatePayload(payload map[string]any) error {
+	eventType, _ := payload[Type].(string)
+	var errs []FieldError
+	check := func(specs []fieldSpec) {
+		for _, spec := range specs {
+			if err := spec.check(payload); err != nil {
+				errs = append(errs, FieldError{Field: spec.path, Err: err})
+			}
+		}
+	}
+
+	check(commonFields)
+	if specs, ok := eventFields[eventType]; ok {
+		check(specs)
+	} else if eventType != "" {
+		errs = append(errs, FieldError{Field: Type, Err: fmt.Errorf("%w %q", ErrUnknownEventType, eventType)})
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+	return &PayloadError{EventType: eventType, Errors: errs}
+}
+
+// check checks the field of a payload.
+func (s fieldSpec) check(payload map[string]any) error {
+	val, ok := lookupField(payload, s.path)
+	if !ok {
+		if s.required {
+			return ErrMissingField
+		}
+		return nil
+	}
+	if !s.kind.matches(val) {
+		return fmt.Errorf("%w: expected %s, got %T", ErrInvalidFieldType, s.kind, val)
+	}
+	return nil
+}
+
+// lookupField returns the value of the field of a payload at a dot-separated path.
+func lookupField(payload map[string]any, path string) (any, bool) {
+	var val any = payload
+	for _, key := range strings.Split(path, ".") {
+		obj, ok := val.(map[string]any)
+		if !ok {
+			return nil, false
+		}
+		if val, ok = obj[key]; !ok {
+			return nil, false
+		}
+	}
+	return val, true
+}
+
+// matches reports whether a value is of this kind. Integers are float64 in payloads decoded from JSON,
+// and int64 in payloads built from OTLP attributes.
+func (k fieldKind) matches(val any) bool {
+	switch k {
+	case kindString:
+		_, ok := val.(string)
+		return ok
+	case kindBool:
+		_, ok := val.(bool)
+		return ok
+	case kindObject:
+		_, ok := val.(map[string]any)
+		return ok
+	case kindInteger:
+		switch v := val.(type) {
+		case int, int64:
+			return true
+		case float64:
+			return v == math.Trunc(v) && !math.IsInf(v, 0)
+		}
+	}
+	return false
+}
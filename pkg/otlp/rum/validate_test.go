@@ -0,0 +1,192 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2025-present Datadog, Inc.
+
+package rum
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+)
+
+func validPayload(eventType string, fields map[string]any) map[string]any {
+	payload := map[string]any{
+		"type":        eventType,
+		"date":        int64(1700000000000),
+		"application": map[string]any{"id": "app"},
+		"session":     map[string]any{"id": "session", "type": "user"},
+		"view":        map[string]any{"id": "view", "url": "https://example.com"},
+	}
+	for k, v := range fields {
+		if obj, ok := v.(map[string]any); ok {
+			if existing, ok := payload[k].(map[string]any); ok {
+				for kk, vv := range obj {
+					existing[kk] = vv
+				}
+				continue
+			}
+		}
+		payload[k] = v
+	}
+	return payload
+}
+
+func TestValidatePayload(t *testing.T) {
+	tests := []struct {
+		name    string
+		payload map[string]any
+		// errs are the expected field errors, by field.
+		errs map[string]error
+	}{
+		{
+			name: "valid view",
+			payload: validPayload(ViewEventType, map[string]any{
+				"view": map[string]any{
+					"time_spent": int64(1000),
+					"action":     map[string]any{"count": int64(1)},
+					"error":      map[string]any{"count": int64(0)},
+					"resource":   map[string]any{"count": int64(2)},
+				},
+				"_dd": map[string]any{"document_version": int64(1), "format_version": int64(2)},
+			}),
+		},
+		{
+			name: "valid action",
+			payload: validPayload(ActionEventType, map[string]any{
+				"action": map[string]any{"type": "click", "target": map[string]any{"name": "button"}},
+			}),
+		},
+		{
+			name: "valid resource",
+			payload: validPayload(ResourceEventType, map[string]any{
+				"resource": map[string]any{"type": "xhr", "url": "https://example.com/api", "status_code": int64(200)},
+			}),
+		},
+		{
+			name: "valid long task",
+			payload: validPayload(LongTaskEventType, map[string]any{
+				"long_task": map[string]any{"duration": int64(50)},
+			}),
+		},
+		{
+			name: "valid error",
+			payload: validPayload(ErrorEventType, map[string]any{
+				"error": map[string]any{"message": "boom", "source": "source", "is_crash": false},
+			}),
+		},
+		{
+			name:    "empty payload",
+			payload: map[string]any{},
+			errs: map[string]error{
+				"type":           ErrMissingField,
+				"date":           ErrMissingField,
+				"application.id": ErrMissingField,
+				"session.id":     ErrMissingField,
+				"view.id":        ErrMissingField,
+			},
+		},
+		{
+			name: "missing event fields",
+			payload: validPayload(ResourceEventType, map[string]any{
+				"resource": map[string]any{"type": "xhr"},
+			}),
+			errs: map[string]error{
+				"resource.url": ErrMissingField,
+			},
+		},
+		{
+			name: "invalid types",
+			payload: validPayload(ErrorEventType, map[string]any{
+				"date":    "yesterday",
+				"service": int64(1),
+				"error":   map[string]any{"message": "boom", "source": "source", "is_crash": "no"},
+			}),
+			errs: map[string]error{
+				"date":           ErrInvalidFieldType,
+				"service":        ErrInvalidFieldType,
+				"error.is_crash": ErrInvalidFieldType,
+			},
+		},
+		{
+			name: "parent of field is not an object",
+			payload: validPayload(LongTaskEventType, map[string]any{
+				"long_task": "long",
+			}),
+			errs: map[string]error{
+				"long_task.duration": ErrMissingField,
+			},
+		},
+		{
+			name: "fractional integer",
+			payload: validPayload(LongTaskEventType, map[string]any{
+				"long_task": map[string]any{"duration": 1.5},
+			}),
+			errs: map[string]error{
+				"long_task.duration": ErrInvalidFieldType,
+			},
+		},
+		{
+			name:    "unknown event type",
+			payload: validPayload("unknown", nil),
+			errs: map[string]error{
+				"type": ErrUnknownEventType,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidatePayload(tt.payload)
+			if len(tt.errs) == 0 {
+				assert.NoError(t, err)
+				return
+			}
+			var payloadErr *PayloadError
+			require.ErrorAs(t, err, &payloadErr)
+			eventType, _ := tt.payload["type"].(string)
+			assert.Equal(t, eventType, payloadErr.EventType)
+			require.Len(t, payloadErr.Errors, len(tt.errs))
+			for _, fieldErr := range payloadErr.Errors {
+				require.Contains(t, tt.errs, fieldErr.Field)
+				assert.ErrorIs(t, fieldErr, tt.errs[fieldErr.Field])
+				assert.ErrorIs(t, err, tt.errs[fieldErr.Field])
+			}
+		})
+	}
+}
+
+func TestValidatePayloadFormats(t *testing.T) {
+	// Payloads decoded from JSON have float64 numbers.
+	var decoded map[string]any
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"type": "long_task",
+		"date": 1700000000000,
+		"application": {"id": "app"},
+		"session": {"id": "session"},
+		"view": {"id": "view"},
+		"long_task": {"duration": 50}
+	}`), &decoded))
+	assert.NoError(t, ValidatePayload(decoded))
+
+	// Payloads built from OTLP attributes have int64 numbers.
+	attrs := pcommon.NewMap()
+	attrs.PutStr("datadog.type", "resource")
+	attrs.PutInt("datadog.date", 1700000000000)
+	attrs.PutStr("datadog.application.id", "app")
+	attrs.PutStr("session.id", "session")
+	attrs.PutStr("datadog.view.id", "view")
+	attrs.PutStr("datadog.resource.type", "fetch")
+	attrs.PutStr("url.full", "https://example.com/api")
+	attrs.PutInt("http.response.status_code", 200)
+	assert.NoError(t, ValidatePayload(ConstructRumPayloadFromOTLP(attrs)))
+}
+
+func TestPayloadErrorMessage(t *testing.T) {
+	err := ValidatePayload(validPayload(ActionEventType, map[string]any{"date": "now"}))
+	assert.EqualError(t, err, `invalid RUM "action" event: date: invalid field type: expected integer, got string; action.type: missing required field`)
+}